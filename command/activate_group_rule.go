@@ -0,0 +1,94 @@
+package command
+
+import (
+	"net/http"
+)
+
+type ActivateGroupRuleCommand struct {
+	*Command
+}
+
+type ActivateGroupRuleCommandConfig struct {
+	RuleID, RuleName string
+}
+
+func (c *ActivateGroupRuleCommand) Synopsis() string {
+	return "Activate a group rule"
+}
+
+func (c *ActivateGroupRuleCommand) Help() string {
+	helpText := `
+Usage: okta-admin activate-group-rule [options]
+
+  Activates a group rule. Once active, Okta evaluates the rule
+  against existing users and assigns the ones matching its
+  expression to the rule's groups.
+  Either the ID or the name of the rule must be specified.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -id   ID of the group rule
+  -name Name of the group rule
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *ActivateGroupRuleCommand) ParseArgs(args []string) (*ActivateGroupRuleCommandConfig, error) {
+	var cfg ActivateGroupRuleCommandConfig
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.RuleID, "id", "", "")
+	flags.StringVar(&cfg.RuleName, "name", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+	if err := validateGroupRuleIdentifier(cfg.RuleID, cfg.RuleName); err != nil {
+		return &cfg, err
+	}
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+	)
+	return &cfg, err
+}
+
+func (c *ActivateGroupRuleCommand) Run(args []string) int {
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+
+	rule, err := findGroupRule(client, cfg.RuleID, cfg.RuleName)
+	if err != nil {
+		c.logError("Failed to fetch group rule", err)
+		return exitCode(err)
+	}
+
+	resp, err := client.ActivateGroupRule(rule.Id)
+	if err != nil {
+		c.logError("Failed to activate group rule", err)
+		return exitCode(err)
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		c.Logger.Printf("Failed to activate group rule: %s\n", resp.Status)
+		return ExitFailure
+	}
+
+	c.Logger.Printf("Successfully activated %s (ID: %s)\n", rule.Name, rule.Id)
+	return 0
+}
@@ -0,0 +1,86 @@
+package command
+
+import (
+	"github.com/okta/okta-sdk-golang/okta"
+	"testing"
+)
+
+func createTestActivateGroupRuleCommand(globalOptsHelpText string) *ActivateGroupRuleCommand {
+	return &ActivateGroupRuleCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_activate_group_rule_cmd"),
+	}
+}
+
+func TestActivateGroupRuleCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestActivateGroupRuleCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestActivateGroupRuleCommand_ParseArgs(t *testing.T) {
+	t.Run("with name", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestActivateGroupRuleCommand("")
+		args := []string{"-name", "Platform engineers"}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.RuleName != args[1] {
+			t.Errorf("Expected rule name to be %s, received %s", args[1], cfg.RuleName)
+		}
+	})
+
+	t.Run("with id", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestActivateGroupRuleCommand("")
+		args := []string{"-id", "0pr3f7zMZZHPgUoWO0g4"}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.RuleID != args[1] {
+			t.Errorf("Expected rule id to be %s, received %s", args[1], cfg.RuleID)
+		}
+	})
+
+	t.Run("without or with both identifiers", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := [][]string{
+			{},
+			{"-id", "0pr3f7zMZZHPgUoWO0g4", "-name", "Platform engineers"},
+		}
+		for _, tc := range testCases {
+			c := createTestActivateGroupRuleCommand("")
+			if _, err := c.ParseArgs(tc); err == nil {
+				t.Errorf("Expected args %v to be invalid", tc)
+			}
+		}
+	})
+}
+
+func TestActivateGroupRuleCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.rules = []*okta.GroupRule{{Id: "0pr1", Name: "Seekers", Status: "INACTIVE"}}
+	c, out := createTestCommandWithClient("test_activate_group_rule_cmd", client)
+	cmd := &ActivateGroupRuleCommand{Command: c}
+
+	if code := cmd.Run([]string{"-name", "Seekers"}); code != 0 {
+		t.Fatalf("Expected exit code 0, received %d: %s", code, out)
+	}
+	if client.rules[0].Status != groupRuleStatusActive {
+		t.Errorf("Expected rule to be activated, status is %s", client.rules[0].Status)
+	}
+	if out.String() != "Successfully activated Seekers (ID: 0pr1)\n" {
+		t.Errorf("Unexpected output %q", out)
+	}
+}
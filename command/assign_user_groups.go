@@ -1,7 +1,10 @@
 package command
 
 import (
-	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/okta/okta-sdk-golang/okta"
 	"net/http"
 )
 
@@ -10,8 +13,9 @@ type AssignUserGroupsCommand struct {
 }
 
 type AssignUserGroupsCommandConfig struct {
-	EmailID    string
-	GroupNames []string
+	User        string
+	GroupNames  []string
+	Parallelism int
 }
 
 func (c *AssignUserGroupsCommand) Synopsis() string {
@@ -25,11 +29,21 @@ Usage: okta-admin assign-groups [options]
   Adds an organization member to one or more groups.
   This command assumes that the specified group(s) already exist
   in the organization. If no groups are specified, it does nothing.
+  Groups are specified by name or ID and are searched for by name
+  one at a time, rather than by listing every group. A name
+  matches the group named exactly the same or, failing that, the
+  group whose name differs only in case. If several groups have
+  the name, their IDs are reported and one of them must be
+  specified by ID instead.
 {{.GlobalOptionsHelpText}}
 Options:
 
-  -email  Email ID of the user to deactivate
-  -groups Comma-separated list of groups to assign to the user
+  -user        ID, login or email ID of the user to assign groups
+               to. -email is accepted as an alias.
+  -groups      Comma-separated list of names or IDs of groups to
+               assign to the user
+  -parallelism Maximum number of groups to look up and assign
+               concurrently (Default: 5)
 `
 
 	return c.Command.prepareHelpMessage(
@@ -45,37 +59,32 @@ func (c *AssignUserGroupsCommand) ParseArgs(args []string) (*AssignUserGroupsCom
 	var groupNames string
 
 	flags := c.Meta.FlagSet
-	flags.StringVar(&cfg.EmailID, "email", "", "")
+	flags.StringVar(&cfg.User, "user", "", "")
+	flags.StringVar(&cfg.User, "email", "", "")
 	flags.StringVar(&groupNames, "groups", "", "")
+	flags.IntVar(&cfg.Parallelism, "parallelism", 5, "")
 
 	if err := flags.Parse(args); err != nil {
 		return &cfg, err
 	}
+	if cfg.Parallelism < 1 {
+		return &cfg, errors.New("parallelism must be at least 1")
+	}
 	cfg.GroupNames = c.parseListOfValues(groupNames, ParamListSep)
 
 	err := c.Command.validateParameters(
 		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
-		&parameter{Name: "email", Required: true, Value: cfg.EmailID, ValidationFunc: ValidateEmailID},
 		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+		&parameter{Name: "user", Required: true, Value: cfg.User, ValidationFunc: ValidateUserIdentifier},
 	)
 	return &cfg, err
 }
 
 func (c *AssignUserGroupsCommand) Run(args []string) int {
-	var (
-		user   oktaapi.ApiResponse
-		groups = OktaGroups{}
-
-		getUserCh        = make(chan *getUserResult)
-		listGroupsCh     = make(chan *listGroupsResult)
-		addUserToGroupCh = make(chan *addUserToGroupResult)
-	)
-	var neg numberOfExistingGroups
-
 	cfg, err := c.ParseArgs(args)
 	if err != nil {
-		c.Logger.Printf("Failed to parse arguments: %v\n", err)
-		return 1
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
 	}
 	if len(cfg.GroupNames) == 0 {
 		c.Logger.Println("No groups were specified, nothing to do")
@@ -84,58 +93,84 @@ func (c *AssignUserGroupsCommand) Run(args []string) int {
 
 	client, err := c.OktaClient()
 	if err != nil {
-		c.Logger.Printf("Failed to initialize Okta client: %v\n", err)
-		return 1
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
 	}
 
-	// Fetch User info and list of groups in the organization
-	go listGroups(client, nil, listGroupsCh)
-	go getUser(cfg.EmailID,
-		c.Meta.GlobalOptions.OrgUrl, c.Meta.GlobalOptions.ApiToken, getUserCh)
-
-	// The first issue encountered should stop further execution
-	for i := 0; i < 2; i++ {
-		select {
-		case u := <-getUserCh:
-			if u.Err != nil {
-				c.Logger.Printf("Failed to resolve user ID: %v\n", u.Err)
-				return 1
-			}
-			user = u.User
-		case g := <-listGroupsCh:
-			if g.Err != nil {
-				c.Logger.Printf("Failed to fetch list of groups: %v\n", g.Err)
-				return 1
-			}
-			if g.Resp.StatusCode != http.StatusOK {
-				c.Logger.Printf("Failed to fetch list of groups: %s\n", g.Resp.Status)
-				return 1
-			}
-			groups = g.Groups
+	ctx := c.operationContext()
+
+	// Resolve the user and each group concurrently
+	var uid string
+	groups := make([]*okta.Group, len(cfg.GroupNames))
+	errs := runPool(ctx, len(cfg.GroupNames)+1, cfg.Parallelism, func(ctx context.Context, i int) (err error) {
+		if i == 0 {
+			uid, err = resolveUser(client, cfg.User)
+		} else {
+			groups[i-1], err = resolveGroup(client, cfg.GroupNames[i-1])
 		}
+		return err
+	})
+	if errs[0] != nil {
+		c.logError("Failed to resolve user ID", errs[0])
+		return exitCode(errs[0])
 	}
 
-	neg = numberOfExistingGroups(len(cfg.GroupNames))
-	for _, n := range cfg.GroupNames {
-		gid := groups.GetID(n)
-		if gid == "" {
-			c.Logger.Printf("%s does not exist\n", n)
-			neg--
-			continue
+	// Groups which don't exist or couldn't be assigned are
+	// reported via the exit code once the others are assigned.
+	var (
+		gids, names []string
+		failures    []error
+	)
+	seen := map[string]bool{}
+	for i, n := range cfg.GroupNames {
+		var nfErr *notFoundError
+		switch err := errs[i+1]; {
+		case errors.As(err, &nfErr):
+			c.Logger.Println(err)
+			failures = append(failures, err)
+		case err != nil && err == ctx.Err():
+			failures = append(failures, c.operationErr())
+		case err != nil:
+			c.logError(fmt.Sprintf("Failed to find group %s", n), err)
+			failures = append(failures, err)
+		case !seen[groups[i].Id]:
+			// A group may be specified both by name and ID
+			seen[groups[i].Id] = true
+			gids = append(gids, groups[i].Id)
+			names = append(names, groups[i].Profile.Name)
 		}
-		go addUserToGroup(client, user["id"].(string), gid, n, addUserToGroupCh)
+	}
+	if err := ctx.Err(); err != nil && len(gids) == 0 {
+		c.Logger.Printf("User was not added to any group: %v\n", c.operationErr())
+		return fanOutExitCode(len(cfg.GroupNames), failures)
 	}
 
-	for i := 0; i < int(neg); i++ {
-		added := <-addUserToGroupCh
-		if added.Err != nil {
-			c.Logger.Printf("Failed to add user to %s: %v\n", added.GroupName, added.Err)
-		} else if added.Resp.StatusCode != http.StatusNoContent {
-			c.Logger.Printf("Failed to add user to %s: %s\n", added.GroupName, added.Resp.Status)
-		} else {
-			c.Logger.Printf("Added to %s\n", added.GroupName)
+	errs = runPool(ctx, len(gids), cfg.Parallelism, func(ctx context.Context, i int) error {
+		resp, err := client.AddUserToGroup(gids[i], uid)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusNoContent {
+			return errors.New(resp.Status)
+		}
+		return nil
+	})
+	skipped := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			c.Logger.Printf("Added to %s\n", names[i])
+		case err == ctx.Err():
+			skipped++
+			failures = append(failures, c.operationErr())
+		default:
+			c.logError(fmt.Sprintf("Failed to add user to %s", names[i]), err)
+			failures = append(failures, err)
 		}
 	}
 
-	return 0
+	if skipped > 0 {
+		c.Logger.Printf("User was not added to %d group(s): %v\n", skipped, c.operationErr())
+	}
+	return fanOutExitCode(len(cfg.GroupNames), failures)
 }
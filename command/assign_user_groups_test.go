@@ -1,6 +1,8 @@
 package command
 
 import (
+	"github.com/okta/okta-sdk-golang/okta"
+	"strings"
 	"testing"
 )
 
@@ -30,8 +32,8 @@ func TestAssignUserGroupsCommand_ParseArgs(t *testing.T) {
 			t.Fatalf("Failed to parse arguments: %v", err)
 		}
 
-		if cfg.EmailID != args[1] {
-			t.Errorf("Expected email id to be %s, received %s", args[1], cfg.EmailID)
+		if cfg.User != args[1] {
+			t.Errorf("Expected user to be %s, received %s", args[1], cfg.User)
 		}
 		if len(cfg.GroupNames) != 0 {
 			t.Errorf("Expected group names slice to be empty, received %v", cfg.GroupNames)
@@ -61,4 +63,136 @@ func TestAssignUserGroupsCommand_ParseArgs(t *testing.T) {
 			}
 		}
 	})
+	t.Run("with parallelism", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestAssignUserGroupsCommand("")
+		args := []string{
+			"-email", "harry.potter@hogwarts.co.uk",
+			"-groups", "Tech",
+			"-parallelism", "2",
+		}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+		if cfg.Parallelism != 2 {
+			t.Errorf("Expected parallelism to be 2, received %d", cfg.Parallelism)
+		}
+
+		c = createTestAssignUserGroupsCommand("")
+		if _, err := c.ParseArgs([]string{"-email", "harry.potter@hogwarts.co.uk", "-parallelism", "0"}); err == nil {
+			t.Errorf("Expected parsing to fail with parallelism 0")
+		}
+	})
+}
+
+func TestAssignUserGroupsCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.users = []*okta.User{fakeUser("00u1", "harry.potter@hogwarts.co.uk", "ACTIVE")}
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor"), fakeGroup("00g2", "Quidditch")}
+	c, out := createTestCommandWithClient("test_assign_user_groups_cmd", client)
+	cmd := &AssignUserGroupsCommand{Command: c}
+
+	if code := cmd.Run([]string{"-email", "harry.potter@hogwarts.co.uk", "-groups", "Quidditch,Slytherin,Gryffindor"}); code != ExitPartialFailure {
+		t.Fatalf("Expected exit code %d since a group doesn't exist, received %d: %s", ExitPartialFailure, code, out)
+	}
+	expected := "Slytherin does not exist\nAdded to Quidditch\nAdded to Gryffindor\n"
+	if out.String() != expected {
+		t.Errorf("Expected output %q, received %q", expected, out)
+	}
+	for _, gid := range []string{"00g1", "00g2"} {
+		if !testEq(client.members[gid], []string{"00u1"}) {
+			t.Errorf("Expected user to be a member of %s, members are %v", gid, client.members[gid])
+		}
+	}
+
+	c, out = createTestCommandWithClient("test_assign_user_groups_cmd", client)
+	cmd = &AssignUserGroupsCommand{Command: c}
+	if code := cmd.Run([]string{"-email", "harry.potter@hogwarts.co.uk", "-groups", "Slytherin"}); code != ExitNotFound {
+		t.Errorf("Expected exit code %d since the group doesn't exist, received %d: %s", ExitNotFound, code, out)
+	}
+
+	c, out = createTestCommandWithClient("test_assign_user_groups_cmd", client)
+	cmd = &AssignUserGroupsCommand{Command: c}
+	if code := cmd.Run([]string{"-email", "draco.malfoy@hogwarts.co.uk", "-groups", "Gryffindor"}); code != ExitNotFound {
+		t.Errorf("Expected exit code %d since the user doesn't exist, received %d: %s", ExitNotFound, code, out)
+	}
+}
+
+func TestAssignUserGroupsCommand_RunByID(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.users = []*okta.User{fakeUser("00u1", "harry.potter@hogwarts.co.uk", "ACTIVE")}
+	client.groups = []*okta.Group{
+		fakeGroup("00gabcdefghijklmnop1", "Dumbledore's Army"),
+		fakeGroup("00gabcdefghijklmnop2", "Dumbledore's Army"),
+		fakeGroup("00gabcdefghijklmnop3", "Quidditch"),
+	}
+	c, out := createTestCommandWithClient("test_assign_user_groups_cmd", client)
+	cmd := &AssignUserGroupsCommand{Command: c}
+
+	if code := cmd.Run([]string{"-user", "00u1", "-groups", "Dumbledore's Army,00gabcdefghijklmnop2,quidditch,00gabcdefghijklmnop3"}); code != ExitPartialFailure {
+		t.Fatalf("Expected exit code %d since a group name is ambiguous, received %d: %s", ExitPartialFailure, code, out)
+	}
+	for _, expected := range []string{
+		"Failed to find group Dumbledore's Army: 2 groups are named Dumbledore's Army (00gabcdefghijklmnop1, 00gabcdefghijklmnop2)",
+		"Added to Dumbledore's Army\n",
+		"Added to Quidditch\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected output to contain %q, received:\n%s", expected, out)
+		}
+	}
+	// The group specified by both name and ID is added once
+	if n := strings.Count(out.String(), "Added to"); n != 2 {
+		t.Errorf("Expected user to be added to 2 groups, received:\n%s", out)
+	}
+	if len(client.members["00gabcdefghijklmnop1"]) != 0 || !testEq(client.members["00gabcdefghijklmnop2"], []string{"00u1"}) {
+		t.Errorf("Expected user to be added only to the group specified by ID, members are %v", client.members)
+	}
+}
+
+func TestAssignUserGroupsCommand_RunCached(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.users = []*okta.User{fakeUser("00u1", "harry.potter@hogwarts.co.uk", "ACTIVE")}
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor")}
+	run := func(groups string) {
+		c, out := createTestCommandWithClient("test_assign_user_groups_cmd", client)
+		c.Meta.GlobalOptions.OrgUrl = "https://assign-cached.okta.com/"
+		c.Meta.GlobalOptions.Cache = true
+		cmd := &AssignUserGroupsCommand{Command: c}
+		// Resolved one at a time, so that the calls are made in order
+		if code := cmd.Run([]string{"-user", "harry.potter@hogwarts.co.uk", "-groups", groups, "-parallelism", "1"}); code != ExitOK {
+			t.Fatalf("Expected exit code 0, received %d: %s", code, out)
+		}
+	}
+
+	run("Gryffindor")
+	run("Gryffindor")
+	expected := []string{
+		"GetUser harry.potter@hogwarts.co.uk", "ListGroups Gryffindor", "ListUserGroups 00u1", "AddUserToGroup 00g1 00u1",
+		"ListUserGroups 00u1", "AddUserToGroup 00g1 00u1",
+	}
+	if !testEq(client.calls, expected) {
+		t.Errorf("Expected the user and groups to be fetched once, calls %q, received %q", expected, client.calls)
+	}
+
+	// Groups missing from the cached ones are fetched again
+	client.groups = append(client.groups, fakeGroup("00g2", "Quidditch"))
+	client.calls = nil
+	run("Quidditch")
+	expected = []string{"ListGroups Quidditch", "ListUserGroups 00u1", "AddUserToGroup 00g2 00u1"}
+	if !testEq(client.calls, expected) {
+		t.Errorf("Expected calls %q, received %q", expected, client.calls)
+	}
+	if !testEq(client.members["00g2"], []string{"00u1"}) {
+		t.Errorf("Expected user to be added to the new group, calls made: %v", client.calls)
+	}
 }
@@ -0,0 +1,503 @@
+package command
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditLogEnv is the environment variable which overrides the path
+// of the audit log.
+const AuditLogEnv = "OKTA_ADMIN_AUDIT_LOG"
+
+const (
+	// auditLogFile is the name of the audit log in the okta-admin
+	// directory of the user's configuration directory.
+	auditLogFile = "audit.jsonl"
+
+	// auditWebhookTimeout is the maximum time a webhook may take to
+	// receive an audit record.
+	auditWebhookTimeout = 10 * time.Second
+
+	// oktaRequestIdHeader is the header of Okta's responses which
+	// contains the ID of the request, by which Okta support and the
+	// System Log can look it up.
+	oktaRequestIdHeader = "X-Okta-Request-Id"
+)
+
+// Results of the commands recorded in the audit log.
+const (
+	auditResultSuccess        = "success"
+	auditResultPartialFailure = "partial-failure"
+	auditResultFailure        = "failure"
+)
+
+var (
+	// auditedCommands are the commands which change the organization,
+	// whose runs are recorded in the audit log.
+	auditedCommands = map[string]bool{
+		"create-user":           true,
+		"deactivate-user":       true,
+		"reset-user-password":   true,
+		"reset-user-mfa":        true,
+		"assign-groups":         true,
+		"create-group-rule":     true,
+		"activate-group-rule":   true,
+		"deactivate-group-rule": true,
+		"delete-group-rule":     true,
+		"restore-policies":      true,
+		"end-user-sessions":     true,
+		"end-session":           true,
+		"undo":                  true,
+	}
+
+	// secretFlags are the flags whose values are masked in the
+	// arguments recorded in the audit log.
+	secretFlags = map[string]bool{"-api-token": true}
+)
+
+// auditRecord is a line of the audit log, describing a run of an
+// audited command.
+type auditRecord struct {
+	Time time.Time `json:"time"`
+	// User is the name of the user of the operating system who ran
+	// the command, and Host the name of their machine
+	User string `json:"user"`
+	Host string `json:"host"`
+
+	OrgUrl  string   `json:"orgUrl"`
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+
+	// Targets are the IDs of the users, groups, rules, policies and
+	// sessions the command changed or tried to
+	Targets    []string `json:"targets"`
+	Result     string   `json:"result"`
+	ExitStatus int      `json:"exitStatus"`
+	// RequestIDs are the IDs of the requests the command made to
+	// change the organization
+	RequestIDs []string `json:"requestIds"`
+}
+
+// auditLogPath returns the path of the audit log, which is
+// specified by the -audit-log global option or the
+// OKTA_ADMIN_AUDIT_LOG environment variable, or is audit.jsonl in
+// the okta-admin directory of the user's configuration directory,
+// eg- ~/.config/okta-admin on Linux.
+func auditLogPath(opts *Config) (string, error) {
+	if opts.AuditLog != "" {
+		return opts.AuditLog, nil
+	}
+	if path := os.Getenv(AuditLogEnv); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "okta-admin", auditLogFile), nil
+}
+
+// maskSecrets returns a copy of the arguments in which the values
+// of secret flags are masked.
+func maskSecrets(args []string) []string {
+	res := make([]string, len(args), len(args))
+	copy(res, args)
+	for i := 0; i < len(res); i++ {
+		name := "-" + strings.TrimLeft(res[i], "-")
+		if parts := strings.SplitN(name, "=", 2); len(parts) == 2 && secretFlags[parts[0]] {
+			res[i] = res[i][:strings.Index(res[i], "=")+1] + "********"
+		} else if secretFlags[name] && i+1 < len(res) {
+			res[i+1] = "********"
+			i++
+		}
+	}
+	return res
+}
+
+// auditResult describes the exit status of a command.
+func auditResult(status int) string {
+	switch status {
+	case ExitOK:
+		return auditResultSuccess
+	case ExitPartialFailure:
+		return auditResultPartialFailure
+	default:
+		return auditResultFailure
+	}
+}
+
+// auditTrail collects the targets and request IDs of the changes a
+// command makes via an auditingClient.
+type auditTrail struct {
+	mu         sync.Mutex
+	targets    []string
+	requestIDs []string
+}
+
+// add records the IDs of the targets of a change and the ID of the
+// request which made it, if the response contains one.
+func (t *auditTrail) add(resp *http.Response, targets ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, target := range targets {
+		if target != "" && !containsString(t.targets, target) {
+			t.targets = append(t.targets, target)
+		}
+	}
+	if resp != nil {
+		if id := resp.Header.Get(oktaRequestIdHeader); id != "" {
+			t.requestIDs = append(t.requestIDs, id)
+		}
+	}
+}
+
+// reset returns the targets and request IDs recorded so far and
+// forgets them.
+func (t *auditTrail) reset() (targets, requestIDs []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	targets, requestIDs = t.targets, t.requestIDs
+	t.targets, t.requestIDs = nil, nil
+	return append([]string{}, targets...), append([]string{}, requestIDs...)
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// auditingClient records the targets and request IDs of the changes
+// made via the client in the trail.
+type auditingClient struct {
+	oktaapi.Client
+	trail *auditTrail
+}
+
+// httpResponse returns the HTTP response of the SDK's response,
+// which is nil if the request failed.
+func httpResponse(resp *okta.Response) *http.Response {
+	if resp == nil {
+		return nil
+	}
+	return resp.Response
+}
+
+func (a *auditingClient) CreateUser(user okta.User, qp *query.Params) (*okta.User, *okta.Response, error) {
+	res, resp, err := a.Client.CreateUser(user, qp)
+	if res != nil {
+		a.trail.add(httpResponse(resp), res.Id)
+	} else {
+		a.trail.add(httpResponse(resp))
+	}
+	return res, resp, err
+}
+
+func (a *auditingClient) DeactivateUser(userID string, qp *query.Params) (*okta.Response, error) {
+	resp, err := a.Client.DeactivateUser(userID, qp)
+	a.trail.add(httpResponse(resp), userID)
+	return resp, err
+}
+
+func (a *auditingClient) ActivateUser(userID string, qp *query.Params) (*okta.UserActivationToken, *okta.Response, error) {
+	res, resp, err := a.Client.ActivateUser(userID, qp)
+	a.trail.add(httpResponse(resp), userID)
+	return res, resp, err
+}
+
+func (a *auditingClient) ResetPassword(userID string, qp *query.Params) (*okta.ResetPasswordToken, *okta.Response, error) {
+	res, resp, err := a.Client.ResetPassword(userID, qp)
+	a.trail.add(httpResponse(resp), userID)
+	return res, resp, err
+}
+
+func (a *auditingClient) ResetAllFactors(userID string) (*okta.Response, error) {
+	resp, err := a.Client.ResetAllFactors(userID)
+	a.trail.add(httpResponse(resp), userID)
+	return resp, err
+}
+
+func (a *auditingClient) AddUserToGroup(groupID, userID string) (*okta.Response, error) {
+	resp, err := a.Client.AddUserToGroup(groupID, userID)
+	a.trail.add(httpResponse(resp), userID, groupID)
+	return resp, err
+}
+
+func (a *auditingClient) RemoveUserFromGroup(groupID, userID string) (*okta.Response, error) {
+	resp, err := a.Client.RemoveUserFromGroup(groupID, userID)
+	a.trail.add(httpResponse(resp), userID, groupID)
+	return resp, err
+}
+
+func (a *auditingClient) CreateGroupRule(rule okta.GroupRule) (*okta.GroupRule, *okta.Response, error) {
+	res, resp, err := a.Client.CreateGroupRule(rule)
+	if res != nil {
+		a.trail.add(httpResponse(resp), res.Id)
+	} else {
+		a.trail.add(httpResponse(resp))
+	}
+	return res, resp, err
+}
+
+func (a *auditingClient) ActivateGroupRule(ruleID string) (*okta.Response, error) {
+	resp, err := a.Client.ActivateGroupRule(ruleID)
+	a.trail.add(httpResponse(resp), ruleID)
+	return resp, err
+}
+
+func (a *auditingClient) DeactivateGroupRule(ruleID string) (*okta.Response, error) {
+	resp, err := a.Client.DeactivateGroupRule(ruleID)
+	a.trail.add(httpResponse(resp), ruleID)
+	return resp, err
+}
+
+func (a *auditingClient) DeleteGroupRule(ruleID string, qp *query.Params) (*okta.Response, error) {
+	resp, err := a.Client.DeleteGroupRule(ruleID, qp)
+	a.trail.add(httpResponse(resp), ruleID)
+	return resp, err
+}
+
+func (a *auditingClient) CreatePolicy(body interface{}, activate bool) (*oktaapi.Policy, *http.Response, error) {
+	res, resp, err := a.Client.CreatePolicy(body, activate)
+	if res != nil {
+		a.trail.add(resp, res.Id)
+	} else {
+		a.trail.add(resp)
+	}
+	return res, resp, err
+}
+
+func (a *auditingClient) UpdatePolicy(policyID string, body interface{}) (*http.Response, error) {
+	resp, err := a.Client.UpdatePolicy(policyID, body)
+	a.trail.add(resp, policyID)
+	return resp, err
+}
+
+func (a *auditingClient) ActivatePolicy(policyID string) (*okta.Response, error) {
+	resp, err := a.Client.ActivatePolicy(policyID)
+	a.trail.add(httpResponse(resp), policyID)
+	return resp, err
+}
+
+func (a *auditingClient) DeactivatePolicy(policyID string) (*okta.Response, error) {
+	resp, err := a.Client.DeactivatePolicy(policyID)
+	a.trail.add(httpResponse(resp), policyID)
+	return resp, err
+}
+
+func (a *auditingClient) CreatePolicyRule(policyID string, body interface{}, activate bool) (*http.Response, error) {
+	resp, err := a.Client.CreatePolicyRule(policyID, body, activate)
+	a.trail.add(resp, policyID)
+	return resp, err
+}
+
+func (a *auditingClient) UpdatePolicyRule(policyID, ruleID string, body interface{}) (*http.Response, error) {
+	resp, err := a.Client.UpdatePolicyRule(policyID, ruleID, body)
+	a.trail.add(resp, policyID, ruleID)
+	return resp, err
+}
+
+func (a *auditingClient) ActivatePolicyRule(policyID, ruleID string) (*okta.Response, error) {
+	resp, err := a.Client.ActivatePolicyRule(policyID, ruleID)
+	a.trail.add(httpResponse(resp), policyID, ruleID)
+	return resp, err
+}
+
+func (a *auditingClient) DeactivatePolicyRule(policyID, ruleID string) (*okta.Response, error) {
+	resp, err := a.Client.DeactivatePolicyRule(policyID, ruleID)
+	a.trail.add(httpResponse(resp), policyID, ruleID)
+	return resp, err
+}
+
+func (a *auditingClient) EndSession(sessionID string) (*okta.Response, error) {
+	resp, err := a.Client.EndSession(sessionID)
+	a.trail.add(httpResponse(resp), sessionID)
+	return resp, err
+}
+
+func (a *auditingClient) EndAllUserSessions(userID string, qp *query.Params) (*okta.Response, error) {
+	resp, err := a.Client.EndAllUserSessions(userID, qp)
+	a.trail.add(httpResponse(resp), userID)
+	return resp, err
+}
+
+// RecordAudit appends a record of the run of the command with the
+// name and arguments, which exited with the status, to the audit
+// log, along with the targets and request IDs of the changes it
+// made, and forwards it to syslog and the webhook specified by the
+// global options. Only commands which change the organization are
+// recorded, unless they failed to parse their arguments. Failing to
+// record the run doesn't change its exit status, but is reported.
+func (c *Command) RecordAudit(name string, args []string, status int) {
+	targets, requestIDs := c.audit.reset()
+	if !auditedCommands[name] || status == ExitUsage {
+		return
+	}
+
+	opts := c.Meta.GlobalOptions
+	rec := &auditRecord{
+		Time:       time.Now().UTC(),
+		User:       osUserName(),
+		OrgUrl:     opts.OrgUrl,
+		Command:    name,
+		Args:       maskSecrets(args),
+		Targets:    targets,
+		Result:     auditResult(status),
+		ExitStatus: status,
+		RequestIDs: requestIDs,
+	}
+	rec.Host, _ = os.Hostname()
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		c.logError("Failed to record the command in the audit log", err)
+		return
+	}
+	if err := appendAuditRecord(opts, line); err != nil {
+		c.logError("Failed to record the command in the audit log", err)
+	}
+	if opts.AuditSyslog != "" {
+		if err := sendAuditSyslog(opts.AuditSyslog, line); err != nil {
+			c.logError("Failed to forward the audit record to syslog", err)
+		}
+	}
+	if opts.AuditWebhook != "" {
+		if err := sendAuditWebhook(opts.AuditWebhook, line); err != nil {
+			c.logError("Failed to forward the audit record to the webhook", err)
+		}
+	}
+}
+
+// osUserName returns the name of the user of the operating system
+// running okta-admin.
+func osUserName() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return Coalesce(os.Getenv("USER"), os.Getenv("USERNAME"))
+}
+
+// appendAuditRecord appends the line to the audit log.
+func appendAuditRecord(opts *Config, line []byte) error {
+	path, err := auditLogPath(opts)
+	if err != nil {
+		return err
+	}
+	return appendLine(path, line)
+}
+
+// readAuditRecords calls fn with each record of the audit log, in
+// the order they were recorded, and returns the number of lines
+// which aren't valid records. A missing log contains no records.
+func readAuditRecords(opts *Config, fn func(rec *auditRecord)) (int, error) {
+	path, err := auditLogPath(opts)
+	if err != nil {
+		return 0, err
+	}
+	return readLines(path, func(line []byte) bool {
+		var rec auditRecord
+		if json.Unmarshal(line, &rec) != nil || rec.Command == "" {
+			return false
+		}
+		fn(&rec)
+		return true
+	})
+}
+
+// appendLine appends the line to the file at path, creating it and
+// the directories it's in if needed. The file is only readable by
+// the user.
+func appendLine(path string, line []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	// The line is written with a single call, so that lines written
+	// by commands run concurrently aren't interleaved
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readLines calls fn with each non-empty line of the file at path
+// and returns the number of lines fn rejected by returning false.
+// A missing file contains no lines.
+func readLines(path string, fn func(line []byte) bool) (int, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	invalid := 0
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 && !fn(line) {
+			invalid++
+		}
+		if err == io.EOF {
+			return invalid, nil
+		}
+		if err != nil {
+			return invalid, err
+		}
+	}
+}
+
+// parseAuditSyslog returns the network and address of the syslog
+// daemon specified by the -audit-syslog global option, which is
+// either "local", for which both are empty, or a URL like
+// udp://host:514 or tcp://host:514.
+func parseAuditSyslog(value string) (network, addr string, err error) {
+	if value == "local" {
+		return "", "", nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "udp" && u.Scheme != "tcp") || u.Host == "" {
+		return "", "", errors.New(fmt.Sprintf("%s is invalid, must be local or a URL like udp://host:514", value))
+	}
+	return u.Scheme, u.Host, nil
+}
+
+// sendAuditWebhook posts the audit record to the webhook's URL.
+func sendAuditWebhook(webhookUrl string, line []byte) error {
+	client := &http.Client{Timeout: auditWebhookTimeout}
+	resp, err := client.Post(webhookUrl, "application/json", bytes.NewReader(line))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.New(resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package command
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	reportFormatCSV  = "csv"
+	reportFormatJSON = "json"
+)
+
+type AuditAdminsCommand struct {
+	*Command
+}
+
+type AuditAdminsCommandConfig struct {
+	Format      string
+	OutputFile  string
+	Parallelism int
+}
+
+func (c *AuditAdminsCommand) Synopsis() string {
+	return "Report users holding administrator roles"
+}
+
+func (c *AuditAdminsCommand) Help() string {
+	helpText := `
+Usage: okta-admin audit-admins [options]
+
+  Audits administrator privileges across the organization.
+  Every user (including suspended and deprovisioned ones) is
+  checked for assigned administrator roles. The report lists
+  each admin, their roles and the groups those roles are scoped
+  to. A role without groups applies to the whole organization.
+
+  Admins are flagged in the report if they are super admins
+  (SUPER_ADMIN), haven't enrolled any MFA factor (NO_MFA), or
+  are suspended or deprovisioned (SUSPENDED, DEPROVISIONED).
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -format      Format of the report, either csv or json (Default: csv)
+  -out         File to write the report to. If unspecified, the
+               report is written to standard output.
+  -parallelism Maximum number of users to audit concurrently (Default: 5)
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *AuditAdminsCommand) ParseArgs(args []string) (*AuditAdminsCommandConfig, error) {
+	var cfg AuditAdminsCommandConfig
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.Format, "format", reportFormatCSV, "")
+	flags.StringVar(&cfg.OutputFile, "out", "", "")
+	flags.IntVar(&cfg.Parallelism, "parallelism", 5, "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+	if cfg.Parallelism < 1 {
+		return &cfg, errors.New("parallelism must be at least 1")
+	}
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "format", Required: true, Value: cfg.Format, ValidationFunc: ValidateOneOf(reportFormatCSV, reportFormatJSON)},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+	)
+	return &cfg, err
+}
+
+func (c *AuditAdminsCommand) Run(args []string) int {
+	var (
+		records  []*adminAuditRecord
+		failures []error
+	)
+
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+
+	// Deprovisioned users must be requested explicitly
+	users, err := listAllUsers(client, nil)
+	if err != nil {
+		c.logError("Failed to fetch list of users", err)
+		return exitCode(err)
+	}
+	deprovisioned, err := listAllUsers(client,
+		query.NewQueryParams(query.WithFilter(fmt.Sprintf("status eq \"%s\"", userStatusDeprovisioned))))
+	if err != nil {
+		c.logError("Failed to fetch list of deprovisioned users", err)
+		return exitCode(err)
+	}
+	users = append(users, deprovisioned...)
+
+	ctx := c.operationContext()
+
+	// Limit the number of users being audited at any time
+	audited := make([]*adminAuditRecord, len(users), len(users))
+	errs := runPool(ctx, len(users), cfg.Parallelism, func(ctx context.Context, i int) (err error) {
+		audited[i], err = createAdminAuditRecord(client, users[i])
+		return err
+	})
+	skipped := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			if audited[i] != nil {
+				records = append(records, audited[i])
+			}
+		case err == ctx.Err():
+			skipped++
+			failures = append(failures, c.operationErr())
+		default:
+			c.logError(fmt.Sprintf("Failed to audit user %s", users[i].Id), err)
+			failures = append(failures, err)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Login < records[j].Login
+	})
+
+	out := c.Logger.Writer()
+	if cfg.OutputFile != "" {
+		f, err := os.Create(cfg.OutputFile)
+		if err != nil {
+			c.logError("Failed to create report file", err)
+			return exitCode(err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if cfg.Format == reportFormatJSON {
+		err = writeAdminAuditJSON(out, records)
+	} else {
+		err = writeAdminAuditCSV(out, records)
+	}
+	if err != nil {
+		c.logError("Failed to write report", err)
+		return exitCode(err)
+	}
+
+	if skipped > 0 {
+		c.Logger.Printf("Report is incomplete, %d user(s) weren't audited: %v\n", skipped, c.operationErr())
+	}
+	if len(failures) > skipped {
+		c.Logger.Printf("Report is incomplete, failed to audit %d user(s)\n", len(failures)-skipped)
+	}
+	return fanOutExitCode(len(users), failures)
+}
+
+// writeAdminAuditCSV writes the audit report as CSV with one
+// row for every role assigned to an admin.
+func writeAdminAuditCSV(w io.Writer, records []*adminAuditRecord) error {
+	cw := csv.NewWriter(w)
+	header := []string{
+		"user_id", "login", "email", "status", "role_type",
+		"role_label", "scoped_groups", "mfa_enrolled", "flags",
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		for _, role := range r.Roles {
+			row := []string{
+				r.UserID, r.Login, r.Email, r.Status, role.Type, role.Label,
+				strings.Join(role.Groups, ";"), strconv.FormatBool(r.MfaEnrolled), strings.Join(r.Flags, ";"),
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeAdminAuditJSON writes the audit report as a JSON array
+// with one object per admin.
+func writeAdminAuditJSON(w io.Writer, records []*adminAuditRecord) error {
+	if records == nil {
+		records = []*adminAuditRecord{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
@@ -0,0 +1,139 @@
+package command
+
+import (
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta"
+	"strings"
+	"testing"
+)
+
+func createTestAuditAdminsCommand(globalOptsHelpText string) *AuditAdminsCommand {
+	return &AuditAdminsCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_audit_admins_cmd"),
+	}
+}
+
+func TestAuditAdminsCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestAuditAdminsCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestAuditAdminsCommand_ParseArgs(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestAuditAdminsCommand("")
+		cfg, err := c.ParseArgs([]string{})
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.Format != reportFormatCSV {
+			t.Errorf("Expected format to be %s, received %s", reportFormatCSV, cfg.Format)
+		}
+		if cfg.OutputFile != "" {
+			t.Errorf("Expected output file to be empty, received %s", cfg.OutputFile)
+		}
+		if cfg.Parallelism != 5 {
+			t.Errorf("Expected parallelism to be 5, received %d", cfg.Parallelism)
+		}
+	})
+
+	t.Run("with options", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestAuditAdminsCommand("")
+		args := []string{
+			"-format", "json",
+			"-out", "admins.json",
+			"-parallelism", "12",
+		}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.Format != args[1] {
+			t.Errorf("Expected format to be %s, received %s", args[1], cfg.Format)
+		}
+		if cfg.OutputFile != args[3] {
+			t.Errorf("Expected output file to be %s, received %s", args[3], cfg.OutputFile)
+		}
+		if cfg.Parallelism != 12 {
+			t.Errorf("Expected parallelism to be 12, received %d", cfg.Parallelism)
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := [][]string{
+			{"-format", "yaml"},
+			{"-parallelism", "0"},
+		}
+		for _, tc := range testCases {
+			c := createTestAuditAdminsCommand("")
+			if _, err := c.ParseArgs(tc); err == nil {
+				t.Errorf("Expected args %v to be invalid", tc)
+			}
+		}
+	})
+}
+
+func TestWriteAdminAuditCSV(t *testing.T) {
+	t.Parallel()
+
+	records := []*adminAuditRecord{
+		{
+			UserID: "00u1", Login: "albus@hogwarts.co.uk", Email: "albus@hogwarts.co.uk",
+			Status: "ACTIVE", MfaEnrolled: true, Flags: []string{"SUPER_ADMIN"},
+			Roles: []adminRole{{Type: "SUPER_ADMIN", Label: "Super Organization Administrator", Groups: []string{}}},
+		},
+		{
+			UserID: "00u2", Login: "minerva@hogwarts.co.uk", Email: "minerva@hogwarts.co.uk",
+			Status: "SUSPENDED", Flags: []string{"NO_MFA", "SUSPENDED"},
+			Roles: []adminRole{{Type: "USER_ADMIN", Label: "User Administrator", Groups: []string{"Gryffindor", "Staff"}}},
+		},
+	}
+	expected := `user_id,login,email,status,role_type,role_label,scoped_groups,mfa_enrolled,flags
+00u1,albus@hogwarts.co.uk,albus@hogwarts.co.uk,ACTIVE,SUPER_ADMIN,Super Organization Administrator,,true,SUPER_ADMIN
+00u2,minerva@hogwarts.co.uk,minerva@hogwarts.co.uk,SUSPENDED,USER_ADMIN,User Administrator,Gryffindor;Staff,false,NO_MFA;SUSPENDED
+`
+
+	builder := &strings.Builder{}
+	if err := writeAdminAuditCSV(builder, records); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+	if builder.String() != expected {
+		t.Errorf("Expected report:\n%s\nreceived:\n%s", expected, builder.String())
+	}
+}
+
+func TestAuditAdminsCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.users = []*okta.User{
+		fakeUser("00u1", "albus.dumbledore@hogwarts.co.uk", "ACTIVE"),
+		fakeUser("00u2", "harry.potter@hogwarts.co.uk", "ACTIVE"),
+		fakeUser("00u3", "gilderoy.lockhart@hogwarts.co.uk", "DEPROVISIONED"),
+	}
+	client.roles["00u1"] = []*okta.Role{{Id: "ra1", Type: roleTypeSuperAdmin, Label: "Super Administrator"}}
+	client.roles["00u3"] = []*okta.Role{{Id: "ra3", Type: "READ_ONLY_ADMIN", Label: "Read Only Administrator"}}
+	client.factors["00u1"] = []*oktaapi.Factor{{Status: factorStatusActive}}
+	c, out := createTestCommandWithClient("test_audit_admins_cmd", client)
+	cmd := &AuditAdminsCommand{Command: c}
+
+	if code := cmd.Run([]string{"-parallelism", "2"}); code != 0 {
+		t.Fatalf("Expected exit code 0, received %d: %s", code, out)
+	}
+	expected := `user_id,login,email,status,role_type,role_label,scoped_groups,mfa_enrolled,flags
+00u1,albus.dumbledore@hogwarts.co.uk,albus.dumbledore@hogwarts.co.uk,ACTIVE,SUPER_ADMIN,Super Administrator,,true,SUPER_ADMIN
+00u3,gilderoy.lockhart@hogwarts.co.uk,gilderoy.lockhart@hogwarts.co.uk,DEPROVISIONED,READ_ONLY_ADMIN,Read Only Administrator,,false,NO_MFA;DEPROVISIONED
+`
+	if out.String() != expected {
+		t.Errorf("Expected report:\n%s\nreceived:\n%s", expected, out)
+	}
+}
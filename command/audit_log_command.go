@@ -0,0 +1,40 @@
+package command
+
+import "github.com/mitchellh/cli"
+
+// AuditLogCommand groups the commands querying the audit log. It
+// only prints its help message, which lists them.
+type AuditLogCommand struct {
+	*Command
+}
+
+func (c *AuditLogCommand) Synopsis() string {
+	return "Query the log of changes made by okta-admin"
+}
+
+func (c *AuditLogCommand) Help() string {
+	helpText := `
+Usage: okta-admin audit-log <subcommand> [options]
+
+  Queries the audit log, to which every run of a command which
+  changes an organization appends a record of who ran it, when,
+  against which organization, with which arguments, the IDs of the
+  users, groups, rules, policies and sessions it changed, its
+  result and the IDs of the requests it made to Okta. The values
+  of secret options like -api-token are masked.
+
+  The log is a file containing a JSON record per line, audit.jsonl
+  in the okta-admin directory of the user's configuration
+  directory, eg- ~/.config/okta-admin on Linux, unless specified
+  by the -audit-log global option or the OKTA_ADMIN_AUDIT_LOG
+  environment variable. It is only readable by the user. Records
+  can also be forwarded to syslog or a webhook, see the
+  -audit-syslog and -audit-webhook global options.
+`
+
+	return c.Command.prepareHelpMessage(helpText, map[string]interface{}{})
+}
+
+func (c *AuditLogCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,174 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type AuditLogShowCommand struct {
+	*Command
+}
+
+type AuditLogShowCommandConfig struct {
+	Command, User, Org string
+	Target, Result     string
+	Since              time.Time
+	Limit              int
+	Format             string
+}
+
+func (c *AuditLogShowCommand) Synopsis() string {
+	return "Show the changes recorded in the audit log"
+}
+
+func (c *AuditLogShowCommand) Help() string {
+	helpText := `
+Usage: okta-admin audit-log show [options]
+
+  Prints the records of the audit log matching the options, in
+  the order they were recorded, most recent last. See
+  "okta-admin audit-log -help" for what is recorded.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -command  Only show runs of this command, eg- deactivate-user
+  -os-user  Only show commands run by this user of the operating
+            system
+  -org      Only show commands run against organizations whose URL
+            contains this value, eg- hogwarts.okta.com
+  -target   Only show commands which changed the user, group, rule,
+            policy or session with this ID
+  -result   Only show commands with this result, one of success,
+            partial-failure or failure
+  -since    Only show commands run after this point in time. Either
+            an RFC3339 timestamp or a duration in the past, like
+            30m, 12h or 7d (Default: all of them)
+  -limit    Maximum number of records to show, the most recent ones.
+            0 shows all of them (Default: 20)
+  -format   Format to print records in. Either json, to print each
+            record as a single line of JSON, or text for a table
+            (Default: text)
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *AuditLogShowCommand) ParseArgs(args []string) (*AuditLogShowCommandConfig, error) {
+	var cfg AuditLogShowCommandConfig
+	var since string
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.Command, "command", "", "")
+	flags.StringVar(&cfg.User, "os-user", "", "")
+	flags.StringVar(&cfg.Org, "org", "", "")
+	flags.StringVar(&cfg.Target, "target", "", "")
+	flags.StringVar(&cfg.Result, "result", "", "")
+	flags.StringVar(&since, "since", "", "")
+	flags.IntVar(&cfg.Limit, "limit", 20, "")
+	flags.StringVar(&cfg.Format, "format", "text", "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+	if rest := flags.Args(); len(rest) > 0 {
+		return &cfg, errors.New(fmt.Sprintf("unexpected arguments: %s", strings.Join(rest, " ")))
+	}
+	if cfg.Limit < 0 {
+		return &cfg, errors.New("limit cannot be negative")
+	}
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "result", Value: cfg.Result, ValidationFunc: ValidateOneOf(auditResultSuccess, auditResultPartialFailure, auditResultFailure)},
+		&parameter{Name: "format", Required: true, Value: cfg.Format, ValidationFunc: ValidateOneOf("text", "json")},
+	)
+	if err != nil {
+		return &cfg, err
+	}
+
+	if since != "" {
+		if cfg.Since, err = ParseRelativeTime(since, time.Now()); err != nil {
+			return &cfg, errors.New(fmt.Sprintf("since: %v", err))
+		}
+	}
+	return &cfg, nil
+}
+
+// matches returns whether the record matches the filters.
+func (cfg *AuditLogShowCommandConfig) matches(rec *auditRecord) bool {
+	return (cfg.Command == "" || rec.Command == cfg.Command) &&
+		(cfg.User == "" || rec.User == cfg.User) &&
+		(cfg.Org == "" || strings.Contains(strings.ToLower(rec.OrgUrl), strings.ToLower(cfg.Org))) &&
+		(cfg.Target == "" || containsString(rec.Targets, cfg.Target)) &&
+		(cfg.Result == "" || rec.Result == cfg.Result) &&
+		(cfg.Since.IsZero() || rec.Time.After(cfg.Since))
+}
+
+func (c *AuditLogShowCommand) Run(args []string) int {
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	var records []*auditRecord
+	invalid, err := readAuditRecords(c.Meta.GlobalOptions, func(rec *auditRecord) {
+		if !cfg.matches(rec) {
+			return
+		}
+		records = append(records, rec)
+		if cfg.Limit > 0 && len(records) > cfg.Limit {
+			records = records[1:]
+		}
+	})
+	if err != nil {
+		c.logError("Failed to read the audit log", err)
+		return ExitFailure
+	}
+	if invalid > 0 {
+		c.Logger.Printf("Skipped %d line(s) of the audit log which aren't valid records\n", invalid)
+	}
+	if len(records) == 0 {
+		c.Logger.Println("No matching records were found")
+		return ExitOK
+	}
+
+	if cfg.Format == "json" {
+		for _, rec := range records {
+			line, err := json.Marshal(rec)
+			if err != nil {
+				c.logError("Failed to format record", err)
+				return ExitFailure
+			}
+			c.Logger.Println(string(line))
+		}
+		return ExitOK
+	}
+
+	w := tabwriter.NewWriter(c.Logger.Writer(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tUSER\tORG\tCOMMAND\tRESULT\tTARGETS\tARGUMENTS")
+	for _, rec := range records {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			rec.Time.UTC().Format(time.RFC3339),
+			rec.User,
+			rec.OrgUrl,
+			rec.Command,
+			rec.Result,
+			Coalesce(strings.Join(rec.Targets, ","), "-"),
+			strings.Join(rec.Args, " "),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		c.logError("Failed to render records", err)
+		return exitCode(err)
+	}
+	return ExitOK
+}
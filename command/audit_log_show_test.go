@@ -0,0 +1,122 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func createTestAuditLogShowCommand(globalOptsHelpText string) *AuditLogShowCommand {
+	return &AuditLogShowCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_audit_log_show_cmd"),
+	}
+}
+
+func TestAuditLogShowCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestAuditLogShowCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestAuditLogShowCommand_ParseArgs(t *testing.T) {
+	t.Parallel()
+
+	c := createTestAuditLogShowCommand("")
+	cfg, err := c.ParseArgs([]string{"-command", "deactivate-user", "-result", "failure", "-since", "7d", "-limit", "0"})
+	if err != nil {
+		t.Fatalf("Failed to parse arguments: %v", err)
+	}
+	if cfg.Command != "deactivate-user" || cfg.Result != auditResultFailure || cfg.Limit != 0 || cfg.Format != "text" {
+		t.Errorf("Unexpected config %+v", cfg)
+	}
+	if since := time.Since(cfg.Since); since < 7*24*time.Hour || since > 7*24*time.Hour+time.Minute {
+		t.Errorf("Expected since to be 7 days ago, received %v", cfg.Since)
+	}
+
+	for _, args := range [][]string{
+		{"-result", "ok"},
+		{"-format", "csv"},
+		{"-limit", "-1"},
+		{"-since", "yesterday"},
+		{"deactivate-user"},
+	} {
+		c := createTestAuditLogShowCommand("")
+		if _, err := c.ParseArgs(args); err == nil {
+			t.Errorf("Expected parsing of %q to fail", args)
+		}
+	}
+}
+
+func TestAuditLogShowCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "okta-admin-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.jsonl")
+
+	log := `{"time":"2020-01-02T10:00:00Z","user":"albus","orgUrl":"https://hogwarts.okta.com/","command":"deactivate-user","args":["-user","tom.riddle"],"targets":["00u1"],"result":"success","exitStatus":0,"requestIds":["req1"]}
+not a record
+{"time":"2020-01-03T10:00:00Z","user":"minerva","orgUrl":"https://hogwarts.okta.com/","command":"assign-groups","args":["-user","harry.potter","-groups","Gryffindor"],"targets":["00u2","00g1"],"result":"success","exitStatus":0,"requestIds":["req2"]}
+{"time":"2020-01-04T10:00:00Z","user":"albus","orgUrl":"https://durmstrang.okta.com/","command":"end-session","args":["-id","102abc"],"targets":[],"result":"failure","exitStatus":4,"requestIds":[]}
+`
+	if err := ioutil.WriteFile(path, []byte(log), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		c, out := createTestCommandWithClient("test_audit_log_show_cmd", newFakeOktaClient())
+		c.Meta.GlobalOptions.AuditLog = path
+		if code := (&AuditLogShowCommand{Command: c}).Run(args); code != ExitOK {
+			t.Fatalf("Expected exit code 0 for %q, received %d: %s", args, code, out)
+		}
+		return out.String()
+	}
+
+	out := run()
+	if !strings.Contains(out, "Skipped 1 line(s)") {
+		t.Errorf("Expected the invalid line to be reported, received:\n%s", out)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[1], "TIME") || !strings.Contains(lines[2], "deactivate-user") || !strings.Contains(lines[4], "end-session") {
+		t.Errorf("Expected every record to be shown in order, received:\n%s", out)
+	}
+	if !strings.Contains(lines[3], "00u2,00g1") || !strings.Contains(lines[3], "-user harry.potter -groups Gryffindor") {
+		t.Errorf("Expected the targets and arguments to be shown, received:\n%s", lines[3])
+	}
+
+	testCases := []struct {
+		args     []string
+		commands []string
+	}{
+		{[]string{"-os-user", "albus"}, []string{"deactivate-user", "end-session"}},
+		{[]string{"-org", "HOGWARTS"}, []string{"deactivate-user", "assign-groups"}},
+		{[]string{"-target", "00g1"}, []string{"assign-groups"}},
+		{[]string{"-result", "failure"}, []string{"end-session"}},
+		{[]string{"-command", "assign-groups"}, []string{"assign-groups"}},
+		{[]string{"-since", "2020-01-02T12:00:00Z"}, []string{"assign-groups", "end-session"}},
+		{[]string{"-limit", "1"}, []string{"end-session"}},
+	}
+	for _, tc := range testCases {
+		out := run(append(tc.args, "-format", "json")...)
+		var commands []string
+		for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+			if strings.HasPrefix(line, "{") {
+				commands = append(commands, strings.Split(strings.Split(line, `"command":"`)[1], `"`)[0])
+			}
+		}
+		if !testEq(commands, tc.commands) {
+			t.Errorf("Expected %q to show %q, received:\n%s", tc.args, tc.commands, out)
+		}
+	}
+
+	if out := run("-command", "create-user"); !strings.Contains(out, "No matching records were found") {
+		t.Errorf("Expected no records to be found, received:\n%s", out)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package command
+
+import "log/syslog"
+
+// sendAuditSyslog writes the audit record to the syslog daemon
+// specified by the -audit-syslog global option, with the auth
+// facility and notice severity.
+func sendAuditSyslog(daemon string, line []byte) error {
+	network, addr, err := parseAuditSyslog(daemon)
+	if err != nil {
+		return err
+	}
+	w, err := syslog.Dial(network, addr, syslog.LOG_AUTH|syslog.LOG_NOTICE, "okta-admin")
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	return w.Notice(string(line))
+}
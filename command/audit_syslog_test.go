@@ -0,0 +1,41 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package command
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendAuditSyslog(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := sendAuditSyslog("udp://"+conn.LocalAddr().String(), []byte(`{"command":"end-session"}`)); err != nil {
+		t.Fatalf("Failed to send record: %v", err)
+	}
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Priority 37 is the auth facility with the notice severity
+	if msg := string(buf[:n]); !strings.HasPrefix(msg, "<37>") || !strings.Contains(msg, `okta-admin[`) || !strings.HasSuffix(strings.TrimSpace(msg), `{"command":"end-session"}`) {
+		t.Errorf("Expected record to be sent to syslog, received %q", msg)
+	}
+
+	for _, daemon := range []string{"syslog", "http://localhost:514", "udp://"} {
+		if err := sendAuditSyslog(daemon, nil); err == nil {
+			t.Errorf("Expected %s to be rejected", daemon)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package command
+
+import "errors"
+
+// sendAuditSyslog fails, since Go's syslog package doesn't support
+// Windows and Plan 9.
+func sendAuditSyslog(daemon string, line []byte) error {
+	if _, _, err := parseAuditSyslog(daemon); err != nil {
+		return err
+	}
+	return errors.New("syslog is not supported on this platform")
+}
@@ -0,0 +1,137 @@
+package command
+
+import (
+	"encoding/json"
+	"github.com/okta/okta-sdk-golang/okta"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMaskSecrets(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct{ args, expected []string }{
+		{[]string{"-user", "00u1"}, []string{"-user", "00u1"}},
+		{[]string{"-api-token", "123abc", "-user", "00u1"}, []string{"-api-token", "********", "-user", "00u1"}},
+		{[]string{"--api-token=123abc"}, []string{"--api-token=********"}},
+		{[]string{"-user", "00u1", "-api-token"}, []string{"-user", "00u1", "-api-token"}},
+	}
+	for _, tc := range testCases {
+		if res := maskSecrets(tc.args); !testEq(res, tc.expected) {
+			t.Errorf("Expected %q to be masked as %q, received %q", tc.args, tc.expected, res)
+		}
+	}
+}
+
+// readTestAuditLog returns the records of the audit log at path.
+func readTestAuditLog(t *testing.T, path string) []*auditRecord {
+	t.Helper()
+	var records []*auditRecord
+	invalid, err := readAuditRecords(&Config{AuditLog: path}, func(rec *auditRecord) {
+		records = append(records, rec)
+	})
+	if err != nil || invalid > 0 {
+		t.Fatalf("Failed to read audit log, %d invalid records: %v", invalid, err)
+	}
+	return records
+}
+
+func TestCommand_RecordAudit(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "okta-admin-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var forwarded []byte
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded, _ = ioutil.ReadAll(r.Body)
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer webhook.Close()
+
+	client := newFakeOktaClient()
+	client.users = []*okta.User{fakeUser("00u1", "harry.potter@hogwarts.co.uk", "ACTIVE")}
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor")}
+	c, out := createTestCommandWithClient("test_record_audit", client)
+	opts := c.Meta.GlobalOptions
+	opts.AuditLog = filepath.Join(dir, "logs", "audit.jsonl")
+	opts.AuditWebhook = webhook.URL
+	c.Meta.FlagSet.StringVar(&opts.ApiToken, "api-token", opts.ApiToken, "")
+
+	args := []string{"-user", "harry.potter@hogwarts.co.uk", "-groups", "Gryffindor,Slytherin", "-api-token", "123abc"}
+	code := (&AssignUserGroupsCommand{Command: c}).Run(args)
+	c.RecordAudit("assign-groups", args, code)
+
+	// Commands which don't change the organization or fail to parse
+	// their arguments aren't recorded
+	c.RecordAudit("list-groups", nil, ExitOK)
+	c.RecordAudit("deactivate-user", []string{"-foo"}, ExitUsage)
+
+	records := readTestAuditLog(t, opts.AuditLog)
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, received %d: %s", len(records), out)
+	}
+	rec := records[0]
+	if rec.Command != "assign-groups" || rec.OrgUrl != "https://foo.okta.com/" || rec.User == "" || rec.Time.IsZero() {
+		t.Errorf("Expected the command, organization, user and time to be recorded, received %+v", rec)
+	}
+	if !testEq(rec.Args, []string{"-user", "harry.potter@hogwarts.co.uk", "-groups", "Gryffindor,Slytherin", "-api-token", "********"}) {
+		t.Errorf("Expected the arguments to be recorded with the API token masked, received %q", rec.Args)
+	}
+	if !testEq(rec.Targets, []string{"00u1", "00g1"}) {
+		t.Errorf("Expected the user and group changed to be recorded, received %q", rec.Targets)
+	}
+	if rec.Result != auditResultPartialFailure || rec.ExitStatus != ExitPartialFailure {
+		t.Errorf("Expected the partial failure to be recorded, received %s (%d)", rec.Result, rec.ExitStatus)
+	}
+
+	var sent auditRecord
+	if err := json.Unmarshal(forwarded, &sent); err != nil || sent.Command != "assign-groups" {
+		t.Errorf("Expected the record to be forwarded to the webhook, received %q", forwarded)
+	}
+	if info, err := os.Stat(opts.AuditLog); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the audit log to be only readable by the user, received %v (error %v)", info.Mode(), err)
+	}
+
+	// Failing to forward a record is reported, but it's still logged
+	opts.AuditWebhook = webhook.URL + "/missing\x7f"
+	opts.AuditSyslog = "smtp://localhost"
+	out.Reset()
+	c.RecordAudit("end-session", []string{"-id", "102abc"}, ExitOK)
+	for _, expected := range []string{"Failed to forward the audit record to syslog", "Failed to forward the audit record to the webhook"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected output to contain %q, received:\n%s", expected, out)
+		}
+	}
+	if records := readTestAuditLog(t, opts.AuditLog); len(records) != 2 || !testEq(records[1].Targets, []string{}) {
+		t.Errorf("Expected the second record to be logged without targets, received %d records", len(records))
+	}
+}
+
+func TestAuditTrail(t *testing.T) {
+	t.Parallel()
+
+	var trail auditTrail
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set(oktaRequestIdHeader, "XkPvE1jW2kHd7sLq6bF5ZwAAA5Q")
+	trail.add(resp, "00u1", "00g1")
+	trail.add(nil, "00u1", "")
+
+	targets, requestIDs := trail.reset()
+	if !testEq(targets, []string{"00u1", "00g1"}) || !testEq(requestIDs, []string{"XkPvE1jW2kHd7sLq6bF5ZwAAA5Q"}) {
+		t.Errorf("Expected targets and request ID to be collected, received %q and %q", targets, requestIDs)
+	}
+	if targets, requestIDs = trail.reset(); len(targets) != 0 || len(requestIDs) != 0 {
+		t.Errorf("Expected trail to be reset, received %q and %q", targets, requestIDs)
+	}
+}
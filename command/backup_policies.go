@@ -0,0 +1,132 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+type BackupPoliciesCommand struct {
+	*Command
+}
+
+type BackupPoliciesCommandConfig struct {
+	OutputFile string
+}
+
+func (c *BackupPoliciesCommand) Synopsis() string {
+	return "Back up all policies and their rules to a file"
+}
+
+func (c *BackupPoliciesCommand) Help() string {
+	helpText := `
+Usage: okta-admin backup-policies [options]
+
+  Saves every sign-on, password and MFA enrollment policy in the
+  organization, along with their rules, to a JSON file. Policies
+  and rules are saved exactly as returned by Okta, so that they
+  can be restored later using restore-policies.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -out File to write the backup to
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *BackupPoliciesCommand) ParseArgs(args []string) (*BackupPoliciesCommandConfig, error) {
+	var cfg BackupPoliciesCommandConfig
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.OutputFile, "out", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+		&parameter{Name: "out", Required: true, Value: cfg.OutputFile},
+	)
+	return &cfg, err
+}
+
+func (c *BackupPoliciesCommand) Run(args []string) int {
+	var rulesCount int
+
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+	backup := &policyBackup{
+		Version:   policyBackupVersion,
+		CreatedAt: time.Now().UTC(),
+		OrgUrl:    c.Meta.GlobalOptions.OrgUrl,
+		Policies:  []*policyBackupEntry{},
+	}
+
+	for _, t := range policyTypes {
+		policies, err := listPolicies(client, t)
+		if err != nil {
+			c.logError(fmt.Sprintf("Failed to fetch list of %s policies", t), err)
+			return exitCode(err)
+		}
+
+		for _, p := range policies {
+			rules, _, err := client.ListPolicyRules(p.Id)
+			if err != nil {
+				c.logError(fmt.Sprintf("Failed to fetch rules of policy %s", p.Name), err)
+				return exitCode(err)
+			}
+
+			entry := &policyBackupEntry{Policy: p.Raw, Rules: []json.RawMessage{}}
+			for _, r := range rules {
+				entry.Rules = append(entry.Rules, r.Raw)
+			}
+			backup.Policies = append(backup.Policies, entry)
+			rulesCount += len(rules)
+		}
+	}
+
+	// Write to a temporary file first so that an existing backup
+	// isn't lost if writing fails midway.
+	tmp := cfg.OutputFile + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		c.logError("Failed to create backup file", err)
+		return exitCode(err)
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(backup); err != nil {
+		f.Close()
+		c.logError("Failed to write backup", err)
+		return exitCode(err)
+	}
+	if err := f.Close(); err != nil {
+		c.logError("Failed to write backup", err)
+		return exitCode(err)
+	}
+	if err := os.Rename(tmp, cfg.OutputFile); err != nil {
+		c.logError("Failed to save backup", err)
+		return exitCode(err)
+	}
+
+	c.Logger.Printf("Backed up %d policies and %d rules to %s\n", len(backup.Policies), rulesCount, cfg.OutputFile)
+	return 0
+}
@@ -0,0 +1,94 @@
+package command
+
+import (
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func createTestBackupPoliciesCommand(globalOptsHelpText string) *BackupPoliciesCommand {
+	return &BackupPoliciesCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_backup_policies_cmd"),
+	}
+}
+
+func TestBackupPoliciesCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestBackupPoliciesCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestBackupPoliciesCommand_ParseArgs(t *testing.T) {
+	t.Run("with output file", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestBackupPoliciesCommand("")
+		args := []string{"-out", "policies.json"}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.OutputFile != args[1] {
+			t.Errorf("Expected output file to be %s, received %s", args[1], cfg.OutputFile)
+		}
+	})
+
+	t.Run("without output file", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestBackupPoliciesCommand("")
+		if _, err := c.ParseArgs([]string{}); err == nil {
+			t.Errorf("Expected error when output file is not specified")
+		}
+	})
+}
+
+func TestBackupPoliciesCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "okta-admin-backup-policies")
+	if err != nil {
+		t.Fatalf("Failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "policies.json")
+
+	client := newFakeOktaClient()
+	client.policies = []*oktaapi.Policy{
+		fakePolicy("00p1", "Staff", policyTypePassword, "Employees"),
+		fakePolicy("00p2", "Default", policyTypeSignOn, ""),
+	}
+	client.policyRules["00p1"] = []*oktaapi.PolicyRule{
+		fakePolicyRule("0pr1", "Default rule", 1),
+		fakePolicyRule("0pr2", "Admins", 2),
+	}
+	run := func() (int, string) {
+		c, out := createTestCommandWithClient("test_backup_policies_cmd", client)
+		return (&BackupPoliciesCommand{Command: c}).Run([]string{"-out", file}), out.String()
+	}
+
+	if code, out := run(); code != ExitOK || !strings.Contains(out, "Backed up 2 policies and 2 rules") {
+		t.Fatalf("Expected the policies to be backed up, received %d: %s", code, out)
+	}
+	orgUrl, policies, rules, err := loadPolicyBackup(file)
+	if err != nil {
+		t.Fatalf("Failed to load backup: %v", err)
+	}
+	if orgUrl != "https://foo.okta.com/" || len(policies) != 2 || policies[0].Id != "00p2" || len(rules["00p1"]) != 2 {
+		t.Errorf("Unexpected backup of %s: %v, %v", orgUrl, policies, rules)
+	}
+
+	// A failed backup leaves the previous one intact
+	client.errs["ListPolicyRules"] = &oktaapi.ApiError{StatusCode: 403, Summary: "You do not have permission"}
+	if code, out := run(); code != ExitAuth {
+		t.Errorf("Expected exit code %d, received %d: %s", ExitAuth, code, out)
+	}
+	if _, policies, _, err := loadPolicyBackup(file); err != nil || len(policies) != 2 {
+		t.Errorf("Expected the previous backup to be kept, received %v (error %v)", policies, err)
+	}
+}
@@ -0,0 +1,204 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// batchStdin is the value of -file, or the argument, which makes
+// a batch command read users from standard input.
+const batchStdin = "-"
+
+// batchInput is the standard input users are read from.
+var batchInput io.Reader = os.Stdin
+
+// userBatchConfig contains the organization members a batch
+// command operates on, collected from -user, -users and -file (and
+// their aliases -email and -emails). Each member is identified by
+// their ID, login or email ID.
+type userBatchConfig struct {
+	User        string
+	Users       []string
+	Parallelism int
+}
+
+// userBatchResult is the outcome of a batch command's action for
+// a single member.
+type userBatchResult struct {
+	User    string
+	Message string
+	Err     error
+}
+
+// parseUserBatchArgs parses the arguments of a command operating
+// on a batch of organization members into cfg. Users are
+// de-duplicated while preserving their order.
+func (c *Command) parseUserBatchArgs(args []string, cfg *userBatchConfig) error {
+	var users, file string
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.User, "user", "", "")
+	flags.StringVar(&cfg.User, "email", "", "")
+	flags.StringVar(&users, "users", "", "")
+	flags.StringVar(&users, "emails", "", "")
+	flags.StringVar(&file, "file", "", "")
+	flags.IntVar(&cfg.Parallelism, "parallelism", 5, "")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if cfg.Parallelism < 1 {
+		return errors.New("parallelism must be at least 1")
+	}
+
+	// A lone "-" argument is equivalent to -file -
+	switch rest := flags.Args(); {
+	case len(rest) == 1 && rest[0] == batchStdin && file == "":
+		file = batchStdin
+	case len(rest) > 0:
+		return errors.New(fmt.Sprintf("unexpected arguments: %s", strings.Join(rest, " ")))
+	}
+	if file == batchStdin && c.Meta.GlobalOptions.ApiTokenStdin {
+		return errors.New("-api-token-stdin cannot be used when users are read from standard input")
+	}
+
+	err := c.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+	)
+	if err != nil {
+		return err
+	}
+
+	candidates := append([]string{cfg.User}, c.parseListOfValues(users, ParamListSep)...)
+	if file != "" {
+		fromFile, err := readUsers(file)
+		if err != nil {
+			return err
+		}
+		candidates = append(candidates, fromFile...)
+	}
+
+	seen := map[string]bool{}
+	for _, u := range candidates {
+		if u == "" || seen[u] {
+			continue
+		}
+		if err := ValidateUserIdentifier(u); err != nil {
+			return errors.New(fmt.Sprintf("%s: %v", u, err))
+		}
+		seen[u] = true
+		cfg.Users = append(cfg.Users, u)
+	}
+	if len(cfg.Users) == 0 {
+		return errors.New("user is required")
+	}
+	return nil
+}
+
+// readUsers reads one user ID, login or email ID per line from the
+// file, or from standard input if file is "-". Blank lines and
+// lines starting with # are ignored.
+func readUsers(file string) ([]string, error) {
+	var res []string
+	r := batchInput
+
+	if file != batchStdin {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		res = append(res, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New(fmt.Sprintf("failed to read users: %v", err))
+	}
+	return res, nil
+}
+
+// runUserBatch performs the action on every member of the batch,
+// at most cfg.Parallelism members at a time. The action returns a
+// message describing what was done to the member. Progress is
+// reported as members are processed and, if the batch contains
+// more than one member, a summary table is printed at the end.
+// Members which haven't been processed when the command is
+// interrupted are skipped and reported as failed.
+// It returns the exit code of the command, see fanOutExitCode.
+func (c *Command) runUserBatch(cfg *userBatchConfig, verb string, action func(user string) (string, error)) int {
+	var (
+		results = make([]*userBatchResult, len(cfg.Users), len(cfg.Users))
+		mu      sync.Mutex
+		done    int
+	)
+
+	ctx := c.operationContext()
+
+	errs := runPool(ctx, len(cfg.Users), cfg.Parallelism, func(ctx context.Context, i int) error {
+		u := cfg.Users[i]
+		msg, err := action(u)
+		results[i] = &userBatchResult{User: u, Message: msg, Err: err}
+
+		// Progress is reported under the lock so that counts
+		// are printed in order.
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		prefix := ""
+		if len(results) > 1 {
+			prefix = fmt.Sprintf("[%d/%d] ", done, len(results))
+		}
+		if err != nil {
+			c.logError(fmt.Sprintf("%sFailed to %s %s", prefix, verb, u), err)
+		} else {
+			c.Logger.Printf("%s%s\n", prefix, msg)
+		}
+		return err
+	})
+	for i, err := range errs {
+		if results[i] == nil {
+			results[i] = &userBatchResult{User: cfg.Users[i], Err: err}
+		}
+	}
+	if done < len(results) {
+		c.Logger.Printf("Stopped after processing %d of %d member(s): %v\n", done, len(results), c.operationErr())
+	}
+
+	var failures []error
+	for _, r := range results {
+		if r.Err != nil {
+			failures = append(failures, r.Err)
+		}
+	}
+	if len(results) > 1 {
+		c.Logger.Println()
+		w := tabwriter.NewWriter(c.Logger.Writer(), 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "USER\tRESULT")
+		for _, r := range results {
+			if r.Err != nil {
+				fmt.Fprintf(w, "%s\tFAILED: %v\n", r.User, r.Err)
+			} else {
+				fmt.Fprintf(w, "%s\tOK\n", r.User)
+			}
+		}
+		w.Flush()
+		c.Logger.Printf("\n%d succeeded, %d failed\n", len(results)-len(failures), len(failures))
+	}
+	return fanOutExitCode(len(results), failures)
+}
@@ -0,0 +1,117 @@
+package command
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommand_parseUserBatchArgs(t *testing.T) {
+	t.Run("with users from all inputs", func(t *testing.T) {
+		t.Parallel()
+
+		dir, err := ioutil.TempDir("", "okta-admin-batch")
+		if err != nil {
+			t.Fatalf("Failed to create temporary directory: %v", err)
+		}
+		defer os.RemoveAll(dir)
+
+		file := filepath.Join(dir, "emails.txt")
+		content := "# Offboarding wave 1\nron.weasley@hogwarts.co.uk\n\nhermione.granger@hogwarts.co.uk\nharry.potter@hogwarts.co.uk\n"
+		if err := ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write emails file: %v", err)
+		}
+
+		var cfg userBatchConfig
+		c := createTestCommand("", "test_batch_cmd")
+		args := []string{
+			"-user", "harry.potter",
+			"-emails", "draco.malfoy@hogwarts.co.uk, ron.weasley@hogwarts.co.uk",
+			"-file", file,
+			"-parallelism", "10",
+		}
+		if err := c.parseUserBatchArgs(args, &cfg); err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		expected := []string{
+			"harry.potter",
+			"draco.malfoy@hogwarts.co.uk",
+			"ron.weasley@hogwarts.co.uk",
+			"hermione.granger@hogwarts.co.uk",
+			"harry.potter@hogwarts.co.uk",
+		}
+		if !testEq(cfg.Users, expected) {
+			t.Errorf("Expected users to be %v, received %v", expected, cfg.Users)
+		}
+		if cfg.Parallelism != 10 {
+			t.Errorf("Expected parallelism to be 10, received %d", cfg.Parallelism)
+		}
+	})
+
+	t.Run("with invalid arguments", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := [][]string{
+			{},
+			{"-users", "harry.potter@hogwarts.co.uk,not a user"},
+			{"-email", "harry.potter@hogwarts.co.uk", "-parallelism", "0"},
+			{"-email", "harry.potter@hogwarts.co.uk", "unexpected"},
+			{"-file", "/non/existent/emails.txt"},
+		}
+		for _, tc := range testCases {
+			var cfg userBatchConfig
+			c := createTestCommand("", "test_batch_cmd")
+			if err := c.parseUserBatchArgs(tc, &cfg); err == nil {
+				t.Errorf("Expected args %v to be invalid", tc)
+			}
+		}
+	})
+
+	t.Run("with users and the API token from standard input", func(t *testing.T) {
+		t.Parallel()
+
+		for _, args := range [][]string{{"-file", "-"}, {"-"}} {
+			var cfg userBatchConfig
+			c := createTestCommand("", "test_batch_cmd")
+			c.Meta.GlobalOptions.ApiTokenStdin = true
+			if err := c.parseUserBatchArgs(args, &cfg); err == nil || !strings.Contains(err.Error(), "-api-token-stdin") {
+				t.Errorf("Expected args %v to be rejected along with -api-token-stdin, received %v", args, err)
+			}
+		}
+	})
+}
+
+func TestCommand_runUserBatch(t *testing.T) {
+	t.Parallel()
+
+	c := createTestCommand("", "test_batch_cmd")
+	cfg := &userBatchConfig{
+		Users:       []string{"a@hogwarts.co.uk", "b@hogwarts.co.uk", "c@hogwarts.co.uk"},
+		Parallelism: 2,
+	}
+
+	processed := make(chan string, 10)
+	action := func(user string) (string, error) {
+		processed <- user
+		if user == "b@hogwarts.co.uk" {
+			return "", errors.New("user not found")
+		}
+		return "done", nil
+	}
+
+	if code := c.runUserBatch(cfg, "process", action); code != ExitPartialFailure {
+		t.Errorf("Expected exit code %d when an item fails, received %d", ExitPartialFailure, code)
+	}
+	if len(processed) != len(cfg.Users) {
+		t.Errorf("Expected all %d items to be processed, %d were", len(cfg.Users), len(processed))
+	}
+
+	cfg.Users = []string{"a@hogwarts.co.uk"}
+	if code := c.runUserBatch(cfg, "process", action); code != 0 {
+		t.Errorf("Expected exit code 0 when all items succeed, received %d", code)
+	}
+}
@@ -0,0 +1,166 @@
+package command
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CacheDirEnv is the environment variable which overrides the
+// directory okta-admin caches data in.
+const CacheDirEnv = "OKTA_ADMIN_CACHE_DIR"
+
+const (
+	// completionCacheFile is the name of the file in the cache
+	// directory which contains the data used to complete command
+	// lines.
+	completionCacheFile = "completion.json"
+
+	// completionGroupsTTL is how long the cached names of groups
+	// are offered as completions before being fetched again.
+	completionGroupsTTL = 10 * time.Minute
+)
+
+// cacheDir returns the directory okta-admin caches data in, which
+// is specified by the OKTA_ADMIN_CACHE_DIR environment variable or
+// is the okta-admin directory in the user's cache directory, eg-
+// ~/.cache/okta-admin on Linux.
+func cacheDir() (string, error) {
+	if dir := os.Getenv(CacheDirEnv); dir != "" {
+		return dir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "okta-admin"), nil
+}
+
+// readCacheFile decodes the JSON file with the name in the cache
+// directory into v.
+func readCacheFile(name string, v interface{}) error {
+	dir, err := cacheDir()
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeCacheFile encodes v as JSON into the file with the name in
+// the cache directory.
+func writeCacheFile(name string, v interface{}) error {
+	dir, err := cacheDir()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dir, name), data)
+}
+
+// writeFileAtomic writes the data into the file at path, creating
+// the directories it's in. The file is replaced atomically and is
+// only readable by the user, since cached data may contain email
+// IDs.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	// ioutil.TempFile creates the file with mode 0600
+	f, err := ioutil.TempFile(dir, filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// orgCacheDir returns the directory in the cache directory which
+// contains the data cached for the organization.
+func orgCacheDir(orgUrl string) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	name := strings.TrimRight(strings.ToLower(orgUrl), "/")
+	if u, err := url.Parse(name); err == nil && u.Host != "" {
+		name = u.Host
+	}
+	name = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, name)
+	return filepath.Join(dir, "orgs", name), nil
+}
+
+// completionCache contains the data used to complete command lines,
+// keyed by organization URL.
+type completionCache struct {
+	Orgs map[string]*orgCompletions `json:"orgs"`
+}
+
+// orgCompletions contains the data used to complete command lines
+// run against an organization.
+type orgCompletions struct {
+	Groups          []string  `json:"groups,omitempty"`
+	GroupsFetchedAt time.Time `json:"groupsFetchedAt"`
+
+	// RecentUsers contains the email IDs used by the commands
+	// which succeeded, most recent first.
+	RecentUsers []string `json:"recentUsers,omitempty"`
+}
+
+// loadCompletionCache returns the cached completion data. It is
+// empty if the cache can't be read.
+func loadCompletionCache() *completionCache {
+	cache := &completionCache{}
+	if err := readCacheFile(completionCacheFile, cache); err != nil || cache.Orgs == nil {
+		cache.Orgs = map[string]*orgCompletions{}
+	}
+	return cache
+}
+
+func (cache *completionCache) save() error {
+	return writeCacheFile(completionCacheFile, cache)
+}
+
+// org returns the completion data of the organization, creating it
+// if it isn't cached yet. URLs differing only in case or trailing
+// slashes refer to the same organization.
+func (cache *completionCache) org(orgUrl string) *orgCompletions {
+	key := completionCacheKey(orgUrl)
+	if _, ok := cache.Orgs[key]; !ok {
+		cache.Orgs[key] = &orgCompletions{}
+	}
+	return cache.Orgs[key]
+}
+
+// remove deletes the completion data of the organization.
+func (cache *completionCache) remove(orgUrl string) {
+	delete(cache.Orgs, completionCacheKey(orgUrl))
+}
+
+func completionCacheKey(orgUrl string) string {
+	return strings.TrimRight(strings.ToLower(orgUrl), "/")
+}
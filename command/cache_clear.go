@@ -0,0 +1,103 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type CacheClearCommand struct {
+	*Command
+}
+
+type CacheClearCommandConfig struct {
+	All bool
+}
+
+func (c *CacheClearCommand) Synopsis() string {
+	return "Delete the data cached for an organization"
+}
+
+func (c *CacheClearCommand) Help() string {
+	helpText := `
+Usage: okta-admin cache clear [options]
+
+  Deletes the groups, the IDs users were resolved to and the
+  completion data cached for the organization, or for every
+  organization if -all is specified. Groups and user IDs are
+  only cached by commands run with the -cache global option.
+  Clearing the cache makes them fetch the latest data, eg- after
+  groups were renamed in the Okta Admin Console.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -all      Delete the data cached for every organization.
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *CacheClearCommand) ParseArgs(args []string) (*CacheClearCommandConfig, error) {
+	var cfg CacheClearCommandConfig
+
+	flags := c.Meta.FlagSet
+	flags.BoolVar(&cfg.All, "all", false, "")
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+	if rest := flags.Args(); len(rest) > 0 {
+		return &cfg, errors.New(fmt.Sprintf("unexpected arguments: %s", strings.Join(rest, " ")))
+	}
+	if cfg.All {
+		return &cfg, nil
+	}
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+	)
+	return &cfg, err
+}
+
+func (c *CacheClearCommand) Run(args []string) int {
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	if cfg.All {
+		dir, err := cacheDir()
+		if err == nil {
+			err = os.RemoveAll(dir)
+		}
+		if err != nil {
+			c.logError("Failed to clear the cache", err)
+			return ExitFailure
+		}
+		c.Logger.Printf("Cleared the cache in %s\n", dir)
+		return ExitOK
+	}
+
+	orgUrl := c.Meta.GlobalOptions.OrgUrl
+	dir, err := orgCacheDir(orgUrl)
+	if err == nil {
+		err = os.RemoveAll(dir)
+	}
+	if err == nil {
+		cache := loadCompletionCache()
+		cache.remove(orgUrl)
+		err = cache.save()
+	}
+	if err != nil {
+		c.logError("Failed to clear the cache", err)
+		return ExitFailure
+	}
+	c.Logger.Printf("Cleared the data cached for %s\n", orgUrl)
+	return ExitOK
+}
@@ -0,0 +1,86 @@
+package command
+
+import (
+	"os"
+	"testing"
+)
+
+func createTestCacheClearCommand(globalOptsHelpText string) *CacheClearCommand {
+	return &CacheClearCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_cache_clear_cmd"),
+	}
+}
+
+func TestCacheClearCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestCacheClearCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestCacheClearCommand_ParseArgs(t *testing.T) {
+	t.Parallel()
+
+	c := createTestCacheClearCommand("")
+	if cfg, err := c.ParseArgs([]string{"-all"}); err != nil || !cfg.All {
+		t.Errorf("Expected -all to be parsed, received %v (error %v)", cfg, err)
+	}
+
+	c = createTestCacheClearCommand("")
+	c.Meta.GlobalOptions.OrgUrl = ""
+	if _, err := c.ParseArgs([]string{}); err == nil {
+		t.Errorf("Expected parsing to fail without an org URL or -all")
+	}
+
+	c = createTestCacheClearCommand("")
+	if _, err := c.ParseArgs([]string{"groups"}); err == nil {
+		t.Errorf("Expected parsing to fail with an argument")
+	}
+}
+
+func TestCacheClearCommand_Run(t *testing.T) {
+	const org = "https://clear.okta.com/"
+	cc, err := newCachingClient(newFakeOktaClient(), org)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cc.cacheUserID("harry.potter@hogwarts.co.uk", "00u1")
+	cache := loadCompletionCache()
+	cache.org(org).RecentUsers = []string{"harry.potter@hogwarts.co.uk"}
+	cache.org("https://other.okta.com/").RecentUsers = []string{"ron.weasley@hogwarts.co.uk"}
+	if err := cache.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	c, out := createTestCommandWithClient("test_cache_clear_cmd", newFakeOktaClient())
+	c.Meta.GlobalOptions.OrgUrl = org
+	cmd := &CacheClearCommand{Command: c}
+	if code := cmd.Run([]string{}); code != ExitOK {
+		t.Fatalf("Expected exit code 0, received %d: %s", code, out)
+	}
+
+	if _, ok := cc.cachedUserID("harry.potter@hogwarts.co.uk"); ok {
+		t.Errorf("Expected cached user ID to be deleted")
+	}
+	cache = loadCompletionCache()
+	if users := cache.org(org).RecentUsers; len(users) != 0 {
+		t.Errorf("Expected completion data of the organization to be deleted, received %v", users)
+	}
+	if users := cache.org("https://other.okta.com/").RecentUsers; len(users) != 1 {
+		t.Errorf("Expected completion data of other organizations to be kept, received %v", users)
+	}
+
+	// -all deletes the cache directory, which is recreated when
+	// data is cached again
+	dir, err := cacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, out = createTestCommandWithClient("test_cache_clear_cmd", newFakeOktaClient())
+	cmd = &CacheClearCommand{Command: c}
+	if code := cmd.Run([]string{"-all"}); code != ExitOK {
+		t.Fatalf("Expected exit code 0, received %d: %s", code, out)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected cache directory to be deleted, received %v", err)
+	}
+}
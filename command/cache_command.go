@@ -0,0 +1,30 @@
+package command
+
+import "github.com/mitchellh/cli"
+
+// CacheCommand groups the commands managing the local cache. It only
+// prints its help message, which lists them.
+type CacheCommand struct {
+	*Command
+}
+
+func (c *CacheCommand) Synopsis() string {
+	return "Manage the data cached locally"
+}
+
+func (c *CacheCommand) Help() string {
+	helpText := `
+Usage: okta-admin cache <subcommand> [options]
+
+  Manages the data okta-admin caches in the okta-admin directory
+  of the user's cache directory, eg- ~/.cache/okta-admin on Linux,
+  or in the directory specified by the OKTA_ADMIN_CACHE_DIR
+  environment variable.
+`
+
+	return c.Command.prepareHelpMessage(helpText, map[string]interface{}{})
+}
+
+func (c *CacheCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
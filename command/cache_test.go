@@ -0,0 +1,33 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompletionCache(t *testing.T) {
+	cache := loadCompletionCache()
+	cache.org("https://Cache.okta.com/").RecentUsers = []string{"harry.potter@hogwarts.co.uk"}
+	if err := cache.save(); err != nil {
+		t.Fatalf("Failed to save cache: %v", err)
+	}
+
+	// URLs differing in case or trailing slashes share their data
+	users := loadCompletionCache().org("https://cache.okta.com").RecentUsers
+	if !testEq(users, []string{"harry.potter@hogwarts.co.uk"}) {
+		t.Errorf("Expected cached email ID, received %q", users)
+	}
+
+	dir, err := cacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(filepath.Join(dir, completionCacheFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := fi.Mode().Perm(); mode != 0600 {
+		t.Errorf("Expected cache to only be readable by the user, mode is %v", mode)
+	}
+}
@@ -5,16 +5,40 @@ import (
 	"errors"
 	"flag"
 	"fmt"
-	"github.com/okta/okta-sdk-golang/okta"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
 	"log"
+	"net/http"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Command contains objects passed to all CLI commands
 type Command struct {
 	Meta       *Metadata
 	Logger     *log.Logger
-	oktaClient *okta.Client
+	oktaClient oktaapi.Client
+	httpClient *http.Client
+
+	// clientCreds contains the credentials oktaClient was created
+	// with. It is nil if the client was injected, eg- in tests.
+	clientCreds *oktaapi.Credentials
+
+	ctxMu  sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// hinted contains the hints already printed by logError
+	hintsMu sync.Mutex
+	hinted  map[string]bool
+
+	// audit and journal collect the changes made by the command
+	// being run via recordingClient, which wraps recordedClient, to
+	// be recorded in the audit log and the journal once it's done
+	audit           auditTrail
+	journal         changeJournal
+	recordingClient oktaapi.Client
+	recordedClient  oktaapi.Client
 }
 
 // Metadata contains data passed to all CLI commands
@@ -29,6 +53,25 @@ type Metadata struct {
 // configuration.
 type Config struct {
 	OrgUrl, ApiToken string
+	Timeout          time.Duration
+
+	// Cache enables caching of groups and user lookups on disk,
+	// unless NoCache is set too.
+	Cache, NoCache bool
+
+	// AuditLog is the path of the audit log. AuditSyslog and
+	// AuditWebhook specify the syslog daemon and the URL audit
+	// records are forwarded to, if any.
+	AuditLog, AuditSyslog, AuditWebhook string
+
+	// ApiTokenStdin makes commands read the API token from the
+	// first line of standard input, instead of using ApiToken.
+	ApiTokenStdin bool
+
+	// ApiTokenLookup returns the API token stored for the
+	// organization by login, which is used if no token is
+	// specified. Tokens aren't looked up if it's nil.
+	ApiTokenLookup func(orgUrl string) (string, error)
 }
 
 // parameter represents a commandline parameter with full
@@ -43,15 +86,24 @@ type parameter struct {
 // raw string representing a list of parameter values.
 const ParamListSep = ","
 
-// OktaClient returns an instance of Okta Client initialized
-// with organization-specific API credentials. This method
+// OktaClient returns the client used to call the Okta API,
+// initialized with organization-specific API credentials. Its
+// requests are bound to the operation context. This method
 // only creates the client the first time it is called.
-// Subsequent calls return the cached client.
+// Subsequent calls return the cached client, which may also
+// have been injected beforehand, eg- a fake in tests, unless the
+// credentials in the global options have changed since, eg- they
+// were overridden for a single command run by the shell. If the
+// -cache global option is set, the client caches groups and user
+// lookups on disk. The changes made via the client are collected
+// to be recorded in the audit log and the journal.
 // This method should only be called after api credentials
 // have been populated in the metadata.
-func (c *Command) OktaClient() (*okta.Client, error) {
-	if c.oktaClient != nil {
-		return c.oktaClient, nil
+func (c *Command) OktaClient() (oktaapi.Client, error) {
+	opts := c.Meta.GlobalOptions
+	if c.oktaClient != nil && (c.clientCreds == nil ||
+		c.clientCreds.OrgUrl == opts.OrgUrl && c.clientCreds.ApiToken == opts.ApiToken) {
+		return c.cachingClient(c.recordChanges(c.oktaClient)), nil
 	}
 
 	if c.Meta.GlobalOptions.OrgUrl == "" {
@@ -61,13 +113,43 @@ func (c *Command) OktaClient() (*okta.Client, error) {
 		return nil, errors.New("api token cannot be empty")
 	}
 
-	client, err := okta.NewClient(context.Background(),
-		okta.WithOrgUrl(c.Meta.GlobalOptions.OrgUrl), okta.WithToken(c.Meta.GlobalOptions.ApiToken))
+	creds := c.credentials()
+	client, err := oktaapi.NewClient(creds)
 	if err != nil {
-		// Cache the newly created client
-		c.oktaClient = client
+		return nil, err
+	}
+	// Cache the newly created client
+	c.oktaClient, c.clientCreds = client, creds
+	return c.cachingClient(c.recordChanges(client)), nil
+}
+
+// recordChanges returns the client wrapped in an auditingClient and
+// a journalingClient, which collect the changes made via it in the
+// command's audit trail and journal. The same wrapper is returned
+// as long as the client is the same.
+func (c *Command) recordChanges(client oktaapi.Client) oktaapi.Client {
+	if c.recordingClient == nil || c.recordedClient != client {
+		c.recordedClient = client
+		c.recordingClient = &auditingClient{
+			Client: &journalingClient{Client: client, journal: &c.journal},
+			trail:  &c.audit,
+		}
+	}
+	return c.recordingClient
+}
+
+// cachingClient returns the client wrapped in a cachingClient if
+// the cache is enabled by the global options. If the cache
+// directory can't be determined, the client is used uncached.
+func (c *Command) cachingClient(client oktaapi.Client) oktaapi.Client {
+	opts := c.Meta.GlobalOptions
+	if !opts.Cache || opts.NoCache {
+		return client
 	}
-	return client, err
+	if cc, err := newCachingClient(client, opts.OrgUrl); err == nil {
+		return cc
+	}
+	return client
 }
 
 func (c *Command) prepareHelpMessage(helpText string, filler map[string]interface{}) string {
@@ -97,6 +179,21 @@ func (c *Command) parseListOfValues(rawInput, sep string) []string {
 
 func (c *Command) validateParameters(params ...*parameter) error {
 	for _, p := range params {
+		// The API token is read from standard input if requested,
+		// and the one stored for the organization is used if none
+		// was specified
+		if p.Name == "api-token" && c.Meta.GlobalOptions.ApiTokenStdin {
+			if err := c.readApiTokenStdin(); err != nil {
+				return err
+			}
+			p.Value = c.Meta.GlobalOptions.ApiToken
+		}
+		if p.Name == "api-token" && p.Value == "" {
+			if err := c.useStoredApiToken(); err != nil {
+				return err
+			}
+			p.Value = c.Meta.GlobalOptions.ApiToken
+		}
 		// Return error if a required param is not set
 		if p.Required && p.Value == "" {
 			return errors.New(fmt.Sprintf("%s is required", p.Name))
@@ -111,3 +208,33 @@ func (c *Command) validateParameters(params ...*parameter) error {
 	}
 	return nil
 }
+
+// logError prints the message along with the error. If the error
+// was returned by the Okta API, it contains Okta's summary and the
+// causes of the error along with the error ID, and advice on how
+// to resolve it is printed too if the error is a common one. Each
+// piece of advice is only printed once per operation, so commands
+// reporting many similar errors don't repeat it.
+func (c *Command) logError(msg string, err error) {
+	c.hintsMu.Lock()
+	defer c.hintsMu.Unlock()
+
+	c.Logger.Printf("%s: %v\n", msg, err)
+	if hint := errorHint(err); hint != "" && !c.hinted[hint] {
+		c.Logger.Printf("Hint: %s\n", hint)
+		if c.hinted == nil {
+			c.hinted = map[string]bool{}
+		}
+		c.hinted[hint] = true
+	}
+}
+
+// errorHint returns advice on how to resolve the error if it
+// wraps an error of the Okta API, or an empty string otherwise.
+func errorHint(err error) string {
+	var apiErr *oktaapi.ApiError
+	if errors.As(err, &apiErr) {
+		return apiErr.Hint()
+	}
+	return ""
+}
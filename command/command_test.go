@@ -1,13 +1,39 @@
 package command
 
 import (
+	"errors"
 	"flag"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
 	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
 
+func TestMain(m *testing.M) {
+	// Commands cache data and record changes in a temporary
+	// directory, not the user's
+	dir, err := ioutil.TempDir("", "okta-admin-cache")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(CacheDirEnv, dir)
+	os.Setenv(AuditLogEnv, filepath.Join(dir, "audit.jsonl"))
+	os.Setenv(JournalEnv, filepath.Join(dir, "journal.jsonl"))
+	// API tokens are stored in a temporary file, not the user's
+	// keyring, and quickly encrypted
+	os.Setenv(TokenFileEnv, filepath.Join(dir, "tokens.enc"))
+	os.Setenv(PassphraseEnv, "mischief managed")
+	systemKeyring = &fakeKeyring{}
+	tokenFileIterations = 1000
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 const testHelpMessage = `
 Welcome to Hogwarts!
 `
@@ -88,4 +114,89 @@ func TestCommand_validateParameters(t *testing.T) {
 			t.Error("Expected param to be valid as it is not required")
 		}
 	})
+
+	t.Run("stored api token", func(t *testing.T) {
+		t.Parallel()
+		c := createTestCommand("", "test_stored_api_token")
+		opts := c.Meta.GlobalOptions
+		opts.ApiToken = ""
+		opts.ApiTokenLookup = func(orgUrl string) (string, error) {
+			if orgUrl != "https://foo.okta.com/" {
+				return "", errors.New("unexpected organization")
+			}
+			return "stored123", nil
+		}
+
+		if err := c.validateParameters(&parameter{Name: "api-token", Required: true, Value: opts.ApiToken}); err != nil {
+			t.Errorf("Expected the stored token to be used, received %v", err)
+		}
+		if opts.ApiToken != "stored123" {
+			t.Errorf("Expected the API token to be set to the stored one, received %q", opts.ApiToken)
+		}
+
+		opts.ApiToken, opts.OrgUrl = "", "https://bar.okta.com/"
+		if err := c.validateParameters(&parameter{Name: "api-token", Required: true, Value: opts.ApiToken}); err == nil || !strings.Contains(err.Error(), "unexpected organization") {
+			t.Errorf("Expected failing to look up the token to be reported, received %v", err)
+		}
+	})
+}
+
+func TestCommand_logError(t *testing.T) {
+	t.Parallel()
+
+	c, out := createTestCommandWithClient("test_log_error", nil)
+	apiErr := &oktaapi.ApiError{Code: oktaapi.ErrorCodeForbidden, Summary: "You do not have permission to perform the requested action", Id: "oae1"}
+
+	c.logError("Failed to add user to Slytherin", fmt.Errorf("failed to add member: %w", apiErr))
+	c.logError("Failed to add user to Gryffindor", apiErr)
+	c.logError("Failed to parse arguments", errors.New("user is required"))
+
+	expected := "Failed to add user to Slytherin: failed to add member: You do not have permission to perform the requested action (E0000006, errorId oae1)\n" +
+		"Hint: " + apiErr.Hint() + "\n" +
+		"Failed to add user to Gryffindor: You do not have permission to perform the requested action (E0000006, errorId oae1)\n" +
+		"Failed to parse arguments: user is required\n"
+	if out.String() != expected {
+		t.Errorf("Expected output:\n%s\nreceived:\n%s", expected, out.String())
+	}
+
+	// Hints are printed again once the operation ends
+	c.EndOperation()
+	out.Reset()
+	c.logError("Failed to add user to Gryffindor", apiErr)
+	if !strings.Contains(out.String(), "Hint: ") {
+		t.Errorf("Expected hint to be printed in a new operation, received:\n%s", out.String())
+	}
+}
+
+func TestCommand_OktaClient(t *testing.T) {
+	t.Parallel()
+
+	c := createTestCommand("", "test_okta_client")
+	defer c.EndOperation()
+
+	client, err := c.OktaClient()
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if cached, _ := c.OktaClient(); cached != client {
+		t.Errorf("Expected client to be cached")
+	}
+	c.EndOperation()
+	if cached, _ := c.OktaClient(); cached != client {
+		t.Errorf("Expected client to be reused by the next operation")
+	}
+
+	c.Meta.GlobalOptions.ApiToken = "456def"
+	if recreated, _ := c.OktaClient(); recreated == client {
+		t.Errorf("Expected client to be recreated once the credentials change")
+	}
+
+	// Injected clients are kept, and the changes made via them are
+	// recorded
+	injected := newFakeOktaClient()
+	c, _ = createTestCommandWithClient("test_okta_client", injected)
+	c.Meta.GlobalOptions.OrgUrl = "https://bar.okta.com/"
+	if cached, _ := c.OktaClient(); cached.(*auditingClient).Client.(*journalingClient).Client != injected {
+		t.Errorf("Expected injected client to be kept")
+	}
 }
@@ -0,0 +1,205 @@
+package command
+
+import (
+	"github.com/mitchellh/cli"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxRecentUsers is the number of recently used email IDs offered
+// as completions.
+const maxRecentUsers = 50
+
+var (
+	// rxHelpFlag matches the flags described in the help message
+	// of a command.
+	rxHelpFlag = regexp.MustCompile(`(?:^|[\s(])(-[a-z][a-z0-9-]*)`)
+
+	// groupFlags are the flags whose values are completed with
+	// group names.
+	groupFlags = map[string]bool{"-groups": true}
+
+	// userFlags are the flags whose values are completed with
+	// recently used email IDs.
+	userFlags = map[string]bool{"-user": true, "-email": true, "-users": true, "-emails": true, "-actor": true}
+)
+
+// completer completes the words of okta-admin command lines, both
+// in the shell and in the shells the completion scripts are
+// installed in.
+type completer struct {
+	commands map[string]cli.CommandFactory
+	// builtins are additional names completed as the first word
+	builtins []string
+
+	// flags caches the flags of commands, keyed by command name
+	flags map[string][]string
+
+	// groups and users return the group names and email IDs
+	// offered as values of the group and user flags
+	groups func() []string
+	users  func() []string
+}
+
+func newCompleter(commands map[string]cli.CommandFactory) *completer {
+	return &completer{
+		commands: commands,
+		flags:    map[string][]string{},
+		groups:   func() []string { return nil },
+		users:    func() []string { return nil },
+	}
+}
+
+// complete returns the candidates for completing cur, the word
+// being typed after words, which start with the command name. The
+// candidates replace the whole word once the prefix is prepended
+// to them. It is the "-flag=" part of cur if a flag's value is
+// being typed after it.
+func (c *completer) complete(words []string, cur string) (prefix string, candidates []string) {
+	var listPrefix string
+	var values []string
+	switch {
+	case len(words) == 0 || len(words) == 1 && words[0] == "help" && c.isBuiltin("help"):
+		values = c.subcommands("")
+		if len(words) == 0 {
+			values = append(values, c.builtins...)
+		}
+	case len(words) == 1 && !strings.HasPrefix(cur, "-") && len(c.subcommands(words[0])) > 0:
+		values = c.subcommands(words[0])
+	case strings.HasPrefix(cur, "-") && !strings.Contains(cur, "="):
+		values = c.commandFlags(c.commandName(words))
+	default:
+		flagName := words[len(words)-1]
+		if i := strings.Index(cur, "="); i > 0 && strings.HasPrefix(cur, "-") {
+			flagName, prefix, cur = cur[:i], cur[:i+1], cur[i+1:]
+		}
+
+		switch {
+		case groupFlags[flagName]:
+			values = c.groups()
+		case userFlags[flagName]:
+			values = c.users()
+		}
+		// Lists of values are completed after the last separator
+		if i := strings.LastIndex(cur, ParamListSep); i >= 0 {
+			listPrefix, cur = cur[:i+1], cur[i+1:]
+		}
+	}
+
+	for _, v := range values {
+		if strings.HasPrefix(strings.ToLower(v), strings.ToLower(cur)) {
+			candidates = append(candidates, listPrefix+v)
+		}
+	}
+	sort.Strings(candidates)
+	return prefix, candidates
+}
+
+// subcommands returns the names of the commands nested under the
+// parent command, eg- "clear" for "cache", or the names of the
+// top-level commands if parent is empty.
+func (c *completer) subcommands(parent string) []string {
+	seen := map[string]bool{}
+	var res []string
+	for name := range c.commands {
+		if parent != "" {
+			if !strings.HasPrefix(name, parent+" ") {
+				continue
+			}
+			name = strings.TrimPrefix(name, parent+" ")
+		}
+		if name = strings.Fields(name)[0]; !seen[name] {
+			seen[name] = true
+			res = append(res, name)
+		}
+	}
+	return res
+}
+
+// commandName returns the name of the command the words start
+// with, which is made of the first two words for nested commands.
+func (c *completer) commandName(words []string) string {
+	if len(words) > 1 {
+		if _, ok := c.commands[words[0]+" "+words[1]]; ok {
+			return words[0] + " " + words[1]
+		}
+	}
+	return words[0]
+}
+
+func (c *completer) isBuiltin(name string) bool {
+	for _, b := range c.builtins {
+		if b == name {
+			return true
+		}
+	}
+	return false
+}
+
+// commandFlags returns the flags of the command, including global
+// options, as described in its help message.
+func (c *completer) commandFlags(name string) []string {
+	if flags, ok := c.flags[name]; ok {
+		return flags
+	}
+
+	factory, ok := c.commands[name]
+	if !ok {
+		return nil
+	}
+	cmd, err := factory()
+	if err != nil {
+		return nil
+	}
+
+	seen := map[string]bool{"-help": true}
+	flags := []string{"-help"}
+	for _, m := range rxHelpFlag.FindAllStringSubmatch(cmd.Help(), -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			flags = append(flags, m[1])
+		}
+	}
+	c.flags[name] = flags
+	return flags
+}
+
+// userFlagValues returns the email IDs specified as values of user
+// flags in the arguments of a command.
+func userFlagValues(args []string) []string {
+	var res []string
+	for i, arg := range args {
+		var value string
+		if parts := strings.SplitN(arg, "=", 2); len(parts) == 2 && userFlags[parts[0]] {
+			value = parts[1]
+		} else if userFlags[arg] && i+1 < len(args) {
+			value = args[i+1]
+		} else {
+			continue
+		}
+
+		for _, u := range strings.Split(value, ParamListSep) {
+			if u = strings.TrimSpace(u); strings.Contains(u, "@") {
+				res = append(res, u)
+			}
+		}
+	}
+	return res
+}
+
+// prependRecent returns the recently used values with the values
+// moved or added to the front, last one first, keeping at most
+// maxRecentUsers of them.
+func prependRecent(recent, values []string) []string {
+	for _, v := range values {
+		res := []string{v}
+		for _, r := range recent {
+			if r != v && len(res) < maxRecentUsers {
+				res = append(res, r)
+			}
+		}
+		recent = res
+	}
+	return recent
+}
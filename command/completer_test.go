@@ -0,0 +1,33 @@
+package command
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestUserFlagValues(t *testing.T) {
+	t.Parallel()
+
+	args := []string{"-user", "harry.potter@hogwarts.co.uk", "-groups", "a@b", "-emails=ron@hogwarts.co.uk, 00u2 ,ginny@hogwarts.co.uk", "-actor"}
+	expected := []string{"harry.potter@hogwarts.co.uk", "ron@hogwarts.co.uk", "ginny@hogwarts.co.uk"}
+	if res := userFlagValues(args); !testEq(res, expected) {
+		t.Errorf("Expected email IDs %q, received %q", expected, res)
+	}
+}
+
+func TestPrependRecent(t *testing.T) {
+	t.Parallel()
+
+	res := prependRecent([]string{"a", "b", "c"}, []string{"c", "d"})
+	if expected := []string{"d", "c", "a", "b"}; !testEq(res, expected) {
+		t.Errorf("Expected %q, received %q", expected, res)
+	}
+
+	var many []string
+	for i := 0; i < maxRecentUsers+10; i++ {
+		many = append(many, fmt.Sprintf("user%d@hogwarts.co.uk", i))
+	}
+	if res := prependRecent(nil, many); len(res) != maxRecentUsers || res[0] != many[len(many)-1] {
+		t.Errorf("Expected the %d most recent values, received %d starting with %s", maxRecentUsers, len(res), res[0])
+	}
+}
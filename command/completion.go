@@ -0,0 +1,349 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/mitchellh/cli"
+	"strings"
+	"time"
+)
+
+// CompleteCommandName is the name of the hidden command which the
+// completion scripts run to complete command lines.
+const CompleteCommandName = "__complete"
+
+// completionTimeout is the maximum time group names may take to be
+// fetched while completing a command line, unless -timeout is
+// shorter.
+const completionTimeout = 3 * time.Second
+
+// completionScripts contains the scripts printed by the completion
+// command, keyed by shell. They run the hidden __complete command
+// with the command line up to the cursor.
+var completionScripts = map[string]string{
+	"bash": `# okta-admin completion for bash
+_okta_admin() {
+    local candidate
+    COMPREPLY=()
+    while IFS= read -r candidate; do
+        COMPREPLY+=("$candidate")
+    done < <(okta-admin __complete -shell bash -- "${COMP_LINE:0:COMP_POINT}" "${COMP_WORDS[COMP_CWORD]}" 2>/dev/null)
+}
+complete -F _okta_admin okta-admin
+`,
+	"zsh": `# okta-admin completion for zsh
+_okta_admin() {
+    local -a candidates
+    candidates=(${(f)"$(okta-admin __complete -shell zsh -- "${BUFFER[1,CURSOR]}" 2>/dev/null)"})
+    compadd -U -- "${candidates[@]}"
+}
+compdef _okta_admin okta-admin
+`,
+	"fish": `# okta-admin completion for fish
+function __okta_admin_complete
+    okta-admin __complete -shell fish -- (commandline -cp) 2>/dev/null
+end
+complete -c okta-admin -f -a '(__okta_admin_complete)'
+`,
+}
+
+type CompletionCommand struct {
+	*Command
+}
+
+func (c *CompletionCommand) Synopsis() string {
+	return "Print a script which enables completion in a shell"
+}
+
+func (c *CompletionCommand) Help() string {
+	helpText := `
+Usage: okta-admin completion bash|zsh|fish
+
+  Prints a script which makes the shell complete the names of
+  commands and their options, group names after -groups and the
+  email IDs used recently after -user, -email and the like. To
+  enable completion, add the following line to the shell's
+  configuration, eg- ~/.bashrc or ~/.zshrc (after compinit):
+
+    source <(okta-admin completion bash)
+    source <(okta-admin completion zsh)
+
+  or, for fish, save the script as a completions file:
+
+    okta-admin completion fish > ~/.config/fish/completions/okta-admin.fish
+
+  Group names are fetched from the organization specified by the
+  global options on the command line being completed or by their
+  environment variables, and are cached for 10 minutes. If they
+  can't be fetched within 3 seconds, the cached names are offered.
+  The email IDs used by commands which succeeded are cached too,
+  per organization, in the okta-admin directory of the user's
+  cache directory, eg- ~/.cache/okta-admin on Linux, or in the
+  directory specified by the OKTA_ADMIN_CACHE_DIR environment
+  variable. The cache is only readable by the user.
+{{.GlobalOptionsHelpText}}
+Arguments:
+
+  bash|zsh|fish   Shell to print the completion script for.
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+// ParseArgs returns the shell the completion script is printed for.
+func (c *CompletionCommand) ParseArgs(args []string) (string, error) {
+	flags := c.Meta.FlagSet
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+
+	var shell string
+	switch rest := flags.Args(); len(rest) {
+	case 0:
+	case 1:
+		shell = rest[0]
+	default:
+		return "", errors.New(fmt.Sprintf("unexpected arguments: %s", strings.Join(rest[1:], " ")))
+	}
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "shell", Required: true, Value: shell, ValidationFunc: ValidateOneOf("bash", "zsh", "fish")},
+	)
+	return shell, err
+}
+
+func (c *CompletionCommand) Run(args []string) int {
+	shell, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	c.Logger.Print(completionScripts[shell])
+	return ExitOK
+}
+
+// CompleteCommand prints the candidates for completing a command
+// line, one per line. It is run by the completion scripts and is
+// hidden from the list of commands.
+type CompleteCommand struct {
+	*Command
+
+	// Commands contains the commands whose command lines are
+	// completed, keyed by their names.
+	Commands map[string]cli.CommandFactory
+}
+
+type CompleteCommandConfig struct {
+	Shell string
+	// Line is the command line up to the cursor
+	Line string
+	// Word is the word being completed, as split by bash, which
+	// also splits words at characters like = and :
+	Word string
+}
+
+func (c *CompleteCommand) Synopsis() string {
+	return "Print the candidates for completing a command line"
+}
+
+func (c *CompleteCommand) Help() string {
+	helpText := `
+Usage: okta-admin __complete [options] -- LINE [WORD]
+
+  Prints the candidates for completing the last word of LINE,
+  which is the command line up to the cursor, one per line. It
+  is run by the scripts printed by the completion command.
+
+Options:
+
+  -shell    Shell the candidates are printed for, one of bash, zsh
+            or fish. Candidates for bash are quoted and replace
+            WORD, the word being completed as split by bash.
+            (Default: bash)
+`
+
+	return c.Command.prepareHelpMessage(helpText, map[string]interface{}{})
+}
+
+func (c *CompleteCommand) ParseArgs(args []string) (*CompleteCommandConfig, error) {
+	var cfg CompleteCommandConfig
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.Shell, "shell", "bash", "")
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+
+	rest := flags.Args()
+	if len(rest) == 0 || len(rest) > 2 {
+		return &cfg, errors.New("a command line and optionally the word being completed must be specified")
+	}
+	cfg.Line = rest[0]
+	if len(rest) == 2 {
+		cfg.Word = rest[1]
+	}
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "shell", Required: true, Value: cfg.Shell, ValidationFunc: ValidateOneOf("bash", "zsh", "fish")},
+	)
+	return &cfg, err
+}
+
+func (c *CompleteCommand) Run(args []string) int {
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	line := []rune(cfg.Line)
+	words, start, _ := shellWords(line)
+	cur := ""
+	if start < len(line) {
+		cur, words = words[len(words)-1], words[:len(words)-1]
+	}
+	// The first word is okta-admin itself
+	if len(words) == 0 {
+		return ExitOK
+	}
+	words = words[1:]
+	c.applyGlobalOptions(words)
+
+	comp := newCompleter(map[string]cli.CommandFactory{})
+	for name, f := range c.Commands {
+		if name != CompleteCommandName {
+			comp.commands[name] = f
+		}
+	}
+	comp.groups = c.groupNames
+	comp.users = func() []string {
+		return loadCompletionCache().org(c.Meta.GlobalOptions.OrgUrl).RecentUsers
+	}
+
+	prefix, candidates := comp.complete(words, cur)
+	if cfg.Shell != "bash" {
+		// zsh and fish quote the candidates themselves
+		for _, v := range candidates {
+			c.Logger.Println(prefix + v)
+		}
+		return ExitOK
+	}
+
+	// bash replaces only the part of the word after the last
+	// character it splits words at, like = or :, so the part
+	// before it is trimmed from the candidates
+	trim := ""
+	if raw := string(line[start:]); cfg.Word != "" && strings.HasSuffix(raw, cfg.Word) {
+		trim = strings.TrimSuffix(raw, cfg.Word)
+	}
+	for _, v := range quoteShellWords(prefix, candidates) {
+		c.Logger.Println(strings.TrimPrefix(v, trim))
+	}
+	return ExitOK
+}
+
+// completionSkippedOptions are the options which aren't applied
+// when completing, since they'd read a file or standard input on
+// every completion.
+var completionSkippedOptions = map[string]bool{
+	"shell":           true,
+	"api-token-file":  true,
+	"api-token-stdin": true,
+}
+
+// applyGlobalOptions sets the global options specified on the
+// command line being completed, which override their environment
+// variables. Values which are invalid or still being typed are
+// ignored. Group names are fetched within completionTimeout.
+func (c *CompleteCommand) applyGlobalOptions(words []string) {
+	flags := c.Meta.FlagSet
+	for i, w := range words {
+		if !strings.HasPrefix(w, "-") {
+			continue
+		}
+		name := strings.TrimLeft(w, "-")
+		var value string
+		if parts := strings.SplitN(name, "=", 2); len(parts) == 2 {
+			name, value = parts[0], parts[1]
+		} else if i+1 < len(words) {
+			value = words[i+1]
+		} else {
+			continue
+		}
+		if !completionSkippedOptions[name] && flags.Lookup(name) != nil {
+			_ = flags.Set(name, value)
+		}
+	}
+
+	opts := c.Meta.GlobalOptions
+	if opts.Timeout <= 0 || opts.Timeout > completionTimeout {
+		opts.Timeout = completionTimeout
+	}
+}
+
+// groupNames returns the names of the groups in the organization.
+// They are cached for completionGroupsTTL once fetched. If they
+// can't be fetched, the cached names are returned even if they
+// have expired.
+func (c *CompleteCommand) groupNames() []string {
+	opts := c.Meta.GlobalOptions
+	if opts.OrgUrl == "" {
+		return nil
+	}
+	cache := loadCompletionCache()
+	org := cache.org(opts.OrgUrl)
+	if time.Since(org.GroupsFetchedAt) < completionGroupsTTL {
+		return org.Groups
+	}
+
+	// The token stored by login is used if none was specified, but
+	// only if it's in the keyring, since the passphrase of the file
+	// would be prompted for
+	if opts.ApiToken == "" && systemKeyring.Available() {
+		if token, err := systemKeyring.Get(opts.OrgUrl); err == nil {
+			opts.ApiToken = token
+		}
+	}
+	if opts.ApiToken == "" {
+		return org.Groups
+	}
+
+	client, err := c.OktaClient()
+	if err != nil {
+		return org.Groups
+	}
+	groups, err := listAllGroups(client, nil)
+	if err != nil {
+		return org.Groups
+	}
+
+	org.Groups = []string{}
+	for _, g := range groups {
+		org.Groups = append(org.Groups, g.Profile.Name)
+	}
+	org.GroupsFetchedAt = time.Now()
+	_ = cache.save()
+	return org.Groups
+}
+
+// RecordRecentUsers saves the email IDs specified as values of user
+// flags in the arguments of a command which succeeded, so that they
+// are offered as completions of the user flags of later commands
+// run against the same organization. Failing to save them doesn't
+// affect the command, so errors are ignored.
+func (c *Command) RecordRecentUsers(args []string) {
+	users := userFlagValues(args)
+	if len(users) == 0 || c.Meta.GlobalOptions.OrgUrl == "" {
+		return
+	}
+	cache := loadCompletionCache()
+	org := cache.org(c.Meta.GlobalOptions.OrgUrl)
+	org.RecentUsers = prependRecent(org.RecentUsers, users)
+	_ = cache.save()
+}
@@ -0,0 +1,206 @@
+package command
+
+import (
+	"errors"
+	"github.com/mitchellh/cli"
+	"github.com/okta/okta-sdk-golang/okta"
+	"strings"
+	"testing"
+	"time"
+)
+
+func createTestCompletionCommand(globalOptsHelpText string) *CompletionCommand {
+	return &CompletionCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_completion_cmd"),
+	}
+}
+
+func TestCompletionCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestCompletionCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestCompletionCommand_ParseArgs(t *testing.T) {
+	t.Parallel()
+
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		c := createTestCompletionCommand("")
+		if res, err := c.ParseArgs([]string{shell}); err != nil || res != shell {
+			t.Errorf("Expected shell %s, received %q (error %v)", shell, res, err)
+		}
+	}
+
+	for _, args := range [][]string{{}, {"tcsh"}, {"bash", "zsh"}} {
+		c := createTestCompletionCommand("")
+		if _, err := c.ParseArgs(args); err == nil {
+			t.Errorf("Expected parsing of %q to fail", args)
+		}
+	}
+}
+
+func TestCompletionCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	c, out := createTestCommandWithClient("test_completion_cmd", newFakeOktaClient())
+	cmd := &CompletionCommand{Command: c}
+	if code := cmd.Run([]string{"bash"}); code != ExitOK {
+		t.Errorf("Expected exit code 0, received %d: %s", code, out)
+	}
+	if !strings.Contains(out.String(), "complete -F _okta_admin okta-admin\n") {
+		t.Errorf("Expected bash completion script, received:\n%s", out)
+	}
+}
+
+// runTestComplete runs the __complete command for the line against
+// the client and returns the candidates printed.
+func runTestComplete(t *testing.T, client *fakeOktaClient, args ...string) []string {
+	t.Helper()
+
+	c, out := createTestCommandWithClient("test_complete_cmd", client)
+	opts := c.Meta.GlobalOptions
+	c.Meta.FlagSet.StringVar(&opts.OrgUrl, "org-url", opts.OrgUrl, "")
+	c.Meta.FlagSet.StringVar(&opts.ApiToken, "api-token", opts.ApiToken, "")
+	cmd := &CompleteCommand{
+		Command: c,
+		Commands: map[string]cli.CommandFactory{
+			"list-groups": func() (cli.Command, error) {
+				return &ListGroupsCommand{Command: c}, nil
+			},
+			"assign-groups": func() (cli.Command, error) {
+				return &AssignUserGroupsCommand{Command: c}, nil
+			},
+		},
+	}
+
+	if code := cmd.Run(args); code != ExitOK {
+		t.Fatalf("Expected exit code 0 for %q, received %d: %s", args, code, out)
+	}
+	if out.Len() == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+}
+
+func TestCompleteCommand_Run(t *testing.T) {
+	// Other tests cache data for https://foo.okta.com/
+	const org = "https://complete.okta.com/"
+	client := newFakeOktaClient()
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor"), fakeGroup("00g2", "Quidditch Team")}
+
+	c := createTestCommand("", "test_complete_record")
+	c.Meta.GlobalOptions.OrgUrl = org
+	c.RecordRecentUsers([]string{"-user", "harry.potter@hogwarts.co.uk", "-groups", "Tech"})
+	c.RecordRecentUsers([]string{"-emails=hermione.granger@hogwarts.co.uk,00u3"})
+
+	testCases := []struct {
+		args       []string
+		candidates []string
+	}{
+		{[]string{"--", "okta-admin li"}, []string{"list-groups"}},
+		{[]string{"--", "okta-admin "}, []string{"assign-groups", "list-groups"}},
+		{[]string{"--", "okta-admin assign-groups -gr"}, []string{"-groups"}},
+		{[]string{"--", "okta-admin -org-url " + org + " assign-groups -groups "}, []string{`"Gryffindor"`, `"Quidditch Team"`}},
+		{[]string{"--", "okta-admin assign-groups -org-url=" + org + " -groups g", "g"}, []string{"Gryffindor"}},
+		{[]string{"--", "okta-admin assign-groups -org-url " + org + " -groups=Q", "Q"}, []string{`"Quidditch Team"`}},
+		{[]string{"--", "okta-admin assign-groups -org-url " + org + " -groups=", "="}, []string{`="Gryffindor"`, `="Quidditch Team"`}},
+		{[]string{"-shell", "zsh", "--", "okta-admin assign-groups -org-url " + org + " -groups=Q"}, []string{"-groups=Quidditch Team"}},
+		{[]string{"-shell", "fish", "--", "okta-admin assign-groups -org-url " + org + " -groups Gryffindor,"}, []string{"Gryffindor,Gryffindor", "Gryffindor,Quidditch Team"}},
+		{[]string{"--", "okta-admin assign-groups -org-url " + org + " -user "}, []string{"harry.potter@hogwarts.co.uk", "hermione.granger@hogwarts.co.uk"}},
+		{[]string{"--", "okta-admin assign-groups -org-url https://empty.okta.com/ -user h"}, nil},
+		{[]string{"--", "okta"}, nil},
+	}
+
+	for _, tc := range testCases {
+		if candidates := runTestComplete(t, client, tc.args...); !testEq(candidates, tc.candidates) {
+			t.Errorf("Expected completions of %q to be %q, received %q", tc.args, tc.candidates, candidates)
+		}
+	}
+
+	// Group names are cached once fetched
+	listed := 0
+	for _, call := range client.calls {
+		if call == "ListGroups" {
+			listed++
+		}
+	}
+	if listed != 1 {
+		t.Errorf("Expected groups to be fetched once, calls made: %v", client.calls)
+	}
+
+	// Expired names are fetched again, or offered if they can't be
+	cache := loadCompletionCache()
+	cache.org(org).GroupsFetchedAt = time.Now().Add(-completionGroupsTTL)
+	if err := cache.save(); err != nil {
+		t.Fatal(err)
+	}
+	client.errs["ListGroups"] = errors.New("connection refused")
+	candidates := runTestComplete(t, client, "--", "okta-admin assign-groups -org-url "+org+" -groups Q")
+	if !testEq(candidates, []string{`"Quidditch Team"`}) || len(client.calls) != 2 {
+		t.Errorf("Expected expired group names to be offered after failing to fetch them, received %q, calls made: %v", candidates, client.calls)
+	}
+}
+
+// countingFlag is a flag which counts how often it's set.
+type countingFlag int
+
+func (f *countingFlag) String() string {
+	return ""
+}
+
+func (f *countingFlag) Set(string) error {
+	*f++
+	return nil
+}
+
+func TestCompleteCommand_RunStoredToken(t *testing.T) {
+	const org = "https://complete-login.okta.com/"
+	client := newFakeOktaClient()
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor")}
+
+	keyring := &fakeKeyring{}
+	defer func(k keyringStore) { systemKeyring = k }(systemKeyring)
+	systemKeyring = keyring
+
+	var tokenFileSet countingFlag
+	complete := func() []string {
+		t.Helper()
+		c, out := createTestCommandWithClient("test_complete_cmd", client)
+		opts := c.Meta.GlobalOptions
+		opts.ApiToken = ""
+		c.Meta.FlagSet.StringVar(&opts.OrgUrl, "org-url", opts.OrgUrl, "")
+		c.Meta.FlagSet.Var(&tokenFileSet, "api-token-file", "")
+		cmd := &CompleteCommand{
+			Command: c,
+			Commands: map[string]cli.CommandFactory{
+				"assign-groups": func() (cli.Command, error) {
+					return &AssignUserGroupsCommand{Command: c}, nil
+				},
+			},
+		}
+		line := "okta-admin assign-groups -api-token-file token.txt -org-url " + org + " -groups G"
+		if code := cmd.Run([]string{"--", line}); code != ExitOK {
+			t.Fatalf("Expected exit code 0, received %d: %s", code, out)
+		}
+		return strings.Fields(out.String())
+	}
+
+	// Without a token, group names aren't fetched
+	if candidates := complete(); len(candidates) != 0 || len(client.calls) != 0 {
+		t.Errorf("Expected no group names without a token, received %q, calls made: %v", candidates, client.calls)
+	}
+
+	// The token stored in the keyring is used
+	keyring.available = true
+	if err := keyring.Set(org, "stored123"); err != nil {
+		t.Fatal(err)
+	}
+	if candidates := complete(); !testEq(candidates, []string{"Gryffindor"}) {
+		t.Errorf("Expected group names to be fetched with the stored token, received %q", candidates)
+	}
+
+	// Options reading the token from a file aren't applied
+	if tokenFileSet != 0 {
+		t.Errorf("Expected -api-token-file not to be applied, it was set %d time(s)", tokenFileSet)
+	}
+}
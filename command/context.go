@@ -0,0 +1,159 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// operationContext returns the context of the operation performed
+// by the command. It is created the first time this method is
+// called and is cancelled when the process receives SIGINT or
+// SIGTERM or, if the -timeout global option is set, once the
+// timeout elapses. This method should only be called after the
+// global options have been parsed.
+func (c *Command) operationContext() context.Context {
+	c.ctxMu.Lock()
+	defer c.ctxMu.Unlock()
+
+	if c.ctx != nil {
+		return c.ctx
+	}
+
+	ctx, cancelTimeout := context.Background(), context.CancelFunc(func() {})
+	if c.Meta.GlobalOptions.Timeout > 0 {
+		ctx, cancelTimeout = context.WithTimeout(ctx, c.Meta.GlobalOptions.Timeout)
+	}
+	ctx, cancel := withInterrupt(ctx)
+
+	c.ctx = ctx
+	c.cancel = func() {
+		cancel()
+		cancelTimeout()
+	}
+	return c.ctx
+}
+
+// EndOperation releases the resources held by the operation
+// context, aborting requests still in flight, so that the command
+// can perform another operation. The clients are kept, and bind
+// their requests to the context of the next operation.
+func (c *Command) EndOperation() {
+	c.ctxMu.Lock()
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.ctx, c.cancel = nil, nil
+	c.ctxMu.Unlock()
+
+	c.hintsMu.Lock()
+	c.hinted = nil
+	c.hintsMu.Unlock()
+}
+
+// HttpClient returns the HTTP client used to make all requests to
+// the Okta API, both via the SDK and directly. Every request made
+// by it is bound to the context of the operation in progress, so
+// it is aborted when the operation times out or is interrupted.
+func (c *Command) HttpClient() *http.Client {
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{
+			Transport: &contextTransport{
+				command: c,
+				base:    http.DefaultTransport,
+			},
+		}
+	}
+	return c.httpClient
+}
+
+// credentials returns the credentials used to call the Okta API
+// directly, for operations which the SDK doesn't support.
+func (c *Command) credentials() *oktaapi.Credentials {
+	return &oktaapi.Credentials{
+		OrgUrl:     c.Meta.GlobalOptions.OrgUrl,
+		ApiToken:   c.Meta.GlobalOptions.ApiToken,
+		HttpClient: c.HttpClient(),
+	}
+}
+
+// withInterrupt returns a context which is cancelled when the
+// process receives SIGINT (Ctrl-C) or SIGTERM, allowing commands
+// to stop gracefully. Only the first signal is handled, a second
+// one terminates the process as usual. The returned cancel func
+// releases the signal handler.
+func withInterrupt(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-sig:
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(sig)
+	}()
+	return ctx, cancel
+}
+
+// contextTransport binds every request it makes to the context of
+// the command's operation in progress and replaces the errors
+// caused by the context ending with ones that explain why the
+// operation was aborted.
+type contextTransport struct {
+	command *Command
+	base    http.RoundTripper
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := t.command.operationContext()
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		if ctxErr := operationError(ctx, t.command.Meta.GlobalOptions.Timeout); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+// operationErr returns an error describing why the operation
+// ended early, or nil if it hasn't.
+func (c *Command) operationErr() error {
+	c.ctxMu.Lock()
+	defer c.ctxMu.Unlock()
+
+	if c.ctx == nil {
+		return nil
+	}
+	return operationError(c.ctx, c.Meta.GlobalOptions.Timeout)
+}
+
+// operationError returns an error describing why the operation
+// whose context is ctx ended, or nil if it hasn't.
+func operationError(ctx context.Context, timeout time.Duration) error {
+	switch ctx.Err() {
+	case nil:
+		return nil
+	case context.DeadlineExceeded:
+		return &operationEndedError{msg: fmt.Sprintf("operation timed out after %s", timeout)}
+	default:
+		return &operationEndedError{msg: "operation was interrupted"}
+	}
+}
+
+// operationEndedError describes why an operation ended early.
+type operationEndedError struct {
+	msg string
+}
+
+func (e *operationEndedError) Error() string {
+	return e.msg
+}
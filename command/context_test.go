@@ -0,0 +1,97 @@
+package command
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommand_HttpClient(t *testing.T) {
+	t.Run("aborts requests once the timeout elapses", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}))
+		defer server.Close()
+		defer close(release)
+
+		c := createTestCommand("", "test_http_client_cmd")
+		c.Meta.GlobalOptions.Timeout = 50 * time.Millisecond
+		defer c.EndOperation()
+
+		start := time.Now()
+		_, err := c.HttpClient().Get(server.URL)
+		if err == nil {
+			t.Fatalf("Expected request to fail")
+		}
+		if !strings.Contains(err.Error(), "operation timed out after 50ms") {
+			t.Errorf("Expected timeout error, received %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("Expected request to be aborted after the timeout, took %s", elapsed)
+		}
+	})
+
+	t.Run("aborts requests once the operation ends", func(t *testing.T) {
+		t.Parallel()
+
+		started := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/slow" {
+				close(started)
+				<-r.Context().Done()
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		c := createTestCommand("", "test_http_client_cmd")
+		client := c.HttpClient()
+		if _, err := client.Get(server.URL); err != nil {
+			t.Fatalf("Expected request to succeed, received %v", err)
+		}
+
+		errs := make(chan error)
+		go func() {
+			_, err := client.Get(server.URL + "/slow")
+			errs <- err
+		}()
+		<-started
+		c.EndOperation()
+		if err := <-errs; err == nil || !strings.Contains(err.Error(), "operation was interrupted") {
+			t.Errorf("Expected interruption error, received %v", err)
+		}
+
+		// The client is reused by the next operation
+		defer c.EndOperation()
+		if c.HttpClient() != client {
+			t.Errorf("Expected the client to be reused by the next operation")
+		}
+		if _, err := client.Get(server.URL); err != nil {
+			t.Errorf("Expected request of the next operation to succeed, received %v", err)
+		}
+	})
+}
+
+func TestCommand_credentials(t *testing.T) {
+	t.Parallel()
+
+	c := createTestCommand("", "test_credentials_cmd")
+	defer c.EndOperation()
+
+	creds := c.credentials()
+	if creds.OrgUrl != c.Meta.GlobalOptions.OrgUrl || creds.ApiToken != c.Meta.GlobalOptions.ApiToken {
+		t.Errorf("Expected credentials to match global options, received %+v", creds)
+	}
+	if creds.HttpClient != c.HttpClient() {
+		t.Errorf("Expected credentials to use the command's HTTP client")
+	}
+}
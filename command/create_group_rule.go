@@ -0,0 +1,146 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/okta/okta-sdk-golang/okta"
+	"net/http"
+)
+
+type CreateGroupRuleCommand struct {
+	*Command
+}
+
+type CreateGroupRuleCommandConfig struct {
+	Name, Expression string
+	GroupNames       []string
+	Activate         bool
+}
+
+func (c *CreateGroupRuleCommand) Synopsis() string {
+	return "Create a rule to dynamically assign users to groups"
+}
+
+func (c *CreateGroupRuleCommand) Help() string {
+	helpText := `
+Usage: okta-admin create-group-rule [options]
+
+  Creates a group rule which assigns users matching an Okta
+  Expression Language expression to one or more groups.
+  This command assumes that the specified group(s) already exist
+  in the organization. The rule is created in inactive state
+  unless -activate is specified.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -name       Name of the rule
+  -expression Okta Expression Language expression users must
+              match, eg- 'user.team=="Platform"'
+  -groups     Comma-separated list of names or IDs of groups to
+              assign matching users to
+  -activate   Whether to activate the rule after creating it
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *CreateGroupRuleCommand) ParseArgs(args []string) (*CreateGroupRuleCommandConfig, error) {
+	var cfg CreateGroupRuleCommandConfig
+	var groupNames string
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.Name, "name", "", "")
+	flags.StringVar(&cfg.Expression, "expression", "", "")
+	flags.StringVar(&groupNames, "groups", "", "")
+	flags.BoolVar(&cfg.Activate, "activate", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+	cfg.GroupNames = c.parseListOfValues(groupNames, ParamListSep)
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "name", Required: true, Value: cfg.Name},
+		&parameter{Name: "expression", Required: true, Value: cfg.Expression},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+	)
+	if err == nil && len(cfg.GroupNames) == 0 {
+		err = errors.New("groups is required")
+	}
+	return &cfg, err
+}
+
+func (c *CreateGroupRuleCommand) Run(args []string) int {
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+
+	gids := make([]string, 0, len(cfg.GroupNames))
+	seen := map[string]bool{}
+	for _, n := range cfg.GroupNames {
+		g, err := resolveGroup(client, n)
+		var nfErr *notFoundError
+		switch {
+		case errors.As(err, &nfErr):
+			c.Logger.Println(err)
+			return ExitNotFound
+		case err != nil:
+			c.logError(fmt.Sprintf("Failed to find group %s", n), err)
+			return exitCode(err)
+		case !seen[g.Id]:
+			// A group may be specified both by name and ID
+			seen[g.Id] = true
+			gids = append(gids, g.Id)
+		}
+	}
+
+	rule := okta.GroupRule{
+		Type: groupRuleType,
+		Name: cfg.Name,
+		Conditions: &okta.GroupRuleConditions{
+			Expression: &okta.GroupRuleExpression{Type: groupRuleExpressionType, Value: cfg.Expression},
+		},
+		Actions: &okta.GroupRuleAction{
+			AssignUserToGroups: &okta.GroupRuleGroupAssignment{GroupIds: gids},
+		},
+	}
+	created, resp, err := client.CreateGroupRule(rule)
+	if err != nil {
+		c.logError("Failed to create group rule", err)
+		return exitCode(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		c.Logger.Printf("Failed to create group rule: %s\n", resp.Status)
+		return ExitFailure
+	}
+	c.Logger.Printf("ID: %s\n", created.Id)
+
+	if cfg.Activate {
+		resp, err := client.ActivateGroupRule(created.Id)
+		if err != nil {
+			c.logError("Failed to activate group rule", err)
+			return exitCode(err)
+		}
+		if resp.StatusCode != http.StatusNoContent {
+			c.Logger.Printf("Failed to activate group rule: %s\n", resp.Status)
+			return ExitFailure
+		}
+		c.Logger.Println("Rule activated")
+	}
+
+	return ExitOK
+}
@@ -0,0 +1,108 @@
+package command
+
+import (
+	"github.com/okta/okta-sdk-golang/okta"
+	"testing"
+)
+
+func createTestCreateGroupRuleCommand(globalOptsHelpText string) *CreateGroupRuleCommand {
+	return &CreateGroupRuleCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_create_group_rule_cmd"),
+	}
+}
+
+func TestCreateGroupRuleCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestCreateGroupRuleCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestCreateGroupRuleCommand_ParseArgs(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		var groups = []string{"Platform", "engineering"}
+		c := createTestCreateGroupRuleCommand("")
+		args := []string{
+			"-name", "Platform engineers",
+			"-expression", `user.team=="Platform"`,
+			"-groups", "Platform, engineering",
+			"-activate",
+		}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.Name != args[1] {
+			t.Errorf("Expected name to be %s, received %s", args[1], cfg.Name)
+		}
+		if cfg.Expression != args[3] {
+			t.Errorf("Expected expression to be %s, received %s", args[3], cfg.Expression)
+		}
+		if !testEq(cfg.GroupNames, groups) {
+			t.Errorf("Expected group names to be %v, received %v", groups, cfg.GroupNames)
+		}
+		if !cfg.Activate {
+			t.Errorf("Expected -activate flag to be set")
+		}
+	})
+
+	t.Run("missing required options", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := [][]string{
+			{"-expression", `user.team=="Platform"`, "-groups", "Platform"},
+			{"-name", "Platform engineers", "-groups", "Platform"},
+			{"-name", "Platform engineers", "-expression", `user.team=="Platform"`},
+		}
+		for _, tc := range testCases {
+			c := createTestCreateGroupRuleCommand("")
+			if _, err := c.ParseArgs(tc); err == nil {
+				t.Errorf("Expected args %v to be invalid", tc)
+			}
+		}
+	})
+}
+
+func TestCreateGroupRuleCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.groups = []*okta.Group{
+		fakeGroup("00gabcdefghijklmnop1", "Platform"),
+		fakeGroup("00gabcdefghijklmnop2", "Engineering"),
+	}
+
+	c, out := createTestCommandWithClient("test_create_group_rule_cmd", client)
+	args := []string{
+		"-name", "Platform engineers",
+		"-expression", `user.team=="Platform"`,
+		"-groups", "platform,00gabcdefghijklmnop2,Platform",
+		"-activate",
+	}
+	if code := (&CreateGroupRuleCommand{Command: c}).Run(args); code != ExitOK {
+		t.Fatalf("Expected exit code %d, received %d: %s", ExitOK, code, out)
+	}
+
+	expected := []string{
+		"ListGroups platform",
+		"GetGroup 00gabcdefghijklmnop2",
+		"ListGroups Platform",
+		"CreateGroupRule Platform engineers 00gabcdefghijklmnop1,00gabcdefghijklmnop2",
+		"ActivateGroupRule 0pr1",
+	}
+	if !testEq(client.calls, expected) {
+		t.Errorf("Expected calls %q, received %q", expected, client.calls)
+	}
+	if client.rules[0].Status != groupRuleStatusActive {
+		t.Errorf("Expected the rule to be active, received %s", client.rules[0].Status)
+	}
+
+	c, out = createTestCommandWithClient("test_create_group_rule_cmd", client)
+	args = []string{"-name", "Slytherins", "-expression", `user.house=="Slytherin"`, "-groups", "Slytherin"}
+	if code := (&CreateGroupRuleCommand{Command: c}).Run(args); code != ExitNotFound {
+		t.Errorf("Expected exit code %d for a missing group, received %d: %s", ExitNotFound, code, out)
+	}
+}
@@ -67,14 +67,14 @@ func (c *CreateUserCommand) ParseArgs(args []string) (*CreateUserCommandConfig,
 func (c *CreateUserCommand) Run(args []string) int {
 	cfg, err := c.ParseArgs(args)
 	if err != nil {
-		c.Logger.Printf("Failed to parse arguments: %v\n", err)
-		return 1
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
 	}
 
 	client, err := c.OktaClient()
 	if err != nil {
-		c.Logger.Printf("Failed to initialize Okta client: %v\n", err)
-		return 1
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
 	}
 
 	queries := query.NewQueryParams(query.WithActivate(true))
@@ -85,14 +85,14 @@ func (c *CreateUserCommand) Run(args []string) int {
 		"firstName": cfg.FirstName,
 		"lastName":  cfg.LastName,
 	}
-	user, resp, err := client.User.CreateUser(okta.User{Profile: &profile}, queries)
+	user, resp, err := client.CreateUser(okta.User{Profile: &profile}, queries)
 	if err != nil {
-		c.Logger.Printf("Failed to create user: %v\n", err)
-		return 1
+		c.logError("Failed to create user", err)
+		return exitCode(err)
 	}
 	if resp.StatusCode != http.StatusOK {
 		c.Logger.Printf("Failed to create user: %s\n", resp.Status)
-		return 1
+		return ExitFailure
 	}
 
 	c.Logger.Printf("ID: %s\n", user.Id)
@@ -45,3 +45,24 @@ func TestCreateUserCommand_ParseArgs(t *testing.T) {
 		t.Errorf("Expected email id to be %s, received %s", args[1], cfg.EmailID)
 	}
 }
+
+func TestCreateUserCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.createdUserId = "00u1"
+	c, out := createTestCommandWithClient("test_create_user_cmd", client)
+	cmd := &CreateUserCommand{Command: c}
+
+	args := []string{"-email", "harry.potter@hogwarts.co.uk", "-team", "Seekers", "-fname", "Harry", "-lname", "Potter"}
+	if code := cmd.Run(args); code != 0 {
+		t.Fatalf("Expected exit code 0, received %d: %s", code, out)
+	}
+	if out.String() != "ID: 00u1\n" {
+		t.Errorf("Expected the new user's ID to be printed, received %q", out)
+	}
+	profile := *client.users[0].Profile
+	if profile["login"] != "harry.potter@hogwarts.co.uk" || profile["team"] != "Seekers" || profile["firstName"] != "Harry" {
+		t.Errorf("Expected user to be created with the supplied profile, received %v", profile)
+	}
+}
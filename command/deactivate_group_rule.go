@@ -0,0 +1,94 @@
+package command
+
+import (
+	"net/http"
+)
+
+type DeactivateGroupRuleCommand struct {
+	*Command
+}
+
+type DeactivateGroupRuleCommandConfig struct {
+	RuleID, RuleName string
+}
+
+func (c *DeactivateGroupRuleCommand) Synopsis() string {
+	return "Deactivate a group rule"
+}
+
+func (c *DeactivateGroupRuleCommand) Help() string {
+	helpText := `
+Usage: okta-admin deactivate-group-rule [options]
+
+  Deactivates a group rule. Users already assigned to groups
+  by the rule keep their memberships, but no new assignments
+  are made until the rule is activated again.
+  Either the ID or the name of the rule must be specified.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -id   ID of the group rule
+  -name Name of the group rule
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *DeactivateGroupRuleCommand) ParseArgs(args []string) (*DeactivateGroupRuleCommandConfig, error) {
+	var cfg DeactivateGroupRuleCommandConfig
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.RuleID, "id", "", "")
+	flags.StringVar(&cfg.RuleName, "name", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+	if err := validateGroupRuleIdentifier(cfg.RuleID, cfg.RuleName); err != nil {
+		return &cfg, err
+	}
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+	)
+	return &cfg, err
+}
+
+func (c *DeactivateGroupRuleCommand) Run(args []string) int {
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+
+	rule, err := findGroupRule(client, cfg.RuleID, cfg.RuleName)
+	if err != nil {
+		c.logError("Failed to fetch group rule", err)
+		return exitCode(err)
+	}
+
+	resp, err := client.DeactivateGroupRule(rule.Id)
+	if err != nil {
+		c.logError("Failed to deactivate group rule", err)
+		return exitCode(err)
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		c.Logger.Printf("Failed to deactivate group rule: %s\n", resp.Status)
+		return ExitFailure
+	}
+
+	c.Logger.Printf("Successfully deactivated %s (ID: %s)\n", rule.Name, rule.Id)
+	return 0
+}
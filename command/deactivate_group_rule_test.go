@@ -0,0 +1,83 @@
+package command
+
+import (
+	"github.com/okta/okta-sdk-golang/okta"
+	"testing"
+)
+
+func createTestDeactivateGroupRuleCommand(globalOptsHelpText string) *DeactivateGroupRuleCommand {
+	return &DeactivateGroupRuleCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_deactivate_group_rule_cmd"),
+	}
+}
+
+func TestDeactivateGroupRuleCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestDeactivateGroupRuleCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestDeactivateGroupRuleCommand_ParseArgs(t *testing.T) {
+	t.Run("with name", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestDeactivateGroupRuleCommand("")
+		args := []string{"-name", "Platform engineers"}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.RuleName != args[1] {
+			t.Errorf("Expected rule name to be %s, received %s", args[1], cfg.RuleName)
+		}
+	})
+
+	t.Run("with id", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestDeactivateGroupRuleCommand("")
+		args := []string{"-id", "0pr3f7zMZZHPgUoWO0g4"}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.RuleID != args[1] {
+			t.Errorf("Expected rule id to be %s, received %s", args[1], cfg.RuleID)
+		}
+	})
+
+	t.Run("without or with both identifiers", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := [][]string{
+			{},
+			{"-id", "0pr3f7zMZZHPgUoWO0g4", "-name", "Platform engineers"},
+		}
+		for _, tc := range testCases {
+			c := createTestDeactivateGroupRuleCommand("")
+			if _, err := c.ParseArgs(tc); err == nil {
+				t.Errorf("Expected args %v to be invalid", tc)
+			}
+		}
+	})
+}
+
+func TestDeactivateGroupRuleCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.rules = []*okta.GroupRule{{Id: "0pr1", Name: "Seekers", Status: groupRuleStatusActive}}
+	c, out := createTestCommandWithClient("test_deactivate_group_rule_cmd", client)
+	cmd := &DeactivateGroupRuleCommand{Command: c}
+
+	if code := cmd.Run([]string{"-id", "0pr1"}); code != 0 {
+		t.Fatalf("Expected exit code 0, received %d: %s", code, out)
+	}
+	if client.rules[0].Status != "INACTIVE" {
+		t.Errorf("Expected rule to be deactivated, status is %s", client.rules[0].Status)
+	}
+}
@@ -1,7 +1,8 @@
 package command
 
 import (
-	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"errors"
+	"fmt"
 	"net/http"
 )
 
@@ -10,7 +11,7 @@ type DeactivateUserCommand struct {
 }
 
 type DeactivateUserCommandConfig struct {
-	EmailID string
+	userBatchConfig
 }
 
 func (c *DeactivateUserCommand) Synopsis() string {
@@ -23,10 +24,23 @@ Usage: okta-admin deactivate-user [options]
 
   Deactivates an organization member but doesn't delete
   their account.
+
+  Multiple members can be deactivated at once by combining
+  -user, -users and -file. A summary is printed at the end and
+  the command fails if any member couldn't be deactivated.
 {{.GlobalOptionsHelpText}}
 Options:
 
-  -email Email ID of the user to deactivate
+  -user        ID, login or email ID of the user to deactivate.
+               -email is accepted as an alias.
+  -users       Comma-separated list of IDs, logins or email IDs
+               of users to deactivate. -emails is accepted as an
+               alias.
+  -file        File containing one ID, login or email ID per
+               line. Specify - (or pass - as the argument) to read
+               from standard input.
+  -parallelism Maximum number of users to deactivate concurrently
+               (Default: 5)
 `
 
 	return c.Command.prepareHelpMessage(
@@ -39,58 +53,38 @@ Options:
 
 func (c *DeactivateUserCommand) ParseArgs(args []string) (*DeactivateUserCommandConfig, error) {
 	var cfg DeactivateUserCommandConfig
-
-	flags := c.Meta.FlagSet
-	flags.StringVar(&cfg.EmailID, "email", "", "")
-
-	if err := flags.Parse(args); err != nil {
-		return &cfg, err
-	}
-	err := c.Command.validateParameters(
-		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
-		&parameter{Name: "email", Required: true, Value: cfg.EmailID, ValidationFunc: ValidateEmailID},
-		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
-	)
+	err := c.Command.parseUserBatchArgs(args, &cfg.userBatchConfig)
 	return &cfg, err
 }
 
 func (c *DeactivateUserCommand) Run(args []string) int {
 	cfg, err := c.ParseArgs(args)
 	if err != nil {
-		c.Logger.Printf("Failed to parse arguments: %v\n", err)
-		return 1
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
 	}
 
 	client, err := c.OktaClient()
 	if err != nil {
-		c.Logger.Printf("Failed to initialize Okta client: %v\n", err)
-		return 1
-	}
-
-	// Fetch user ID
-	user, _, err := oktaapi.GetUserByEmail(
-		&oktaapi.Credentials{
-			OrgUrl:   c.Meta.GlobalOptions.OrgUrl,
-			ApiToken: c.Meta.GlobalOptions.ApiToken,
-		},
-		cfg.EmailID,
-	)
-	if err != nil {
-		c.Logger.Printf("Failed to resolve user ID: %v\n", err)
-		return 1
-	}
-
-	// Deactivate user
-	resp, err := client.User.DeactivateUser(user["id"].(string), nil)
-	if err != nil {
-		c.Logger.Printf("Failed to deactivate member: %v\n", err)
-		return 1
-	}
-	if resp.StatusCode != http.StatusOK {
-		c.Logger.Printf("Failed to deactivate member: %s\n", resp.Status)
-		return 1
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
 	}
 
-	c.Logger.Printf("Successfully deactivated %s (ID: %s)\n", cfg.EmailID, user["id"])
-	return 0
+	return c.runUserBatch(&cfg.userBatchConfig, "deactivate", func(user string) (string, error) {
+		// Fetch user ID
+		uid, err := resolveUser(client, user)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve user ID: %w", err)
+		}
+
+		// Deactivate user
+		resp, err := client.DeactivateUser(uid, nil)
+		if err != nil {
+			return "", err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return "", errors.New(resp.Status)
+		}
+		return fmt.Sprintf("Successfully deactivated %s (ID: %s)", user, uid), nil
+	})
 }
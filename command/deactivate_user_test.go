@@ -1,6 +1,8 @@
 package command
 
 import (
+	"github.com/okta/okta-sdk-golang/okta"
+	"strings"
 	"testing"
 )
 
@@ -29,7 +31,32 @@ func TestDeactivateUserCommand_ParseArgs(t *testing.T) {
 		t.Fatalf("Failed to parse arguments: %v", err)
 	}
 
-	if cfg.EmailID != args[1] {
-		t.Errorf("Expected email id to be %s, received %s", args[1], cfg.EmailID)
+	if cfg.User != args[1] {
+		t.Errorf("Expected user to be %s, received %s", args[1], cfg.User)
+	}
+}
+
+func TestDeactivateUserCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.users = []*okta.User{
+		fakeUser("00u1", "ron.weasley@hogwarts.co.uk", "ACTIVE"),
+		fakeUser("00u2", "hermione.granger@hogwarts.co.uk", "ACTIVE"),
+	}
+	c, out := createTestCommandWithClient("test_deactivate_user_cmd", client)
+	cmd := &DeactivateUserCommand{Command: c}
+
+	args := []string{"-emails", "ron.weasley@hogwarts.co.uk,draco.malfoy@hogwarts.co.uk,hermione.granger@hogwarts.co.uk"}
+	if code := cmd.Run(args); code != ExitPartialFailure {
+		t.Errorf("Expected exit code %d since a member doesn't exist, received %d", ExitPartialFailure, code)
+	}
+	for _, u := range client.users {
+		if u.Status != "DEPROVISIONED" {
+			t.Errorf("Expected %s to be deactivated, status is %s", u.Id, u.Status)
+		}
+	}
+	if !strings.Contains(out.String(), "2 succeeded, 1 failed") {
+		t.Errorf("Expected summary of the batch, received %q", out)
 	}
 }
@@ -0,0 +1,110 @@
+package command
+
+import (
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"net/http"
+)
+
+type DeleteGroupRuleCommand struct {
+	*Command
+}
+
+type DeleteGroupRuleCommandConfig struct {
+	RuleID, RuleName string
+	RemoveUsers      bool
+}
+
+func (c *DeleteGroupRuleCommand) Synopsis() string {
+	return "Delete a group rule"
+}
+
+func (c *DeleteGroupRuleCommand) Help() string {
+	helpText := `
+Usage: okta-admin delete-group-rule [options]
+
+  Deletes a group rule. Okta only allows inactive rules to be
+  deleted, so an active rule is deactivated first.
+  Either the ID or the name of the rule must be specified.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -id           ID of the group rule
+  -name         Name of the group rule
+  -remove-users Whether to also remove users from the groups
+                they were assigned to by the rule
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *DeleteGroupRuleCommand) ParseArgs(args []string) (*DeleteGroupRuleCommandConfig, error) {
+	var cfg DeleteGroupRuleCommandConfig
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.RuleID, "id", "", "")
+	flags.StringVar(&cfg.RuleName, "name", "", "")
+	flags.BoolVar(&cfg.RemoveUsers, "remove-users", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+	if err := validateGroupRuleIdentifier(cfg.RuleID, cfg.RuleName); err != nil {
+		return &cfg, err
+	}
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+	)
+	return &cfg, err
+}
+
+func (c *DeleteGroupRuleCommand) Run(args []string) int {
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+
+	rule, err := findGroupRule(client, cfg.RuleID, cfg.RuleName)
+	if err != nil {
+		c.logError("Failed to fetch group rule", err)
+		return exitCode(err)
+	}
+
+	if rule.Status == groupRuleStatusActive {
+		resp, err := client.DeactivateGroupRule(rule.Id)
+		if err != nil {
+			c.logError("Failed to deactivate group rule", err)
+			return exitCode(err)
+		}
+		if resp.StatusCode != http.StatusNoContent {
+			c.Logger.Printf("Failed to deactivate group rule: %s\n", resp.Status)
+			return ExitFailure
+		}
+	}
+
+	resp, err := client.DeleteGroupRule(rule.Id, query.NewQueryParams(query.WithRemoveUsers(cfg.RemoveUsers)))
+	if err != nil {
+		c.logError("Failed to delete group rule", err)
+		return exitCode(err)
+	}
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNoContent {
+		c.Logger.Printf("Failed to delete group rule: %s\n", resp.Status)
+		return ExitFailure
+	}
+
+	c.Logger.Printf("Successfully deleted %s (ID: %s)\n", rule.Name, rule.Id)
+	return 0
+}
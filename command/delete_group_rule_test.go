@@ -0,0 +1,105 @@
+package command
+
+import (
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta"
+	"strings"
+	"testing"
+)
+
+func createTestDeleteGroupRuleCommand(globalOptsHelpText string) *DeleteGroupRuleCommand {
+	return &DeleteGroupRuleCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_delete_group_rule_cmd"),
+	}
+}
+
+func TestDeleteGroupRuleCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestDeleteGroupRuleCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestDeleteGroupRuleCommand_ParseArgs(t *testing.T) {
+	t.Run("with name", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestDeleteGroupRuleCommand("")
+		args := []string{"-name", "Platform engineers", "-remove-users"}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.RuleName != args[1] {
+			t.Errorf("Expected rule name to be %s, received %s", args[1], cfg.RuleName)
+		}
+		if !cfg.RemoveUsers {
+			t.Errorf("Expected -remove-users flag to be set")
+		}
+	})
+
+	t.Run("with id", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestDeleteGroupRuleCommand("")
+		args := []string{"-id", "0pr3f7zMZZHPgUoWO0g4"}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.RuleID != args[1] {
+			t.Errorf("Expected rule id to be %s, received %s", args[1], cfg.RuleID)
+		}
+	})
+
+	t.Run("without or with both identifiers", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := [][]string{
+			{},
+			{"-id", "0pr3f7zMZZHPgUoWO0g4", "-name", "Platform engineers"},
+		}
+		for _, tc := range testCases {
+			c := createTestDeleteGroupRuleCommand("")
+			if _, err := c.ParseArgs(tc); err == nil {
+				t.Errorf("Expected args %v to be invalid", tc)
+			}
+		}
+	})
+}
+
+func TestDeleteGroupRuleCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.rules = []*okta.GroupRule{
+		fakeGroupRule("0pr1", "Seekers", groupRuleStatusActive, `user.title == "Seeker"`),
+		fakeGroupRule("0pr2", "Keepers", "INACTIVE", `user.title == "Keeper"`),
+	}
+
+	// Active rules are deactivated before they're deleted
+	c, out := createTestCommandWithClient("test_delete_group_rule_cmd", client)
+	if code := (&DeleteGroupRuleCommand{Command: c}).Run([]string{"-name", "Seekers", "-remove-users"}); code != ExitOK {
+		t.Fatalf("Expected exit code %d, received %d: %s", ExitOK, code, out)
+	}
+	if out.String() != "Successfully deleted Seekers (ID: 0pr1)\n" {
+		t.Errorf("Unexpected output %q", out)
+	}
+	expectedCalls := []string{"ListGroupRules", "DeactivateGroupRule 0pr1", "DeleteGroupRule 0pr1 ?removeUsers=true"}
+	if !testEq(client.calls, expectedCalls) {
+		t.Errorf("Expected calls %v, received %v", expectedCalls, client.calls)
+	}
+	if len(client.rules) != 1 || client.rules[0].Id != "0pr2" {
+		t.Errorf("Expected only Keepers to remain, received %v", client.rules)
+	}
+
+	client.errs["DeleteGroupRule"] = &oktaapi.ApiError{StatusCode: 403, Summary: "You do not have permission"}
+	c, out = createTestCommandWithClient("test_delete_group_rule_cmd", client)
+	code := (&DeleteGroupRuleCommand{Command: c}).Run([]string{"-id", "0pr2"})
+	if code != ExitAuth || !strings.Contains(out.String(), "Failed to delete group rule") {
+		t.Errorf("Expected exit code %d, received %d: %s", ExitAuth, code, out)
+	}
+}
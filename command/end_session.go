@@ -0,0 +1,82 @@
+package command
+
+import (
+	"net/http"
+)
+
+type EndSessionCommand struct {
+	*Command
+}
+
+type EndSessionCommandConfig struct {
+	SessionID string
+}
+
+func (c *EndSessionCommand) Synopsis() string {
+	return "End a session"
+}
+
+func (c *EndSessionCommand) Help() string {
+	helpText := `
+Usage: okta-admin end-session [options]
+
+  Ends a single session, signing its user out of Okta on the
+  device the session belongs to. Use end-user-sessions to end
+  all sessions of a user instead.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -id ID of the session
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *EndSessionCommand) ParseArgs(args []string) (*EndSessionCommandConfig, error) {
+	var cfg EndSessionCommandConfig
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.SessionID, "id", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+	err := c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "id", Required: true, Value: cfg.SessionID},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+	)
+	return &cfg, err
+}
+
+func (c *EndSessionCommand) Run(args []string) int {
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+
+	resp, err := client.EndSession(cfg.SessionID)
+	if err != nil {
+		c.logError("Failed to end session", err)
+		return exitCode(err)
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		c.Logger.Printf("Failed to end session: %s\n", resp.Status)
+		return ExitFailure
+	}
+
+	c.Logger.Printf("Session %s has been ended\n", cfg.SessionID)
+	return 0
+}
@@ -0,0 +1,64 @@
+package command
+
+import (
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"testing"
+)
+
+func createTestEndSessionCommand(globalOptsHelpText string) *EndSessionCommand {
+	return &EndSessionCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_end_session_cmd"),
+	}
+}
+
+func TestEndSessionCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestEndSessionCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestEndSessionCommand_ParseArgs(t *testing.T) {
+	t.Run("with id", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestEndSessionCommand("")
+		args := []string{"-id", "102SmBFaL6hQ1aFSI0xmo1Ouw"}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.SessionID != args[1] {
+			t.Errorf("Expected session id to be %s, received %s", args[1], cfg.SessionID)
+		}
+	})
+
+	t.Run("without id", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestEndSessionCommand("")
+		if _, err := c.ParseArgs([]string{}); err == nil {
+			t.Errorf("Expected error when session id is not specified")
+		}
+	})
+}
+
+func TestEndSessionCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.sessions["102abc"] = &oktaapi.Session{Id: "102abc"}
+	c, out := createTestCommandWithClient("test_end_session_cmd", client)
+	cmd := &EndSessionCommand{Command: c}
+
+	if code := cmd.Run([]string{"-id", "102abc"}); code != 0 {
+		t.Fatalf("Expected exit code 0, received %d: %s", code, out)
+	}
+	if _, ok := client.sessions["102abc"]; ok {
+		t.Errorf("Expected session to be ended")
+	}
+	if out.String() != "Session 102abc has been ended\n" {
+		t.Errorf("Unexpected output %q", out)
+	}
+}
@@ -0,0 +1,102 @@
+package command
+
+import (
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"net/http"
+)
+
+type EndUserSessionsCommand struct {
+	*Command
+}
+
+type EndUserSessionsCommandConfig struct {
+	User              string
+	RevokeOauthTokens bool
+}
+
+func (c *EndUserSessionsCommand) Synopsis() string {
+	return "End all sessions of an organization member"
+}
+
+func (c *EndUserSessionsCommand) Help() string {
+	helpText := `
+Usage: okta-admin end-user-sessions [options]
+
+  Ends all active sessions of an organization member, signing
+  them out of Okta on every device. Applications the member is
+  already signed into may keep them signed in until their own
+  sessions expire, unless their OAuth tokens are revoked too.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -user          ID, login or email ID of the organization member.
+                 -email is accepted as an alias.
+  -revoke-tokens Whether to also revoke all OAuth access and
+                 refresh tokens issued to the member
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *EndUserSessionsCommand) ParseArgs(args []string) (*EndUserSessionsCommandConfig, error) {
+	var cfg EndUserSessionsCommandConfig
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.User, "user", "", "")
+	flags.StringVar(&cfg.User, "email", "", "")
+	flags.BoolVar(&cfg.RevokeOauthTokens, "revoke-tokens", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+	err := c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+		&parameter{Name: "user", Required: true, Value: cfg.User, ValidationFunc: ValidateUserIdentifier},
+	)
+	return &cfg, err
+}
+
+func (c *EndUserSessionsCommand) Run(args []string) int {
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+
+	// Fetch user ID
+	uid, err := resolveUser(client, cfg.User)
+	if err != nil {
+		c.logError("Failed to resolve user ID", err)
+		return exitCode(err)
+	}
+
+	resp, err := client.EndAllUserSessions(
+		uid, query.NewQueryParams(query.WithOauthTokens(cfg.RevokeOauthTokens)))
+	if err != nil {
+		c.logError("Failed to end member's sessions", err)
+		return exitCode(err)
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		c.Logger.Printf("Failed to end member's sessions: %s\n", resp.Status)
+		return ExitFailure
+	}
+
+	if cfg.RevokeOauthTokens {
+		c.Logger.Printf("All sessions of %s have been ended and their OAuth tokens revoked\n", cfg.User)
+	} else {
+		c.Logger.Printf("All sessions of %s have been ended\n", cfg.User)
+	}
+	return 0
+}
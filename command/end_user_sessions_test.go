@@ -0,0 +1,59 @@
+package command
+
+import (
+	"github.com/okta/okta-sdk-golang/okta"
+	"testing"
+)
+
+func createTestEndUserSessionsCommand(globalOptsHelpText string) *EndUserSessionsCommand {
+	return &EndUserSessionsCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_end_user_sessions_cmd"),
+	}
+}
+
+func TestEndUserSessionsCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestEndUserSessionsCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestEndUserSessionsCommand_ParseArgs(t *testing.T) {
+	t.Parallel()
+
+	c := createTestEndUserSessionsCommand("")
+	args := []string{
+		"-email", "harry.potter@hogwarts.co.uk",
+		"-revoke-tokens",
+	}
+
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		t.Fatalf("Failed to parse arguments: %v", err)
+	}
+
+	if cfg.User != args[1] {
+		t.Errorf("Expected user to be %s, received %s", args[1], cfg.User)
+	}
+	if !cfg.RevokeOauthTokens {
+		t.Errorf("Expected -revoke-tokens flag to be set")
+	}
+}
+
+func TestEndUserSessionsCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.users = []*okta.User{fakeUser("00u1", "harry.potter@hogwarts.co.uk", "ACTIVE")}
+	c, out := createTestCommandWithClient("test_end_user_sessions_cmd", client)
+	cmd := &EndUserSessionsCommand{Command: c}
+
+	if code := cmd.Run([]string{"-email", "harry.potter@hogwarts.co.uk", "-revoke-tokens"}); code != 0 {
+		t.Fatalf("Expected exit code 0, received %d: %s", code, out)
+	}
+	if out.String() != "All sessions of harry.potter@hogwarts.co.uk have been ended and their OAuth tokens revoked\n" {
+		t.Errorf("Unexpected output %q", out)
+	}
+	if call := client.calls[len(client.calls)-1]; call != "EndAllUserSessions 00u1 ?oauthTokens=true" {
+		t.Errorf("Expected sessions and tokens of the user to be revoked, received call %q", call)
+	}
+}
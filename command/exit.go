@@ -0,0 +1,128 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Exit codes returned by commands, which allow scripts to tell
+// apart the reasons a command failed. They are documented in the
+// README and must not change.
+const (
+	// ExitOK is returned when the command succeeded.
+	ExitOK = 0
+	// ExitFailure is returned for failures without a more
+	// specific exit code.
+	ExitFailure = 1
+	// ExitUsage is returned when the arguments are invalid.
+	ExitUsage = 2
+	// ExitAuth is returned when the API token is invalid or its
+	// administrator lacks permission for the operation.
+	ExitAuth = 3
+	// ExitNotFound is returned when a user, group or other
+	// resource specified doesn't exist.
+	ExitNotFound = 4
+	// ExitConflict is returned when the resource already exists
+	// or its state doesn't allow the operation.
+	ExitConflict = 5
+	// ExitPartialFailure is returned when a command operating on
+	// several users, groups or policies failed for some of them,
+	// or failed for all of them for different reasons.
+	ExitPartialFailure = 6
+	// ExitRateLimited is returned when the organization's API
+	// rate limit was exceeded.
+	ExitRateLimited = 7
+	// ExitNetwork is returned when Okta couldn't be reached.
+	ExitNetwork = 8
+)
+
+// notFoundError is returned when a resource specified by the user,
+// eg- a group identified by its name, doesn't exist.
+type notFoundError struct {
+	msg string
+}
+
+func (e *notFoundError) Error() string {
+	return e.msg
+}
+
+// newNotFoundError creates a notFoundError with the formatted
+// message.
+func newNotFoundError(format string, a ...interface{}) error {
+	return &notFoundError{msg: fmt.Sprintf(format, a...)}
+}
+
+// exitCode returns the exit code describing the failure caused by
+// err, or ExitOK if err is nil.
+func exitCode(err error) int {
+	var (
+		apiErr *oktaapi.ApiError
+		nfErr  *notFoundError
+		opErr  *operationEndedError
+		netErr net.Error
+	)
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &nfErr):
+		return ExitNotFound
+	case errors.As(err, &apiErr):
+		return apiErrorExitCode(apiErr)
+	// Errors caused by the operation ending early are wrapped in
+	// url.Error, which is a net.Error too.
+	case errors.As(err, &opErr):
+		return ExitFailure
+	case errors.As(err, &netErr):
+		return ExitNetwork
+	}
+	return ExitFailure
+}
+
+// apiErrorExitCode returns the exit code describing an error
+// response of the Okta API.
+func apiErrorExitCode(e *oktaapi.ApiError) int {
+	// Okta reports duplicates as validation errors
+	causes := strings.Join(e.Causes, "; ")
+
+	// Okta rejects operations not allowed in a user's status with
+	// 403, so conflicts are checked first.
+	switch {
+	case e.StatusCode == http.StatusConflict,
+		e.Code == oktaapi.ErrorCodeAlreadyActive,
+		e.Code == oktaapi.ErrorCodeInvalidStatus,
+		e.Code == oktaapi.ErrorCodeValidation && strings.Contains(causes, "already exists"):
+		return ExitConflict
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return ExitAuth
+	case e.StatusCode == http.StatusNotFound:
+		return ExitNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ExitRateLimited
+	}
+	return ExitFailure
+}
+
+// fanOutExitCode returns the exit code of a command which operated
+// on n users, groups or policies and failed for those whose errors
+// are in errs. If it failed for all of them for the same reason,
+// that reason's exit code is returned.
+func fanOutExitCode(n int, errs []error) int {
+	if len(errs) == 0 {
+		return ExitOK
+	}
+	code := exitCode(errs[0])
+	if len(errs) < n {
+		return ExitPartialFailure
+	}
+	for _, err := range errs[1:] {
+		if exitCode(err) != code {
+			return ExitPartialFailure
+		}
+	}
+	return code
+}
@@ -0,0 +1,68 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		err      error
+		expected int
+	}{
+		{nil, ExitOK},
+		{errors.New("failed to read users"), ExitFailure},
+		{newNotFoundError("Slytherin does not exist"), ExitNotFound},
+		{fmt.Errorf("failed to add member: %w", &oktaapi.ApiError{StatusCode: http.StatusUnauthorized, Code: oktaapi.ErrorCodeInvalidToken}), ExitAuth},
+		{&oktaapi.ApiError{StatusCode: http.StatusForbidden, Code: oktaapi.ErrorCodeForbidden}, ExitAuth},
+		{&oktaapi.ApiError{StatusCode: http.StatusNotFound, Code: oktaapi.ErrorCodeNotFound}, ExitNotFound},
+		{&oktaapi.ApiError{StatusCode: http.StatusConflict}, ExitConflict},
+		{&oktaapi.ApiError{StatusCode: http.StatusBadRequest, Code: oktaapi.ErrorCodeValidation,
+			Causes: []string{"login: An object with this field already exists in the current organization"}}, ExitConflict},
+		{&oktaapi.ApiError{StatusCode: http.StatusBadRequest, Code: oktaapi.ErrorCodeValidation,
+			Causes: []string{"email: Does not match required pattern"}}, ExitFailure},
+		{&oktaapi.ApiError{StatusCode: http.StatusForbidden, Code: oktaapi.ErrorCodeInvalidStatus}, ExitConflict},
+		{&oktaapi.ApiError{StatusCode: http.StatusTooManyRequests, Code: oktaapi.ErrorCodeRateLimited}, ExitRateLimited},
+		{&oktaapi.ApiError{StatusCode: http.StatusInternalServerError}, ExitFailure},
+		{&url.Error{Op: "Get", URL: "https://foo.okta.com/api/v1/users", Err: syscall.ECONNREFUSED}, ExitNetwork},
+		{&url.Error{Op: "Get", URL: "https://foo.okta.com/api/v1/users", Err: &operationEndedError{msg: "operation was interrupted"}}, ExitFailure},
+	}
+
+	for _, tc := range testCases {
+		if code := exitCode(tc.err); code != tc.expected {
+			t.Errorf("Expected exit code %d for error %v, received %d", tc.expected, tc.err, code)
+		}
+	}
+}
+
+func TestFanOutExitCode(t *testing.T) {
+	t.Parallel()
+
+	notFound := newNotFoundError("Slytherin does not exist")
+	rateLimited := &oktaapi.ApiError{StatusCode: http.StatusTooManyRequests}
+
+	testCases := []struct {
+		n        int
+		errs     []error
+		expected int
+	}{
+		{3, nil, ExitOK},
+		{1, []error{notFound}, ExitNotFound},
+		{2, []error{notFound, notFound}, ExitNotFound},
+		{3, []error{notFound, notFound}, ExitPartialFailure},
+		{2, []error{notFound, rateLimited}, ExitPartialFailure},
+	}
+
+	for _, tc := range testCases {
+		if code := fanOutExitCode(tc.n, tc.errs); code != tc.expected {
+			t.Errorf("Expected exit code %d for %d failures of %d, received %d", tc.expected, len(tc.errs), tc.n, code)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"time"
+)
+
+type ExportLogsCommand struct {
+	*Command
+}
+
+type ExportLogsCommandConfig struct {
+	OutputDir    string
+	Filter       string
+	Since, Until time.Time
+}
+
+func (c *ExportLogsCommand) Synopsis() string {
+	return "Archive the organization's System Log to files"
+}
+
+func (c *ExportLogsCommand) Help() string {
+	helpText := `
+Usage: okta-admin export-logs [options]
+
+  Exports events from the System Log to a directory. Events are
+  written as gzip-compressed, newline-delimited JSON to one file
+  per day (UTC) they were published in, named like
+  okta-logs-2019-10-19.jsonl.gz.
+
+  After every page of events, a checkpoint is saved in the
+  directory. Subsequent exports to the same directory resume
+  from the checkpoint instead of -since, so running this command
+  periodically (eg- via cron) archives every event exactly once.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -out    Directory to export events to
+  -since  Export events published after this point in time if the
+          directory doesn't contain a checkpoint. Either an RFC3339
+          timestamp or a duration in the past, like 12h or 30d.
+          (Default: 90d, the retention period of the System Log)
+  -until  Export events published before this point in time,
+          specified the same way as -since. Okta may take a few
+          minutes to make new events available, so recent events
+          are left for the next export. (Default: 5m)
+  -filter System Log filter expression to export only matching
+          events
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *ExportLogsCommand) ParseArgs(args []string) (*ExportLogsCommandConfig, error) {
+	var cfg ExportLogsCommandConfig
+	var since, until string
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.OutputDir, "out", "", "")
+	flags.StringVar(&since, "since", "90d", "")
+	flags.StringVar(&until, "until", "5m", "")
+	flags.StringVar(&cfg.Filter, "filter", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "out", Required: true, Value: cfg.OutputDir},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+	)
+	if err != nil {
+		return &cfg, err
+	}
+
+	now := time.Now()
+	if cfg.Since, err = ParseRelativeTime(since, now); err != nil {
+		return &cfg, errors.New(fmt.Sprintf("since: %v", err))
+	}
+	if cfg.Until, err = ParseRelativeTime(until, now); err != nil {
+		return &cfg, errors.New(fmt.Sprintf("until: %v", err))
+	}
+	return &cfg, nil
+}
+
+func (c *ExportLogsCommand) Run(args []string) int {
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	archive, err := openLogArchive(cfg.OutputDir)
+	if err != nil {
+		c.logError("Failed to open archive", err)
+		return exitCode(err)
+	}
+
+	since := cfg.Since
+	if cp := archive.Checkpoint(); cp != nil {
+		since = cp.Published
+		c.Logger.Printf("Resuming export from %s\n", since.UTC().Format(time.RFC3339))
+	}
+	if !since.Before(cfg.Until) {
+		c.Logger.Println("No new events to export")
+		return 0
+	}
+
+	// Okta treats since as inclusive, so events published at the
+	// checkpoint are fetched again and skipped by the archive.
+	qp := query.NewQueryParams(
+		query.WithSince(since.UTC().Format(logsTimeLayout)),
+		query.WithUntil(cfg.Until.UTC().Format(logsTimeLayout)),
+		query.WithFilter(cfg.Filter),
+		query.WithSortOrder("ASCENDING"),
+		query.WithLimit(logsPageSize),
+	)
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+
+	exported := 0
+	for {
+		events, resp, err := client.GetLogs(qp)
+		if err != nil {
+			c.logError("Failed to fetch logs", err)
+			return exitCode(err)
+		}
+
+		n, err := archive.Write(events)
+		exported += n
+		if err != nil {
+			c.logError("Failed to write events to archive", err)
+			return exitCode(err)
+		}
+
+		cursor := oktaapi.NextPageCursor(resp)
+		if len(events) == 0 || cursor == "" {
+			break
+		}
+		qp.After = cursor
+	}
+
+	c.Logger.Printf("Exported %d events to %s\n", exported, cfg.OutputDir)
+	return 0
+}
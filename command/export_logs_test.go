@@ -0,0 +1,132 @@
+package command
+
+import (
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func createTestExportLogsCommand(globalOptsHelpText string) *ExportLogsCommand {
+	return &ExportLogsCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_export_logs_cmd"),
+	}
+}
+
+func TestExportLogsCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestExportLogsCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestExportLogsCommand_ParseArgs(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestExportLogsCommand("")
+		cfg, err := c.ParseArgs([]string{"-out", "archive/"})
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.OutputDir != "archive/" {
+			t.Errorf("Expected output dir to be archive/, received %s", cfg.OutputDir)
+		}
+		if d := time.Since(cfg.Since); d < 90*24*time.Hour || d > 90*24*time.Hour+time.Minute {
+			t.Errorf("Expected since to be 90 days ago, received %s", cfg.Since)
+		}
+		if d := time.Since(cfg.Until); d < 5*time.Minute || d > 6*time.Minute {
+			t.Errorf("Expected until to be 5 minutes ago, received %s", cfg.Until)
+		}
+	})
+
+	t.Run("with options", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestExportLogsCommand("")
+		args := []string{
+			"-out", "/var/okta",
+			"-since", "2019-10-01T00:00:00Z",
+			"-until", "2019-10-02T00:00:00Z",
+			"-filter", `eventType sw "user."`,
+		}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.OutputDir != args[1] {
+			t.Errorf("Expected output dir to be %s, received %s", args[1], cfg.OutputDir)
+		}
+		if cfg.Since.Format(time.RFC3339) != args[3] {
+			t.Errorf("Expected since to be %s, received %s", args[3], cfg.Since)
+		}
+		if cfg.Until.Format(time.RFC3339) != args[5] {
+			t.Errorf("Expected until to be %s, received %s", args[5], cfg.Until)
+		}
+		if cfg.Filter != args[7] {
+			t.Errorf("Expected filter to be %s, received %s", args[7], cfg.Filter)
+		}
+	})
+
+	t.Run("without output dir", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestExportLogsCommand("")
+		if _, err := c.ParseArgs([]string{}); err == nil {
+			t.Error("Expected args to be invalid as -out is not set")
+		}
+	})
+}
+
+func TestExportLogsCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "okta-admin-export-logs")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	client := newFakeOktaClient()
+	client.logs = []*oktaapi.LogEvent{
+		createTestArchiveEvent("a", "2019-10-01T10:00:00Z"),
+		createTestArchiveEvent("b", "2019-10-01T11:00:00Z"),
+	}
+	run := func(args ...string) (int, string) {
+		c, out := createTestCommandWithClient("test_export_logs_cmd", client)
+		args = append([]string{"-out", dir}, args...)
+		return (&ExportLogsCommand{Command: c}).Run(args), out.String()
+	}
+
+	code, out := run("-since", "2019-10-01T00:00:00Z", "-until", "2019-10-03T00:00:00Z")
+	if code != ExitOK || !strings.Contains(out, "Exported 2 events") {
+		t.Fatalf("Expected 2 events to be exported, received %d: %s", code, out)
+	}
+
+	// The next export resumes from the checkpoint, skipping the
+	// events the fake client returns again
+	client.logs = append(client.logs, createTestArchiveEvent("c", "2019-10-02T09:00:00Z"))
+	code, out = run("-since", "2019-10-01T00:00:00Z", "-until", "2019-10-03T00:00:00Z")
+	if code != ExitOK || !strings.Contains(out, "Resuming export from 2019-10-01T11:00:00Z") || !strings.Contains(out, "Exported 1 events") {
+		t.Fatalf("Expected the export to resume, received %d: %s", code, out)
+	}
+	for day, uuids := range map[string][]string{"2019-10-01": {"a", "b"}, "2019-10-02": {"c"}} {
+		if res := readTestArchiveFile(t, filepath.Join(dir, logArchiveFileName(day))); !testEq(res, uuids) {
+			t.Errorf("Expected events %v on %s, received %v", uuids, day, res)
+		}
+	}
+
+	if code, out = run("-until", "2019-10-01T00:00:00Z"); code != ExitOK || !strings.Contains(out, "No new events to export") {
+		t.Errorf("Expected nothing to be exported, received %d: %s", code, out)
+	}
+
+	client.errs["GetLogs"] = &oktaapi.ApiError{StatusCode: 429, Summary: "API call exceeded rate limit"}
+	if code, out = run("-until", "2019-10-04T00:00:00Z"); code != ExitRateLimited {
+		t.Errorf("Expected exit code %d, received %d: %s", ExitRateLimited, code, out)
+	}
+}
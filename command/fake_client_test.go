@@ -0,0 +1,624 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// fakeOktaClient is an in-memory implementation of the Okta API
+// operations used by commands. Calls to operations it doesn't
+// implement panic, via the embedded nil interface. Failures of
+// specific operations are simulated by adding their names to errs.
+type fakeOktaClient struct {
+	oktaapi.Client
+
+	mu            sync.Mutex
+	users         []*okta.User
+	groups        []*okta.Group
+	members       map[string][]string
+	rules         []*okta.GroupRule
+	roles         map[string][]*okta.Role
+	factors       map[string][]*oktaapi.Factor
+	logs          []*oktaapi.LogEvent
+	sessions      map[string]*oktaapi.Session
+	policies      []*oktaapi.Policy
+	policyRules   map[string][]*oktaapi.PolicyRule
+	errs          map[string]error
+	calls         []string
+	createdUserId string
+}
+
+func newFakeOktaClient() *fakeOktaClient {
+	return &fakeOktaClient{
+		members:     map[string][]string{},
+		roles:       map[string][]*okta.Role{},
+		factors:     map[string][]*oktaapi.Factor{},
+		sessions:    map[string]*oktaapi.Session{},
+		policyRules: map[string][]*oktaapi.PolicyRule{},
+		errs:        map[string]error{},
+	}
+}
+
+// createTestCommandWithClient returns a command which uses the
+// fake client and writes its output to the returned buffer.
+func createTestCommandWithClient(flagSetName string, client oktaapi.Client) (*Command, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	c := createTestCommand("", flagSetName)
+	c.Logger = log.New(out, "", 0)
+	c.oktaClient = client
+	return c, out
+}
+
+func fakeResponse(statusCode int) *okta.Response {
+	return &okta.Response{Response: fakeHttpResponse(statusCode)}
+}
+
+func fakeHttpResponse(statusCode int) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Status:     fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		Header:     http.Header{},
+	}
+}
+
+func fakeUser(id, email, status string) *okta.User {
+	return fakeUserWithLogin(id, email, email, status)
+}
+
+func fakeUserWithLogin(id, login, email, status string) *okta.User {
+	return &okta.User{
+		Id:      id,
+		Status:  status,
+		Profile: &okta.UserProfile{"email": email, "login": login},
+	}
+}
+
+func fakeGroup(id, name string) *okta.Group {
+	return &okta.Group{
+		Id:      id,
+		Profile: &okta.GroupProfile{Name: name},
+		Links: map[string]interface{}{
+			"users": map[string]interface{}{"href": "https://foo.okta.com/api/v1/groups/" + id + "/users"},
+			"apps":  map[string]interface{}{"href": "https://foo.okta.com/api/v1/groups/" + id + "/apps"},
+		},
+	}
+}
+
+func fakeGroupRule(id, name, status, expr string, groupIDs ...string) *okta.GroupRule {
+	return &okta.GroupRule{
+		Id:         id,
+		Name:       name,
+		Status:     status,
+		Conditions: &okta.GroupRuleConditions{Expression: &okta.GroupRuleExpression{Value: expr}},
+		Actions:    &okta.GroupRuleAction{AssignUserToGroups: &okta.GroupRuleGroupAssignment{GroupIds: groupIDs}},
+	}
+}
+
+// fakePolicy returns a policy whose raw JSON, which restoring and
+// showing policies use, matches its fields.
+func fakePolicy(id, name, policyType, description string) *oktaapi.Policy {
+	p := &oktaapi.Policy{Id: id, Name: name, Type: policyType, Description: description, Status: "ACTIVE", Priority: 1}
+	p.Raw, _ = json.Marshal(p)
+	return p
+}
+
+func fakePolicyRule(id, name string, priority int64) *oktaapi.PolicyRule {
+	r := &oktaapi.PolicyRule{Id: id, Name: name, Type: "PASSWORD", Status: "ACTIVE", Priority: priority}
+	r.Raw, _ = json.Marshal(r)
+	return r
+}
+
+// call records an operation and returns the error registered for
+// it, if any.
+func (f *fakeOktaClient) call(op string, args ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, strings.TrimSpace(op+" "+strings.Join(args, " ")))
+	return f.errs[op]
+}
+
+func (f *fakeOktaClient) findUser(id string) *okta.User {
+	for _, u := range f.users {
+		if u.Id == id {
+			return u
+		}
+	}
+	return nil
+}
+
+func (f *fakeOktaClient) GetUser(idOrLogin string) (*oktaapi.User, *http.Response, error) {
+	if err := f.call("GetUser", idOrLogin); err != nil {
+		return nil, nil, err
+	}
+	for _, u := range f.users {
+		if u.Id == idOrLogin || (*u.Profile)["login"] == idOrLogin {
+			return &oktaapi.User{Id: u.Id, Status: u.Status, Profile: oktaapi.ProfileOf(u)}, fakeHttpResponse(http.StatusOK), nil
+		}
+	}
+	return nil, fakeHttpResponse(http.StatusNotFound), errors.New("failed to fetch user (404 Not Found)")
+}
+
+// ListUsers supports searching users by email ID, in which case
+// users of all statuses are returned, like Okta does.
+func (f *fakeOktaClient) ListUsers(qp *query.Params) ([]*okta.User, *okta.Response, error) {
+	if qp != nil && qp.Search != "" {
+		if err := f.call("ListUsers", qp.Search); err != nil {
+			return nil, nil, err
+		}
+		var res []*okta.User
+		for _, u := range f.users {
+			if qp.Search == fmt.Sprintf("profile.email eq \"%s\"", (*u.Profile)["email"]) {
+				res = append(res, u)
+			}
+		}
+		return res, fakeResponse(http.StatusOK), nil
+	}
+
+	if err := f.call("ListUsers"); err != nil {
+		return nil, nil, err
+	}
+	var res []*okta.User
+	for _, u := range f.users {
+		deprovisioned := u.Status == userStatusDeprovisioned
+		if deprovisioned == (qp != nil && strings.Contains(qp.Filter, userStatusDeprovisioned)) {
+			res = append(res, u)
+		}
+	}
+	return res, fakeResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) CreateUser(user okta.User, qp *query.Params) (*okta.User, *okta.Response, error) {
+	if err := f.call("CreateUser", (*user.Profile)["email"].(string)); err != nil {
+		return nil, nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	user.Id = f.createdUserId
+	f.users = append(f.users, &user)
+	return &user, fakeResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) DeactivateUser(userID string, qp *query.Params) (*okta.Response, error) {
+	if err := f.call("DeactivateUser", userID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.findUser(userID).Status = "DEPROVISIONED"
+	return fakeResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) ActivateUser(userID string, qp *query.Params) (*okta.UserActivationToken, *okta.Response, error) {
+	if err := f.call("ActivateUser", userID); err != nil {
+		return nil, nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.findUser(userID).Status = "PROVISIONED"
+	return &okta.UserActivationToken{}, fakeResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) ListUserGroups(userID string, qp *query.Params) ([]*okta.Group, *okta.Response, error) {
+	if err := f.call("ListUserGroups", userID); err != nil {
+		return nil, nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	res := []*okta.Group{}
+	for _, g := range f.groups {
+		for _, uid := range f.members[g.Id] {
+			if uid == userID {
+				res = append(res, g)
+				break
+			}
+		}
+	}
+	return res, fakeResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) ResetPassword(userID string, qp *query.Params) (*okta.ResetPasswordToken, *okta.Response, error) {
+	if err := f.call("ResetPassword", userID); err != nil {
+		return nil, nil, err
+	}
+	return &okta.ResetPasswordToken{}, fakeResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) ListAssignedRoles(userID string, qp *query.Params) ([]*okta.Role, *okta.Response, error) {
+	if err := f.call("ListAssignedRoles", userID); err != nil {
+		return nil, nil, err
+	}
+	return f.roles[userID], fakeResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) ListGroupTargetsForRole(userID, roleID string, qp *query.Params) ([]*okta.Group, *okta.Response, error) {
+	if err := f.call("ListGroupTargetsForRole", userID, roleID); err != nil {
+		return nil, nil, err
+	}
+	var res []*okta.Group
+	for _, g := range f.groups {
+		for _, uid := range f.members[g.Id] {
+			if uid == userID {
+				res = append(res, g)
+			}
+		}
+	}
+	return res, fakeResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) ListUserFactors(userID string) ([]*oktaapi.Factor, *http.Response, error) {
+	if err := f.call("ListUserFactors", userID); err != nil {
+		return nil, nil, err
+	}
+	return f.factors[userID], fakeHttpResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) ResetAllFactors(userID string) (*okta.Response, error) {
+	if err := f.call("ResetAllFactors", userID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.factors, userID)
+	return fakeResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) ListGroups(qp *query.Params) ([]*okta.Group, *okta.Response, error) {
+	if qp == nil || qp.Q == "" {
+		if err := f.call("ListGroups"); err != nil {
+			return nil, nil, err
+		}
+		return f.groups, fakeResponse(http.StatusOK), nil
+	}
+
+	// q matches groups whose name starts with it
+	if err := f.call("ListGroups", qp.Q); err != nil {
+		return nil, nil, err
+	}
+	res := []*okta.Group{}
+	for _, g := range f.groups {
+		if strings.HasPrefix(strings.ToLower(g.Profile.Name), strings.ToLower(qp.Q)) {
+			res = append(res, g)
+		}
+	}
+	return res, fakeResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) GetGroup(groupID string, qp *query.Params) (*okta.Group, *okta.Response, error) {
+	if err := f.call("GetGroup", groupID); err != nil {
+		return nil, nil, err
+	}
+	for _, g := range f.groups {
+		if g.Id == groupID {
+			return g, fakeResponse(http.StatusOK), nil
+		}
+	}
+	return nil, fakeResponse(http.StatusNotFound), errors.New("Not found: Resource not found: " + groupID + " (UserGroup)")
+}
+
+func (f *fakeOktaClient) ListGroupUsers(groupID string, qp *query.Params) ([]*okta.User, *okta.Response, error) {
+	if err := f.call("ListGroupUsers", groupID); err != nil {
+		return nil, nil, err
+	}
+	var res []*okta.User
+	for _, uid := range f.members[groupID] {
+		res = append(res, f.findUser(uid))
+	}
+	return res, fakeResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) AddUserToGroup(groupID, userID string) (*okta.Response, error) {
+	if err := f.call("AddUserToGroup", groupID, userID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// Like Okta, adding a member again changes nothing
+	for _, uid := range f.members[groupID] {
+		if uid == userID {
+			return fakeResponse(http.StatusNoContent), nil
+		}
+	}
+	f.members[groupID] = append(f.members[groupID], userID)
+	return fakeResponse(http.StatusNoContent), nil
+}
+
+func (f *fakeOktaClient) RemoveUserFromGroup(groupID, userID string) (*okta.Response, error) {
+	if err := f.call("RemoveUserFromGroup", groupID, userID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var members []string
+	for _, uid := range f.members[groupID] {
+		if uid != userID {
+			members = append(members, uid)
+		}
+	}
+	f.members[groupID] = members
+	return fakeResponse(http.StatusNoContent), nil
+}
+
+func (f *fakeOktaClient) ListGroupRules(qp *query.Params) ([]*okta.GroupRule, *okta.Response, error) {
+	if err := f.call("ListGroupRules"); err != nil {
+		return nil, nil, err
+	}
+	return f.rules, fakeResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) GetGroupRule(ruleID string, qp *query.Params) (*okta.GroupRule, *okta.Response, error) {
+	if err := f.call("GetGroupRule", ruleID); err != nil {
+		return nil, nil, err
+	}
+	for _, r := range f.rules {
+		if r.Id == ruleID {
+			return r, fakeResponse(http.StatusOK), nil
+		}
+	}
+	return nil, fakeResponse(http.StatusNotFound), errors.New("the API returned an error: not found")
+}
+
+func (f *fakeOktaClient) CreateGroupRule(rule okta.GroupRule) (*okta.GroupRule, *okta.Response, error) {
+	if err := f.call("CreateGroupRule", rule.Name, strings.Join(rule.Actions.AssignUserToGroups.GroupIds, ",")); err != nil {
+		return nil, nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rule.Id = fmt.Sprintf("0pr%d", len(f.rules)+1)
+	rule.Status = "INACTIVE"
+	f.rules = append(f.rules, &rule)
+	return &rule, fakeResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) setGroupRuleStatus(op, ruleID, status string) (*okta.Response, error) {
+	if err := f.call(op, ruleID); err != nil {
+		return nil, err
+	}
+	for _, r := range f.rules {
+		if r.Id == ruleID {
+			r.Status = status
+		}
+	}
+	return fakeResponse(http.StatusNoContent), nil
+}
+
+func (f *fakeOktaClient) ActivateGroupRule(ruleID string) (*okta.Response, error) {
+	return f.setGroupRuleStatus("ActivateGroupRule", ruleID, groupRuleStatusActive)
+}
+
+func (f *fakeOktaClient) DeactivateGroupRule(ruleID string) (*okta.Response, error) {
+	return f.setGroupRuleStatus("DeactivateGroupRule", ruleID, "INACTIVE")
+}
+
+// DeleteGroupRule deletes a rule, which like in Okta must be
+// deactivated first.
+func (f *fakeOktaClient) DeleteGroupRule(ruleID string, qp *query.Params) (*okta.Response, error) {
+	if err := f.call("DeleteGroupRule", ruleID, qp.String()); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, r := range f.rules {
+		if r.Id != ruleID {
+			continue
+		}
+		if r.Status == groupRuleStatusActive {
+			return fakeResponse(http.StatusBadRequest), errors.New("cannot delete an active rule")
+		}
+		f.rules = append(f.rules[:i], f.rules[i+1:]...)
+		return fakeResponse(http.StatusAccepted), nil
+	}
+	return fakeResponse(http.StatusNotFound), errors.New("the API returned an error: not found")
+}
+
+func (f *fakeOktaClient) GetLogs(qp *query.Params) ([]*oktaapi.LogEvent, *http.Response, error) {
+	if err := f.call("GetLogs", qp.Filter); err != nil {
+		return nil, nil, err
+	}
+	return f.logs, fakeHttpResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) GetSession(sessionID string) (*oktaapi.Session, *http.Response, error) {
+	if err := f.call("GetSession", sessionID); err != nil {
+		return nil, nil, err
+	}
+	s, ok := f.sessions[sessionID]
+	if !ok {
+		return nil, fakeHttpResponse(http.StatusNotFound), errors.New("failed to fetch session (404 Not Found)")
+	}
+	return s, fakeHttpResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) EndSession(sessionID string) (*okta.Response, error) {
+	if err := f.call("EndSession", sessionID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.sessions, sessionID)
+	return fakeResponse(http.StatusNoContent), nil
+}
+
+func (f *fakeOktaClient) EndAllUserSessions(userID string, qp *query.Params) (*okta.Response, error) {
+	if err := f.call("EndAllUserSessions", userID, qp.String()); err != nil {
+		return nil, err
+	}
+	return fakeResponse(http.StatusNoContent), nil
+}
+
+func (f *fakeOktaClient) ListPolicies(qp *query.Params) ([]*oktaapi.Policy, *http.Response, error) {
+	if err := f.call("ListPolicies", qp.Type); err != nil {
+		return nil, nil, err
+	}
+	var res []*oktaapi.Policy
+	for _, p := range f.policies {
+		if p.Type == qp.Type {
+			res = append(res, p)
+		}
+	}
+	return res, fakeHttpResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) ListPolicyRules(policyID string) ([]*oktaapi.PolicyRule, *http.Response, error) {
+	if err := f.call("ListPolicyRules", policyID); err != nil {
+		return nil, nil, err
+	}
+	return f.policyRules[policyID], fakeHttpResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) GetPolicy(policyID string) (*oktaapi.Policy, *http.Response, error) {
+	if err := f.call("GetPolicy", policyID); err != nil {
+		return nil, nil, err
+	}
+	for _, p := range f.policies {
+		if p.Id == policyID {
+			return p, fakeHttpResponse(http.StatusOK), nil
+		}
+	}
+	return nil, fakeHttpResponse(http.StatusNotFound), newNotFoundError("policy %s does not exist", policyID)
+}
+
+// fakeRaw returns the raw JSON of a policy or rule created or
+// updated with body, to which Okta adds the ID and status.
+func fakeRaw(body interface{}, id, status string) json.RawMessage {
+	fields := map[string]interface{}{}
+	data, _ := json.Marshal(body)
+	json.Unmarshal(data, &fields)
+	fields["id"], fields["status"] = id, status
+	raw, _ := json.Marshal(fields)
+	return raw
+}
+
+func (f *fakeOktaClient) setPolicy(i int, raw json.RawMessage) *oktaapi.Policy {
+	p := &oktaapi.Policy{}
+	json.Unmarshal(raw, p)
+	p.Raw = raw
+	if i < len(f.policies) {
+		f.policies[i] = p
+	} else {
+		f.policies = append(f.policies, p)
+	}
+	return p
+}
+
+func (f *fakeOktaClient) CreatePolicy(body interface{}, activate bool) (*oktaapi.Policy, *http.Response, error) {
+	if err := f.call("CreatePolicy"); err != nil {
+		return nil, nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	status := "INACTIVE"
+	if activate {
+		status = "ACTIVE"
+	}
+	id := fmt.Sprintf("00pnew%d", len(f.policies))
+	return f.setPolicy(len(f.policies), fakeRaw(body, id, status)), fakeHttpResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) UpdatePolicy(policyID string, body interface{}) (*http.Response, error) {
+	if err := f.call("UpdatePolicy", policyID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, p := range f.policies {
+		if p.Id == policyID {
+			f.setPolicy(i, fakeRaw(body, p.Id, p.Status))
+			return fakeHttpResponse(http.StatusOK), nil
+		}
+	}
+	return fakeHttpResponse(http.StatusNotFound), newNotFoundError("policy %s does not exist", policyID)
+}
+
+func (f *fakeOktaClient) setPolicyStatus(op, policyID, status string) (*okta.Response, error) {
+	if err := f.call(op, policyID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, p := range f.policies {
+		if p.Id == policyID {
+			f.setPolicy(i, fakeRaw(p.Raw, p.Id, status))
+		}
+	}
+	return fakeResponse(http.StatusNoContent), nil
+}
+
+func (f *fakeOktaClient) ActivatePolicy(policyID string) (*okta.Response, error) {
+	return f.setPolicyStatus("ActivatePolicy", policyID, "ACTIVE")
+}
+
+func (f *fakeOktaClient) DeactivatePolicy(policyID string) (*okta.Response, error) {
+	return f.setPolicyStatus("DeactivatePolicy", policyID, "INACTIVE")
+}
+
+func (f *fakeOktaClient) setPolicyRule(policyID string, i int, raw json.RawMessage) {
+	r := &oktaapi.PolicyRule{}
+	json.Unmarshal(raw, r)
+	r.Raw = raw
+	if i < len(f.policyRules[policyID]) {
+		f.policyRules[policyID][i] = r
+	} else {
+		f.policyRules[policyID] = append(f.policyRules[policyID], r)
+	}
+}
+
+func (f *fakeOktaClient) CreatePolicyRule(policyID string, body interface{}, activate bool) (*http.Response, error) {
+	if err := f.call("CreatePolicyRule", policyID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	status := "INACTIVE"
+	if activate {
+		status = "ACTIVE"
+	}
+	id := fmt.Sprintf("0prnew%d", len(f.policyRules[policyID]))
+	f.setPolicyRule(policyID, len(f.policyRules[policyID]), fakeRaw(body, id, status))
+	return fakeHttpResponse(http.StatusOK), nil
+}
+
+func (f *fakeOktaClient) UpdatePolicyRule(policyID, ruleID string, body interface{}) (*http.Response, error) {
+	if err := f.call("UpdatePolicyRule", policyID, ruleID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, r := range f.policyRules[policyID] {
+		if r.Id == ruleID {
+			f.setPolicyRule(policyID, i, fakeRaw(body, r.Id, r.Status))
+			return fakeHttpResponse(http.StatusOK), nil
+		}
+	}
+	return fakeHttpResponse(http.StatusNotFound), newNotFoundError("rule %s does not exist", ruleID)
+}
+
+func (f *fakeOktaClient) setPolicyRuleStatus(op, policyID, ruleID, status string) (*okta.Response, error) {
+	if err := f.call(op, policyID, ruleID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, r := range f.policyRules[policyID] {
+		if r.Id == ruleID {
+			f.setPolicyRule(policyID, i, fakeRaw(r.Raw, r.Id, status))
+		}
+	}
+	return fakeResponse(http.StatusNoContent), nil
+}
+
+func (f *fakeOktaClient) ActivatePolicyRule(policyID, ruleID string) (*okta.Response, error) {
+	return f.setPolicyRuleStatus("ActivatePolicyRule", policyID, ruleID, "ACTIVE")
+}
+
+func (f *fakeOktaClient) DeactivatePolicyRule(policyID, ruleID string) (*okta.Response, error) {
+	return f.setPolicyRuleStatus("DeactivatePolicyRule", policyID, ruleID, "INACTIVE")
+}
@@ -1,11 +1,23 @@
 package command
 
 import (
+	"errors"
+	"fmt"
 	oktaapi "github.com/duaraghav8/okta-admin/okta"
 	"github.com/okta/okta-sdk-golang/okta"
 	"github.com/okta/okta-sdk-golang/okta/query"
+	"net/http"
+	"regexp"
+	"strings"
 )
 
+// maxGroupSuggestions is the number of groups whose names start
+// with a name which doesn't exist that are suggested instead.
+const maxGroupSuggestions = 5
+
+// rxGroupID matches the IDs Okta assigns to groups.
+var rxGroupID = regexp.MustCompile(`^00g[0-9A-Za-z]{17}$`)
+
 type OktaGroups []*okta.Group
 
 // GetID returns the ID of the Group whose name is specified.
@@ -20,20 +32,16 @@ func (groups OktaGroups) GetID(name string) string {
 	return ""
 }
 
-type numberOfExistingGroups uint32
-
-// listGroupsResult contains the result of an async HTTP request
-// made to Okta API to fetch list of Groups.
-type listGroupsResult struct {
-	oktaapi.GenericResult
-	Groups []*okta.Group
-}
-
-// addUserToGroup contains the result of an async HTTP request
-// made to Okta API to add a User to a Group.
-type addUserToGroupResult struct {
-	oktaapi.GenericResult
-	GroupName, GroupId string
+// GetName returns the name of the Group whose ID is specified.
+// If the Group with that ID doesn't exist, this method simply
+// returns an empty string.
+func (groups OktaGroups) GetName(id string) string {
+	for _, g := range groups {
+		if g.Id == id {
+			return g.Profile.Name
+		}
+	}
+	return ""
 }
 
 // FilterGroupEvalFunc defines the criteria based on which an
@@ -77,27 +85,132 @@ Links
 
 // getLinkFromGroup returns a specific type of link from the
 // group passed to it. It abstracts away the nuances of
-// typecasting Links to retrieve data.
+// typecasting Links to retrieve data, returning an empty
+// string if the group has no such link.
 func getLinkFromGroup(g *okta.Group, linkType string) string {
-	links := g.Links.(map[string]interface{})
-	return links[linkType].(map[string]interface{})["href"].(string)
+	links, _ := g.Links.(map[string]interface{})
+	link, _ := links[linkType].(map[string]interface{})
+	href, _ := link["href"].(string)
+	return href
 }
 
-// listGroups fetches the list of Groups from Okta API asynchronously
-func listGroups(client *okta.Client, qp *query.Params, ch chan<- *listGroupsResult) {
-	groups, resp, err := client.Group.ListGroups(qp)
-	ch <- &listGroupsResult{
-		Groups:        groups,
-		GenericResult: oktaapi.GenericResult{Resp: resp, Err: err},
+// listAllGroups fetches every page of Groups matching the
+// query params supplied to it.
+func listAllGroups(client oktaapi.Client, qp *query.Params) (OktaGroups, error) {
+	var res OktaGroups
+	if qp == nil {
+		qp = query.NewQueryParams()
+	}
+
+	for {
+		groups, resp, err := client.ListGroups(qp)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.New(resp.Status)
+		}
+		res = append(res, groups...)
+
+		cursor := oktaapi.NextPageCursor(resp.Response)
+		if cursor == "" {
+			return res, nil
+		}
+		qp.After = cursor
 	}
 }
 
-// addUserToGroup adds a user to a group using the Okta API asynchronously
-func addUserToGroup(client *okta.Client, uid, gid, gname string, ch chan<- *addUserToGroupResult) {
-	resp, err := client.Group.AddUserToGroup(gid, uid)
-	ch <- &addUserToGroupResult{
-		GroupId:       gid,
-		GroupName:     gname,
-		GenericResult: oktaapi.GenericResult{Err: err, Resp: resp},
+// resolveGroup returns the group specified by group, which may be
+// its ID or its name. Groups are searched for by name in Okta, which
+// returns the ones whose name starts with it, of which the one named
+// exactly the same is picked, or else the one whose name differs
+// only in case. Resolving fails if more than one group has the name.
+// If the client caches groups and none has the name, the cached
+// groups are forgotten and it is searched for again, since it may
+// have been created since they were cached.
+func resolveGroup(client oktaapi.Client, group string) (*okta.Group, error) {
+	g, err := lookupGroup(client, group)
+	var nfErr *notFoundError
+	if cache, ok := client.(groupCache); ok && errors.As(err, &nfErr) {
+		cache.forgetGroups()
+		g, err = lookupGroup(client, group)
+	}
+	return g, err
+}
+
+// lookupGroup resolves the group via the API, as resolveGroup does.
+func lookupGroup(client oktaapi.Client, group string) (*okta.Group, error) {
+	if rxGroupID.MatchString(group) {
+		g, resp, err := client.GetGroup(group, nil)
+		if err == nil {
+			return g, nil
+		}
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, newNotFoundError("no group found with ID %s", group)
+		}
+		return nil, err
+	}
+
+	groups, err := listAllGroups(client, query.NewQueryParams(query.WithQ(group)))
+	if err != nil {
+		return nil, err
+	}
+	matches := filterGroups(groups, func(g *okta.Group, i int) bool {
+		return g.Profile.Name == group
+	})
+	if len(matches) == 0 {
+		matches = filterGroups(groups, func(g *okta.Group, i int) bool {
+			return strings.EqualFold(g.Profile.Name, group)
+		})
+	}
+
+	switch len(matches) {
+	case 0:
+		if len(groups) == 0 {
+			return nil, newNotFoundError("%s does not exist", group)
+		}
+		names := make([]string, 0, maxGroupSuggestions)
+		for _, g := range groups {
+			if len(names) == maxGroupSuggestions {
+				names = append(names, "...")
+				break
+			}
+			names = append(names, g.Profile.Name)
+		}
+		return nil, newNotFoundError("%s does not exist, groups whose name starts with it: %s", group, strings.Join(names, ", "))
+	case 1:
+		return matches[0], nil
+	}
+
+	ids := make([]string, len(matches), len(matches))
+	for i, m := range matches {
+		ids[i] = m.Id
+	}
+	return nil, errors.New(fmt.Sprintf(
+		"%d groups are named %s (%s), specify the ID of one of them instead",
+		len(matches), group, strings.Join(ids, ", ")))
+}
+
+// listAllGroupUsers fetches every page of members of the
+// Group with the specified ID.
+func listAllGroupUsers(client oktaapi.Client, gid string) ([]*okta.User, error) {
+	var res []*okta.User
+	qp := query.NewQueryParams()
+
+	for {
+		users, resp, err := client.ListGroupUsers(gid, qp)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.New(resp.Status)
+		}
+		res = append(res, users...)
+
+		cursor := oktaapi.NextPageCursor(resp.Response)
+		if cursor == "" {
+			return res, nil
+		}
+		qp.After = cursor
 	}
 }
@@ -0,0 +1,221 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const (
+	groupRuleType           = "group_rule"
+	groupRuleExpressionType = "urn:okta:expression:1.0"
+	groupRuleStatusActive   = "ACTIVE"
+	groupRuleStatusInactive = "INACTIVE"
+)
+
+// rxGroupIDLiteral matches Okta Group IDs quoted inside an
+// Expression Language expression.
+var rxGroupIDLiteral = regexp.MustCompile(`"(00g[0-9A-Za-z]{17})"`)
+
+// listAllGroupRules fetches every page of Group Rules matching
+// the query params supplied to it.
+func listAllGroupRules(client oktaapi.Client, qp *query.Params) ([]*okta.GroupRule, error) {
+	var res []*okta.GroupRule
+	if qp == nil {
+		qp = query.NewQueryParams()
+	}
+
+	for {
+		rules, resp, err := client.ListGroupRules(qp)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.New(resp.Status)
+		}
+		res = append(res, rules...)
+
+		cursor := oktaapi.NextPageCursor(resp.Response)
+		if cursor == "" {
+			return res, nil
+		}
+		qp.After = cursor
+	}
+}
+
+// findGroupRule returns the Group Rule with the specified ID or,
+// if the ID is empty, the one with the specified name. An error
+// is returned if no rule or more than one rule has the name.
+func findGroupRule(client oktaapi.Client, id, name string) (*okta.GroupRule, error) {
+	if id != "" {
+		rule, resp, err := client.GetGroupRule(id, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.New(resp.Status)
+		}
+		return rule, nil
+	}
+
+	rules, err := listAllGroupRules(client, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var res *okta.GroupRule
+	for _, r := range rules {
+		if r.Name != name {
+			continue
+		}
+		if res != nil {
+			return nil, errors.New(fmt.Sprintf("multiple rules are named %s, specify the rule ID instead", name))
+		}
+		res = r
+	}
+	if res == nil {
+		return nil, newNotFoundError("%s does not exist", name)
+	}
+	return res, nil
+}
+
+// validateGroupRuleIdentifier returns an error unless exactly
+// one of a Group Rule's ID and name is specified.
+func validateGroupRuleIdentifier(id, name string) error {
+	if (id == "") == (name == "") {
+		return errors.New("either id or name of the rule is required")
+	}
+	return nil
+}
+
+// formatGroupRuleExpression makes a Group Rule expression more
+// readable by putting each top-level boolean operator on a new
+// line and annotating Group IDs with the names of the groups.
+func formatGroupRuleExpression(expr string, groups OktaGroups) string {
+	var (
+		builder = &strings.Builder{}
+		depth   = 0
+		quote   rune
+	)
+
+	runes := []rune(strings.TrimSpace(expr))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if quote != 0 {
+			builder.WriteRune(r)
+			if r == '\\' && i+1 < len(runes) {
+				i++
+				builder.WriteRune(runes[i])
+			} else if r == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch r {
+		case '"', '\'':
+			quote = r
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+
+		if depth == 0 {
+			if op := booleanOperatorAt(runes, i); op != "" {
+				builder.WriteString(fmt.Sprintf("\n  %s ", strings.TrimSpace(op)))
+				i += len(op) - 1
+				// Skip whitespace following the operator
+				for i+1 < len(runes) && runes[i+1] == ' ' {
+					i++
+				}
+				continue
+			}
+		}
+		builder.WriteRune(r)
+	}
+
+	lines := strings.Split(builder.String(), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+	}
+
+	return rxGroupIDLiteral.ReplaceAllStringFunc(strings.Join(lines, "\n"), func(literal string) string {
+		if name := groups.GetName(strings.Trim(literal, `"`)); name != "" {
+			return fmt.Sprintf("%s /* %s */", literal, name)
+		}
+		return literal
+	})
+}
+
+// booleanOperatorAt returns the boolean operator of the Okta
+// Expression Language starting at position i of the expression,
+// including surrounding whitespace for word operators. If there
+// is no operator at i, an empty string is returned.
+func booleanOperatorAt(runes []rune, i int) string {
+	rest := string(runes[i:])
+	for _, op := range []string{"&&", "||", " AND ", " OR ", " and ", " or "} {
+		if strings.HasPrefix(rest, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// getGroupRuleDetailsPretty returns a pretty string describing
+// the Group Rule passed to it.
+func getGroupRuleDetailsPretty(r *okta.GroupRule, groups OktaGroups) string {
+	tpl := `
+Name:   {{.Name}}
+ID:     {{.Id}}
+Status: {{.Status}}
+
+Expression
+  {{.Expression}}
+
+Assigns users to
+{{- range .Groups}}
+  {{.}}
+{{- else}}
+  [None]
+{{- end}}
+
+Excluded users
+{{- range .ExcludedUsers}}
+  {{.}}
+{{- else}}
+  [None]
+{{- end}}
+`
+
+	var expr string
+	var groupNames, excluded []string
+
+	if r.Conditions != nil && r.Conditions.Expression != nil {
+		expr = formatGroupRuleExpression(r.Conditions.Expression.Value, groups)
+	}
+	if r.Actions != nil && r.Actions.AssignUserToGroups != nil {
+		for _, id := range r.Actions.AssignUserToGroups.GroupIds {
+			groupNames = append(groupNames, fmt.Sprintf("%s (%s)", Coalesce(groups.GetName(id), "[Unknown]"), id))
+		}
+	}
+	if r.Conditions != nil && r.Conditions.People != nil && r.Conditions.People.Users != nil {
+		excluded = r.Conditions.People.Users.Exclude
+	}
+
+	res, _ := FillTemplateMessage(tpl, map[string]interface{}{
+		"Id":            r.Id,
+		"Name":          r.Name,
+		"Status":        r.Status,
+		"Expression":    strings.ReplaceAll(Coalesce(expr, "[None]"), "\n", "\n  "),
+		"Groups":        groupNames,
+		"ExcludedUsers": excluded,
+	})
+	return res
+}
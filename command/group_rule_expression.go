@@ -0,0 +1,411 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// groupRuleExpression is a parsed Okta Expression Language
+// expression which can be evaluated locally against users. Only
+// the subset of the language commonly used in Group Rules is
+// supported:
+//
+//	user.attr == "value", user.attr != "value"
+//	String.startsWith(user.attr, "prefix")
+//	String.stringContains(user.attr, "substring")
+//	isMemberOfAnyGroup("groupId", ...)
+//	&&, ||, AND, OR (in any case), ! and parentheses
+type groupRuleExpression struct {
+	root     exprNode
+	groupIDs []string
+}
+
+// exprEnv contains the data an expression is evaluated against.
+type exprEnv struct {
+	Profile map[string]interface{}
+	Groups  map[string]bool
+}
+
+type exprNode interface {
+	eval(env *exprEnv) (interface{}, error)
+}
+
+type (
+	exprLiteral   struct{ value string }
+	exprAttribute struct{ name string }
+	exprNot       struct{ operand exprNode }
+	exprBinary    struct {
+		op          string
+		left, right exprNode
+	}
+	exprCall struct {
+		name string
+		args []exprNode
+	}
+)
+
+func (n *exprLiteral) eval(env *exprEnv) (interface{}, error) {
+	return n.value, nil
+}
+
+// eval returns the value of the user profile attribute. Values
+// which aren't strings are converted to their string form, and
+// attributes missing from the profile evaluate to nil.
+func (n *exprAttribute) eval(env *exprEnv) (interface{}, error) {
+	v, ok := env.Profile[n.name]
+	if !ok || v == nil {
+		return nil, nil
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(v), nil
+}
+
+func (n *exprNot) eval(env *exprEnv) (interface{}, error) {
+	v, err := evalBool(n.operand, env)
+	if err != nil {
+		return nil, err
+	}
+	return !v, nil
+}
+
+func (n *exprBinary) eval(env *exprEnv) (interface{}, error) {
+	switch n.op {
+	case "&&", "||":
+		l, err := evalBool(n.left, env)
+		if err != nil {
+			return nil, err
+		}
+		// Short-circuit like Okta does
+		if (n.op == "&&" && !l) || (n.op == "||" && l) {
+			return l, nil
+		}
+		return evalBool(n.right, env)
+	}
+
+	l, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	r, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "==" {
+		return l == r, nil
+	}
+	return l != r, nil
+}
+
+func (n *exprCall) eval(env *exprEnv) (interface{}, error) {
+	args := make([]string, len(n.args), len(n.args))
+	for i, a := range n.args {
+		v, err := a.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		s, _ := v.(string)
+		args[i] = s
+	}
+
+	switch n.name {
+	case "String.startsWith":
+		return strings.HasPrefix(args[0], args[1]), nil
+	case "String.stringContains":
+		return strings.Contains(args[0], args[1]), nil
+	case "isMemberOfAnyGroup":
+		for _, gid := range args {
+			if env.Groups[gid] {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return nil, errors.New(fmt.Sprintf("unsupported function %s", n.name))
+}
+
+func evalBool(n exprNode, env *exprEnv) (bool, error) {
+	v, err := n.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, errors.New(fmt.Sprintf("expected a boolean, received %v", v))
+	}
+	return b, nil
+}
+
+// exprFuncArity contains the number of arguments accepted by
+// each supported function. A negative number means that the
+// function accepts at least that many (absolute) arguments.
+var exprFuncArity = map[string]int{
+	"String.startsWith":     2,
+	"String.stringContains": 2,
+	"isMemberOfAnyGroup":    -1,
+}
+
+// Matches returns true if a user with the specified profile and
+// group memberships satisfies the expression. The memberships
+// only need to contain the groups returned by GroupIDs.
+func (e *groupRuleExpression) Matches(profile map[string]interface{}, groups map[string]bool) (bool, error) {
+	return evalBool(e.root, &exprEnv{Profile: profile, Groups: groups})
+}
+
+// GroupIDs returns the IDs of all groups the expression checks
+// membership of.
+func (e *groupRuleExpression) GroupIDs() []string {
+	return e.groupIDs
+}
+
+// parseGroupRuleExpression parses the expression of a Group Rule.
+// An error is returned if the expression is invalid or uses parts
+// of the language which aren't supported.
+func parseGroupRuleExpression(expr string) (*groupRuleExpression, error) {
+	tokens, err := tokenizeExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, errors.New(fmt.Sprintf("unexpected %s", p.peek().value))
+	}
+	return &groupRuleExpression{root: root, groupIDs: p.groupIDs}, nil
+}
+
+const (
+	tokenIdent = iota
+	tokenString
+	tokenOperator
+)
+
+type exprToken struct {
+	kind  int
+	value string
+}
+
+// tokenizeExpression splits an expression into identifiers, string
+// literals and operators. Word operators (AND, OR), which are case
+// insensitive like in Okta, are normalized to their symbolic form.
+func tokenizeExpression(expr string) ([]exprToken, error) {
+	var tokens []exprToken
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '"' || r == '\'':
+			builder := &strings.Builder{}
+			j := i + 1
+			for ; j < len(runes) && runes[j] != r; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				builder.WriteRune(runes[j])
+			}
+			if j == len(runes) {
+				return nil, errors.New("unterminated string literal")
+			}
+			tokens = append(tokens, exprToken{kind: tokenString, value: builder.String()})
+			i = j + 1
+
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '.') {
+				j++
+			}
+			word := string(runes[i:j])
+			switch strings.ToUpper(word) {
+			case "AND":
+				tokens = append(tokens, exprToken{kind: tokenOperator, value: "&&"})
+			case "OR":
+				tokens = append(tokens, exprToken{kind: tokenOperator, value: "||"})
+			default:
+				tokens = append(tokens, exprToken{kind: tokenIdent, value: word})
+			}
+			i = j
+
+		default:
+			op := ""
+			for _, o := range []string{"==", "!=", "&&", "||", "!", "(", ")", ","} {
+				if strings.HasPrefix(string(runes[i:]), o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, errors.New(fmt.Sprintf("unexpected character %q", r))
+			}
+			tokens = append(tokens, exprToken{kind: tokenOperator, value: op})
+			i += len([]rune(op))
+		}
+	}
+	return tokens, nil
+}
+
+// exprParser is a recursive descent parser for expressions.
+type exprParser struct {
+	tokens   []exprToken
+	pos      int
+	groupIDs []string
+}
+
+func (p *exprParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *exprParser) peek() exprToken {
+	if p.done() {
+		return exprToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) acceptOperator(op string) bool {
+	if t := p.peek(); t.kind == tokenOperator && t.value == op && !p.done() {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) expectOperator(op string) error {
+	if !p.acceptOperator(op) {
+		return errors.New(fmt.Sprintf("expected %s", op))
+	}
+	return nil
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOperator("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &exprBinary{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOperator("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &exprBinary{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.acceptOperator("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &exprNot{operand: operand}, nil
+	}
+	if p.acceptOperator("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expectOperator(")")
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (exprNode, error) {
+	left, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!="} {
+		if p.acceptOperator(op) {
+			right, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			return &exprBinary{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseValue() (exprNode, error) {
+	if p.done() {
+		return nil, errors.New("unexpected end of expression")
+	}
+
+	t := p.peek()
+	p.pos++
+	switch {
+	case t.kind == tokenString:
+		return &exprLiteral{value: t.value}, nil
+	case t.kind == tokenIdent && p.acceptOperator("("):
+		return p.parseCall(t.value)
+	case t.kind == tokenIdent && strings.HasPrefix(t.value, "user."):
+		return &exprAttribute{name: strings.TrimPrefix(t.value, "user.")}, nil
+	case t.kind == tokenIdent:
+		return nil, errors.New(fmt.Sprintf("unsupported identifier %s", t.value))
+	}
+	return nil, errors.New(fmt.Sprintf("unexpected %s", t.value))
+}
+
+func (p *exprParser) parseCall(name string) (exprNode, error) {
+	arity, ok := exprFuncArity[name]
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("unsupported function %s", name))
+	}
+
+	call := &exprCall{name: name}
+	if !p.acceptOperator(")") {
+		for {
+			arg, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			if !p.acceptOperator(",") {
+				break
+			}
+		}
+		if err := p.expectOperator(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	if (arity >= 0 && len(call.args) != arity) || (arity < 0 && len(call.args) < -arity) {
+		return nil, errors.New(fmt.Sprintf("wrong number of arguments to %s", name))
+	}
+	if name == "isMemberOfAnyGroup" {
+		for _, a := range call.args {
+			lit, ok := a.(*exprLiteral)
+			if !ok {
+				return nil, errors.New("isMemberOfAnyGroup only accepts group IDs as string literals")
+			}
+			p.groupIDs = append(p.groupIDs, lit.value)
+		}
+	}
+	return call, nil
+}
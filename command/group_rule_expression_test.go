@@ -0,0 +1,106 @@
+package command
+
+import (
+	"testing"
+)
+
+func TestParseGroupRuleExpression(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		profile := map[string]interface{}{
+			"team":       "Platform",
+			"login":      "harry.potter@hogwarts.co.uk",
+			"title":      "Seeker",
+			"employeeNo": 1234,
+		}
+		groups := map[string]bool{"00g1emaKYZTWRYYRRTSK": true}
+
+		testCases := []struct {
+			expr     string
+			expected bool
+		}{
+			{`user.team == "Platform"`, true},
+			{`user.team=="Marketing"`, false},
+			{`user.team != "Marketing"`, true},
+			{`user.missing == "x"`, false},
+			{`user.employeeNo == "1234"`, true},
+			{`String.startsWith(user.login, "harry")`, true},
+			{`String.startsWith(user.login, "ron")`, false},
+			{`String.stringContains(user.login, "@hogwarts")`, true},
+			{`isMemberOfAnyGroup("00g000000000000000ZZ", "00g1emaKYZTWRYYRRTSK")`, true},
+			{`isMemberOfAnyGroup("00g000000000000000ZZ")`, false},
+			{`user.team == "Platform" && user.title == "Keeper"`, false},
+			{`user.team == "Platform" || user.title == "Keeper"`, true},
+			{`user.team == "Marketing" OR (user.title == "Seeker" AND !isMemberOfAnyGroup("00g000000000000000ZZ"))`, true},
+			{`user.team == "Marketing" or (user.title == "Seeker" and user.team == "Platform")`, true},
+			{`user.team == "Platform" And user.title == "Keeper"`, false},
+			{`!(user.team == "Platform")`, false},
+			{`user.title == 'Seeker'`, true},
+			{`user.team == "Plat\"form"`, false},
+		}
+
+		for _, tc := range testCases {
+			e, err := parseGroupRuleExpression(tc.expr)
+			if err != nil {
+				t.Errorf("Failed to parse %s: %v", tc.expr, err)
+				continue
+			}
+			res, err := e.Matches(profile, groups)
+			if err != nil {
+				t.Errorf("Failed to evaluate %s: %v", tc.expr, err)
+				continue
+			}
+			if res != tc.expected {
+				t.Errorf("Expected %s to evaluate to %v", tc.expr, tc.expected)
+			}
+		}
+	})
+
+	t.Run("group IDs", func(t *testing.T) {
+		t.Parallel()
+
+		e, err := parseGroupRuleExpression(`isMemberOfAnyGroup("a", "b") || isMemberOfAnyGroup("c")`)
+		if err != nil {
+			t.Fatalf("Failed to parse expression: %v", err)
+		}
+		if expected := []string{"a", "b", "c"}; !testEq(e.GroupIDs(), expected) {
+			t.Errorf("Expected group IDs %v, received %v", expected, e.GroupIDs())
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []string{
+			``,
+			`user.team ==`,
+			`user.team == "Platform`,
+			`(user.team == "Platform"`,
+			`user.team == "Platform")`,
+			`user.team = "Platform"`,
+			`group.name == "x"`,
+			`String.toUpperCase(user.team) == "X"`,
+			`String.startsWith(user.login)`,
+			`isMemberOfAnyGroup()`,
+			`isMemberOfAnyGroup(user.team)`,
+		}
+		for _, tc := range testCases {
+			if _, err := parseGroupRuleExpression(tc); err == nil {
+				t.Errorf("Expected %s to be invalid", tc)
+			}
+		}
+	})
+
+	t.Run("non-boolean", func(t *testing.T) {
+		t.Parallel()
+
+		e, err := parseGroupRuleExpression(`user.team`)
+		if err != nil {
+			t.Fatalf("Failed to parse expression: %v", err)
+		}
+		if _, err := e.Matches(map[string]interface{}{"team": "x"}, nil); err == nil {
+			t.Error("Expected evaluating a non-boolean expression to fail")
+		}
+	})
+}
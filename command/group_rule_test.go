@@ -0,0 +1,37 @@
+package command
+
+import (
+	"github.com/okta/okta-sdk-golang/okta"
+	"testing"
+)
+
+func TestFormatGroupRuleExpression(t *testing.T) {
+	t.Parallel()
+
+	groups := OktaGroups{
+		{Id: "00g1emaKYZTWRYYRRTSK", Profile: &okta.GroupProfile{Name: "Staff"}},
+	}
+	testCases := []struct {
+		expr, expected string
+	}{
+		{`user.team=="Platform"`, `user.team=="Platform"`},
+		{
+			`user.team=="Platform" && user.title!="Intern"`,
+			"user.team==\"Platform\"\n  && user.title!=\"Intern\"",
+		},
+		{
+			`user.team=="A || B" AND (user.x=="1" OR user.y=="2")`,
+			"user.team==\"A || B\"\n  AND (user.x==\"1\" OR user.y==\"2\")",
+		},
+		{
+			`isMemberOfAnyGroup("00g1emaKYZTWRYYRRTSK", "00g000000000000000ZZ")||String.startsWith(user.login,"a")`,
+			"isMemberOfAnyGroup(\"00g1emaKYZTWRYYRRTSK\" /* Staff */, \"00g000000000000000ZZ\")\n  || String.startsWith(user.login,\"a\")",
+		},
+	}
+
+	for _, tc := range testCases {
+		if res := formatGroupRuleExpression(tc.expr, groups); res != tc.expected {
+			t.Errorf("Expected:\n%s\nreceived:\n%s", tc.expected, res)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package command
+
+import (
+	"github.com/okta/okta-sdk-golang/okta"
+	"strings"
+	"testing"
+)
+
+func TestResolveGroup(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.groups = []*okta.Group{
+		fakeGroup("00g1", "Quidditch"),
+		fakeGroup("00g2", "Quidditch Team"),
+		fakeGroup("00g3", "gryffindor"),
+		fakeGroup("00g4", "Prefects"),
+		fakeGroup("00g5", "prefects"),
+		fakeGroup("00g6", "Order"),
+		fakeGroup("00g7", "Order"),
+		fakeGroup("00gabcdefghijklmnopq", "Slytherin"),
+	}
+
+	testCases := []struct{ group, expected string }{
+		{"Quidditch", "00g1"},
+		{"Quidditch Team", "00g2"},
+		{"Gryffindor", "00g3"},
+		{"prefects", "00g5"},
+		{"00gabcdefghijklmnopq", "00gabcdefghijklmnopq"},
+	}
+	for _, tc := range testCases {
+		g, err := resolveGroup(client, tc.group)
+		if err != nil {
+			t.Errorf("Failed to resolve %s: %v", tc.group, err)
+			continue
+		}
+		if g.Id != tc.expected {
+			t.Errorf("Expected %s to resolve to %s, received %s", tc.group, tc.expected, g.Id)
+		}
+	}
+
+	if _, err := resolveGroup(client, "Order"); err == nil || !strings.Contains(err.Error(), "00g6, 00g7") {
+		t.Errorf("Expected ambiguity error listing both IDs, received %v", err)
+	}
+	if _, err := resolveGroup(client, "PREFECTS"); err == nil || !strings.Contains(err.Error(), "00g4, 00g5") {
+		t.Errorf("Expected ambiguity error listing both IDs, received %v", err)
+	}
+
+	testCases = []struct{ group, expected string }{
+		{"Hufflepuff", "Hufflepuff does not exist"},
+		{"Quid", "Quid does not exist, groups whose name starts with it: Quidditch, Quidditch Team"},
+		{"00gzzzzzzzzzzzzzzzzz", "no group found with ID 00gzzzzzzzzzzzzzzzzz"},
+	}
+	for _, tc := range testCases {
+		if _, err := resolveGroup(client, tc.group); exitCode(err) != ExitNotFound || err.Error() != tc.expected {
+			t.Errorf("Expected %s not to be found with error %q, received %v", tc.group, tc.expected, err)
+		}
+	}
+
+	// Groups are searched for by name rather than listed
+	for _, call := range client.calls {
+		if call == "ListGroups" {
+			t.Errorf("Expected every group not to be listed, calls made: %v", client.calls)
+			break
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package command
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JournalEnv is the environment variable which overrides the path
+// of the journal.
+const JournalEnv = "OKTA_ADMIN_JOURNAL"
+
+// journalFile is the name of the journal in the okta-admin
+// directory of the user's configuration directory.
+const journalFile = "journal.jsonl"
+
+// Operations which undo the changes recorded in the journal.
+const (
+	opRemoveGroupMember   = "remove-group-member"
+	opAddGroupMember      = "add-group-member"
+	opActivateUser        = "activate-user"
+	opDeactivateUser      = "deactivate-user"
+	opActivateGroupRule   = "activate-group-rule"
+	opDeactivateGroupRule = "deactivate-group-rule"
+)
+
+// journalOp is an operation which undoes a change.
+type journalOp struct {
+	Op      string `json:"op"`
+	UserID  string `json:"userId,omitempty"`
+	GroupID string `json:"groupId,omitempty"`
+	RuleID  string `json:"ruleId,omitempty"`
+}
+
+// String describes what the operation does.
+func (o *journalOp) String() string {
+	switch o.Op {
+	case opRemoveGroupMember:
+		return fmt.Sprintf("Remove user %s from group %s", o.UserID, o.GroupID)
+	case opAddGroupMember:
+		return fmt.Sprintf("Add user %s to group %s", o.UserID, o.GroupID)
+	case opActivateUser:
+		return fmt.Sprintf("Activate user %s", o.UserID)
+	case opDeactivateUser:
+		return fmt.Sprintf("Deactivate user %s", o.UserID)
+	case opActivateGroupRule:
+		return fmt.Sprintf("Activate group rule %s", o.RuleID)
+	case opDeactivateGroupRule:
+		return fmt.Sprintf("Deactivate group rule %s", o.RuleID)
+	}
+	return fmt.Sprintf("Unknown operation %s", o.Op)
+}
+
+// journalChange is a change made to an organization and the
+// operation which undoes it, which is nil if it can't be undone.
+type journalChange struct {
+	Change  string     `json:"change"`
+	Inverse *journalOp `json:"inverse"`
+}
+
+// journalEntry is a line of the journal, describing the changes
+// made by a run of a command.
+type journalEntry struct {
+	ID      string    `json:"id"`
+	Time    time.Time `json:"time"`
+	OrgUrl  string    `json:"orgUrl"`
+	Command string    `json:"command"`
+	Args    []string  `json:"args"`
+	// Undoes is the ID of the entry whose changes the command undid
+	Undoes string `json:"undoes,omitempty"`
+	// Incomplete is set if some of the changes of the entry weren't
+	// undone, so that undoing them can be retried
+	Incomplete bool             `json:"incomplete,omitempty"`
+	Changes    []*journalChange `json:"changes"`
+}
+
+// journalPath returns the path of the journal, which is specified
+// by the OKTA_ADMIN_JOURNAL environment variable, or is
+// journal.jsonl in the okta-admin directory of the user's
+// configuration directory, eg- ~/.config/okta-admin on Linux.
+func journalPath() (string, error) {
+	if path := os.Getenv(JournalEnv); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "okta-admin", journalFile), nil
+}
+
+// readJournal returns the entries of the journal, in the order they
+// were recorded, along with the number of lines which aren't valid
+// entries.
+func readJournal() ([]*journalEntry, int, error) {
+	path, err := journalPath()
+	if err != nil {
+		return nil, 0, err
+	}
+	var entries []*journalEntry
+	invalid, err := readLines(path, func(line []byte) bool {
+		var e journalEntry
+		if json.Unmarshal(line, &e) != nil || e.ID == "" {
+			return false
+		}
+		entries = append(entries, &e)
+		return true
+	})
+	return entries, invalid, err
+}
+
+// newJournalID returns a random ID for a journal entry.
+func newJournalID() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// sameOrgUrl returns whether both URLs are of the same organization.
+func sameOrgUrl(a, b string) bool {
+	return strings.EqualFold(strings.TrimRight(a, "/"), strings.TrimRight(b, "/"))
+}
+
+// changeJournal collects the changes a command makes via a
+// journalingClient.
+type changeJournal struct {
+	mu      sync.Mutex
+	changes []*journalChange
+	// undoes is the ID of the entry the command is undoing, and
+	// incomplete is set if some of its changes weren't undone
+	undoes     string
+	incomplete bool
+
+	// groups contains the IDs of the groups of users, fetched to
+	// tell whether adding them to or removing them from a group
+	// changes their membership
+	groups map[string]map[string]bool
+}
+
+func (j *changeJournal) add(change string, inverse *journalOp) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.changes = append(j.changes, &journalChange{Change: change, Inverse: inverse})
+}
+
+func (j *changeJournal) setUndoes(id string, incomplete bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.undoes, j.incomplete = id, incomplete
+}
+
+// reset returns the changes collected so far, the ID of the entry
+// they undo and whether some of its changes weren't undone, and
+// forgets them along with the memberships fetched.
+func (j *changeJournal) reset() ([]*journalChange, string, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	changes, undoes, incomplete := j.changes, j.undoes, j.incomplete
+	j.changes, j.undoes, j.incomplete, j.groups = nil, "", false, nil
+	return changes, undoes, incomplete
+}
+
+// isMember returns whether the user is a member of the group,
+// fetching the user's groups the first time a user is asked about.
+// The lock isn't held while they're fetched, so that the groups of
+// several users can be fetched concurrently.
+func (j *changeJournal) isMember(client oktaapi.Client, userID, groupID string) (bool, error) {
+	j.mu.Lock()
+	groups, ok := j.groups[userID]
+	j.mu.Unlock()
+	if ok {
+		return groups[groupID], nil
+	}
+
+	res, resp, err := client.ListUserGroups(userID, nil)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, errors.New(fmt.Sprintf("failed to fetch groups of user %s (%s)", userID, resp.Status))
+	}
+	groups = map[string]bool{}
+	for _, g := range res {
+		groups[g.Id] = true
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	// The groups may have been fetched meanwhile and changed since
+	if fetched, ok := j.groups[userID]; ok {
+		return fetched[groupID], nil
+	}
+	if j.groups == nil {
+		j.groups = map[string]map[string]bool{}
+	}
+	j.groups[userID] = groups
+	return groups[groupID], nil
+}
+
+// setMember records whether the user is a member of the group, if
+// the user's groups were fetched.
+func (j *changeJournal) setMember(userID, groupID string, member bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if groups, ok := j.groups[userID]; ok {
+		groups[groupID] = member
+	}
+}
+
+// journalingClient records the changes made via the client which
+// can be undone in the journal, along with the operations which
+// undo them. Adding a user to a group they're already a member of,
+// or removing them from one they aren't, changes nothing, so the
+// user's groups are fetched before their membership is changed.
+type journalingClient struct {
+	oktaapi.Client
+	journal *changeJournal
+}
+
+func (j *journalingClient) CreateUser(user okta.User, qp *query.Params) (*okta.User, *okta.Response, error) {
+	res, resp, err := j.Client.CreateUser(user, qp)
+	if err == nil && res != nil {
+		j.journal.add(fmt.Sprintf("Created user %s", res.Id), &journalOp{Op: opDeactivateUser, UserID: res.Id})
+	}
+	return res, resp, err
+}
+
+func (j *journalingClient) DeactivateUser(userID string, qp *query.Params) (*okta.Response, error) {
+	resp, err := j.Client.DeactivateUser(userID, qp)
+	if err == nil && resp.StatusCode == http.StatusOK {
+		j.journal.add(fmt.Sprintf("Deactivated user %s", userID), &journalOp{Op: opActivateUser, UserID: userID})
+	}
+	return resp, err
+}
+
+func (j *journalingClient) ActivateUser(userID string, qp *query.Params) (*okta.UserActivationToken, *okta.Response, error) {
+	res, resp, err := j.Client.ActivateUser(userID, qp)
+	if err == nil && resp.StatusCode == http.StatusOK {
+		j.journal.add(fmt.Sprintf("Activated user %s", userID), &journalOp{Op: opDeactivateUser, UserID: userID})
+	}
+	return res, resp, err
+}
+
+func (j *journalingClient) AddUserToGroup(groupID, userID string) (*okta.Response, error) {
+	member, checkErr := j.journal.isMember(j.Client, userID, groupID)
+	resp, err := j.Client.AddUserToGroup(groupID, userID)
+	if err != nil || resp.StatusCode != http.StatusNoContent {
+		return resp, err
+	}
+
+	change := fmt.Sprintf("Added user %s to group %s", userID, groupID)
+	switch {
+	case checkErr != nil:
+		// The user may have been a member already, so removing
+		// them from the group might not restore their groups
+		j.journal.add(fmt.Sprintf("%s (can't be undone: %v)", change, checkErr), nil)
+	case !member:
+		j.journal.add(change, &journalOp{Op: opRemoveGroupMember, UserID: userID, GroupID: groupID})
+		j.journal.setMember(userID, groupID, true)
+	}
+	return resp, err
+}
+
+func (j *journalingClient) RemoveUserFromGroup(groupID, userID string) (*okta.Response, error) {
+	member, checkErr := j.journal.isMember(j.Client, userID, groupID)
+	resp, err := j.Client.RemoveUserFromGroup(groupID, userID)
+	if err != nil || resp.StatusCode != http.StatusNoContent {
+		return resp, err
+	}
+
+	change := fmt.Sprintf("Removed user %s from group %s", userID, groupID)
+	switch {
+	case checkErr != nil:
+		j.journal.add(fmt.Sprintf("%s (can't be undone: %v)", change, checkErr), nil)
+	case member:
+		j.journal.add(change, &journalOp{Op: opAddGroupMember, UserID: userID, GroupID: groupID})
+		j.journal.setMember(userID, groupID, false)
+	}
+	return resp, err
+}
+
+func (j *journalingClient) ActivateGroupRule(ruleID string) (*okta.Response, error) {
+	resp, err := j.Client.ActivateGroupRule(ruleID)
+	if err == nil && resp.StatusCode == http.StatusNoContent {
+		j.journal.add(fmt.Sprintf("Activated group rule %s", ruleID), &journalOp{Op: opDeactivateGroupRule, RuleID: ruleID})
+	}
+	return resp, err
+}
+
+func (j *journalingClient) DeactivateGroupRule(ruleID string) (*okta.Response, error) {
+	resp, err := j.Client.DeactivateGroupRule(ruleID)
+	if err == nil && resp.StatusCode == http.StatusNoContent {
+		j.journal.add(fmt.Sprintf("Deactivated group rule %s", ruleID), &journalOp{Op: opActivateGroupRule, RuleID: ruleID})
+	}
+	return resp, err
+}
+
+// RecordJournal appends the changes made by the run of the command
+// with the name and arguments to the journal, so that they can be
+// undone by the undo command. Nothing is recorded if the command
+// made no changes which are journaled. Failing to record them is
+// reported, since they can't be undone then.
+func (c *Command) RecordJournal(name string, args []string) {
+	changes, undoes, incomplete := c.journal.reset()
+	if len(changes) == 0 {
+		return
+	}
+
+	err := func() error {
+		id, err := newJournalID()
+		if err != nil {
+			return err
+		}
+		line, err := json.Marshal(&journalEntry{
+			ID:         id,
+			Time:       time.Now().UTC(),
+			OrgUrl:     c.Meta.GlobalOptions.OrgUrl,
+			Command:    name,
+			Args:       maskSecrets(args),
+			Undoes:     undoes,
+			Incomplete: incomplete,
+			Changes:    changes,
+		})
+		if err != nil {
+			return err
+		}
+		path, err := journalPath()
+		if err != nil {
+			return err
+		}
+		return appendLine(path, line)
+	}()
+	if err != nil {
+		c.logError("Failed to record the changes in the journal, so they can't be undone", err)
+	}
+}
@@ -0,0 +1,109 @@
+package command
+
+import (
+	"github.com/okta/okta-sdk-golang/okta"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestJournalingClient(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.users = []*okta.User{
+		fakeUser("00u1", "harry.potter@hogwarts.co.uk", "ACTIVE"),
+		fakeUser("00u2", "ron.weasley@hogwarts.co.uk", "ACTIVE"),
+	}
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor"), fakeGroup("00g2", "Quidditch")}
+	client.members["00g2"] = []string{"00u2"}
+	client.rules = []*okta.GroupRule{{Id: "0pr1", Name: "Seekers", Status: groupRuleStatusActive}}
+
+	var journal changeJournal
+	j := &journalingClient{Client: client, journal: &journal}
+
+	// Changes which change nothing aren't journaled
+	for _, call := range []func() (*okta.Response, error){
+		func() (*okta.Response, error) { return j.AddUserToGroup("00g1", "00u1") },
+		func() (*okta.Response, error) { return j.AddUserToGroup("00g1", "00u1") },
+		func() (*okta.Response, error) { return j.AddUserToGroup("00g2", "00u2") },
+		func() (*okta.Response, error) { return j.RemoveUserFromGroup("00g2", "00u2") },
+		func() (*okta.Response, error) { return j.RemoveUserFromGroup("00g2", "00u1") },
+		func() (*okta.Response, error) { return j.DeactivateUser("00u1", nil) },
+		func() (*okta.Response, error) { return j.DeactivateGroupRule("0pr1") },
+	} {
+		if _, err := call(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	changes, undoes, _ := journal.reset()
+	expected := []journalOp{
+		{Op: opRemoveGroupMember, UserID: "00u1", GroupID: "00g1"},
+		{Op: opAddGroupMember, UserID: "00u2", GroupID: "00g2"},
+		{Op: opActivateUser, UserID: "00u1"},
+		{Op: opActivateGroupRule, RuleID: "0pr1"},
+	}
+	if len(changes) != len(expected) || undoes != "" {
+		t.Fatalf("Expected %d changes to be journaled, received %d (undoes %q)", len(expected), len(changes), undoes)
+	}
+	for i, ch := range changes {
+		if ch.Inverse == nil || *ch.Inverse != expected[i] {
+			t.Errorf("Expected %q to be undone by %+v, received %+v", ch.Change, expected[i], ch.Inverse)
+		}
+	}
+
+	// Memberships are fetched once per user
+	n := 0
+	for _, call := range client.calls {
+		if call == "ListUserGroups 00u1" || call == "ListUserGroups 00u2" {
+			n++
+		}
+	}
+	if n != 2 {
+		t.Errorf("Expected groups of each user to be fetched once, calls made: %v", client.calls)
+	}
+}
+
+// barrierClient waits for the groups of every user to be requested
+// before returning any of them.
+type barrierClient struct {
+	*fakeOktaClient
+	wg *sync.WaitGroup
+}
+
+func (b *barrierClient) ListUserGroups(userID string, qp *query.Params) ([]*okta.Group, *okta.Response, error) {
+	b.wg.Done()
+	b.wg.Wait()
+	return b.fakeOktaClient.ListUserGroups(userID, qp)
+}
+
+func TestChangeJournal_isMemberConcurrent(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor")}
+	client.members["00g1"] = []string{"00u1"}
+	barrier := &barrierClient{fakeOktaClient: client, wg: &sync.WaitGroup{}}
+	barrier.wg.Add(2)
+
+	var journal changeJournal
+	done := make(chan bool, 2)
+	for _, uid := range []string{"00u1", "00u2"} {
+		go func(uid string) {
+			member, err := journal.isMember(barrier, uid, "00g1")
+			done <- err == nil && member == (uid == "00u1")
+		}(uid)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case ok := <-done:
+			if !ok {
+				t.Errorf("Unexpected membership")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected the groups of both users to be fetched concurrently")
+		}
+	}
+}
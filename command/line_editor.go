@@ -0,0 +1,296 @@
+package command
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Control characters and keys handled by lineEditor
+const (
+	keyCtrlA     = 0x01
+	keyCtrlB     = 0x02
+	keyCtrlC     = 0x03
+	keyCtrlD     = 0x04
+	keyCtrlE     = 0x05
+	keyCtrlF     = 0x06
+	keyBackspace = 0x08
+	keyTab       = 0x09
+	keyCtrlK     = 0x0b
+	keyCtrlN     = 0x0e
+	keyCtrlP     = 0x10
+	keyCtrlU     = 0x15
+	keyCtrlW     = 0x17
+	keyEscape    = 0x1b
+	keyDelete    = 0x7f
+)
+
+// lineEditor reads lines typed in a terminal which neither buffers
+// nor echoes its input, so that lines can be edited in place,
+// recalled from the history with the arrow keys and completed with
+// Tab. Only single-width characters are supported.
+type lineEditor struct {
+	in      *bufio.Reader
+	out     io.Writer
+	history []string
+
+	// complete returns the candidates for completing the line up
+	// to the cursor, and the position the text they replace
+	// starts at.
+	complete func(line []rune) (start int, candidates []string)
+}
+
+func newLineEditor(in io.Reader, out io.Writer) *lineEditor {
+	return &lineEditor{in: bufio.NewReader(in), out: out}
+}
+
+// addHistory appends the line to the history, unless it is blank
+// or the same as the previous line.
+func (e *lineEditor) addHistory(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	if n := len(e.history); n > 0 && e.history[n-1] == line {
+		return
+	}
+	e.history = append(e.history, line)
+}
+
+// readLine prints the prompt and returns the line typed after it.
+// Ctrl-C discards the line typed so far and starts a new one. It
+// returns io.EOF once the input ends or Ctrl-D is typed on an
+// empty line.
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	var (
+		buf   []rune
+		pos   int
+		saved []rune
+		hist  = len(e.history)
+	)
+
+	refresh := func() {
+		fmt.Fprintf(e.out, "\r%s%s\x1b[K", prompt, string(buf))
+		if n := len(buf) - pos; n > 0 {
+			fmt.Fprintf(e.out, "\x1b[%dD", n)
+		}
+	}
+	recall := func(i int) {
+		if hist == len(e.history) {
+			saved = buf
+		}
+		hist = i
+		if hist == len(e.history) {
+			buf = saved
+		} else {
+			buf = []rune(e.history[hist])
+		}
+		pos = len(buf)
+	}
+	replace := func(start int, text []rune) {
+		buf = append(append(append([]rune{}, buf[:start]...), text...), buf[pos:]...)
+		pos = start + len(text)
+	}
+
+	refresh()
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			if err == io.EOF && len(buf) > 0 {
+				fmt.Fprintln(e.out)
+				return string(buf), nil
+			}
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			fmt.Fprintln(e.out)
+			return string(buf), nil
+		case keyCtrlC:
+			fmt.Fprintln(e.out, "^C")
+			buf, pos, hist = nil, 0, len(e.history)
+		case keyCtrlD:
+			if len(buf) == 0 {
+				fmt.Fprintln(e.out)
+				return "", io.EOF
+			}
+			if pos < len(buf) {
+				buf = append(buf[:pos], buf[pos+1:]...)
+			}
+		case keyBackspace, keyDelete:
+			if pos > 0 {
+				replace(pos-1, nil)
+			}
+		case keyCtrlA:
+			pos = 0
+		case keyCtrlE:
+			pos = len(buf)
+		case keyCtrlB:
+			if pos > 0 {
+				pos--
+			}
+		case keyCtrlF:
+			if pos < len(buf) {
+				pos++
+			}
+		case keyCtrlK:
+			buf = buf[:pos]
+		case keyCtrlU:
+			buf, pos = buf[pos:], 0
+		case keyCtrlW:
+			start := pos
+			for start > 0 && buf[start-1] == ' ' {
+				start--
+			}
+			for start > 0 && buf[start-1] != ' ' {
+				start--
+			}
+			buf = append(buf[:start], buf[pos:]...)
+			pos = start
+		case keyCtrlP:
+			if hist > 0 {
+				recall(hist - 1)
+			}
+		case keyCtrlN:
+			if hist < len(e.history) {
+				recall(hist + 1)
+			}
+		case keyTab:
+			e.completeLine(buf, pos, replace)
+		case keyEscape:
+			switch e.readEscapeSequence() {
+			case "[A", "OA":
+				if hist > 0 {
+					recall(hist - 1)
+				}
+			case "[B", "OB":
+				if hist < len(e.history) {
+					recall(hist + 1)
+				}
+			case "[C", "OC":
+				if pos < len(buf) {
+					pos++
+				}
+			case "[D", "OD":
+				if pos > 0 {
+					pos--
+				}
+			case "[H", "OH", "[1~", "[7~":
+				pos = 0
+			case "[F", "OF", "[4~", "[8~":
+				pos = len(buf)
+			case "[3~":
+				if pos < len(buf) {
+					buf = append(buf[:pos], buf[pos+1:]...)
+				}
+			}
+		default:
+			if r >= ' ' {
+				replace(pos, []rune{r})
+			}
+		}
+		refresh()
+	}
+}
+
+// readEscapeSequence reads the rest of an escape sequence sent by
+// a key, eg- "[A" for the up arrow.
+func (e *lineEditor) readEscapeSequence() string {
+	var seq []rune
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return string(seq)
+		}
+		seq = append(seq, r)
+		// Sequences are terminated by a letter or ~, after the
+		// introducer [ or O
+		if len(seq) > 1 && (r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r == '~') {
+			return string(seq)
+		}
+		if len(seq) == 1 && r != '[' && r != 'O' {
+			return string(seq)
+		}
+	}
+}
+
+// completeLine completes the text before the cursor. A single
+// candidate replaces the text, followed by a space. Otherwise the
+// text is extended to the longest prefix the candidates share or,
+// if it can't be, the candidates are listed.
+func (e *lineEditor) completeLine(buf []rune, pos int, replace func(start int, text []rune)) {
+	if e.complete == nil {
+		return
+	}
+	start, candidates := e.complete(buf[:pos])
+	switch len(candidates) {
+	case 0:
+		fmt.Fprint(e.out, "\a")
+	case 1:
+		replace(start, []rune(candidates[0]+" "))
+	default:
+		if prefix := []rune(commonPrefix(candidates)); len(prefix) > pos-start {
+			replace(start, prefix)
+			return
+		}
+		sorted := append([]string{}, candidates...)
+		sort.Strings(sorted)
+		fmt.Fprintf(e.out, "\n%s\n", strings.Join(sorted, "  "))
+	}
+}
+
+// commonPrefix returns the longest prefix shared by all strings.
+func commonPrefix(strs []string) string {
+	if len(strs) == 0 {
+		return ""
+	}
+	prefix := []rune(strs[0])
+	for _, s := range strs[1:] {
+		r := []rune(s)
+		n := 0
+		for n < len(prefix) && n < len(r) && prefix[n] == r[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
+
+// isTerminal returns true if the file is a character device, such
+// as a terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// rawTerminal stops the terminal f from buffering and echoing its
+// input and from turning Ctrl-C into SIGINT, so that lineEditor
+// can handle every key. The returned func restores the terminal's
+// previous settings. It uses stty, so it fails if the terminal
+// doesn't support it, eg- on Windows.
+func rawTerminal(f *os.File) (restore func() error, err error) {
+	state, err := stty(f, "-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty(f, "-icanon", "-echo", "-isig", "min", "1", "time", "0"); err != nil {
+		return nil, err
+	}
+	return func() error {
+		_, err := stty(f, state)
+		return err
+	}, nil
+}
+
+// stty runs stty with the args against the terminal f and returns
+// its output.
+func stty(f *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = f
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
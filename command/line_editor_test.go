@@ -0,0 +1,101 @@
+package command
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLineEditor_readLine(t *testing.T) {
+	t.Parallel()
+
+	complete := func(line []rune) (int, []string) {
+		words, start, _ := shellWords(line)
+		if len(words) == 0 || start == len(line) {
+			return start, nil
+		}
+		var res []string
+		for _, c := range []string{"list-groups", "list-policies", "logs"} {
+			if strings.HasPrefix(c, words[len(words)-1]) {
+				res = append(res, c)
+			}
+		}
+		return start, res
+	}
+
+	testCases := []struct {
+		name, input string
+		expected    []string
+	}{
+		{"plain lines", "list-groups\nlogs -since 1h\n", []string{"list-groups", "logs -since 1h"}},
+		{"backspace and cursor movement", "lgs\x1b[D\x1b[Do\x1b[C\x1b[C\x7fs\n", []string{"logs"}},
+		{"home, end and kill", "groups\x01list-\x05 -detailed\x17\x0b\n", []string{"list-groups "}},
+		{"Ctrl-U kills the line before the cursor", "foo bar\x1b[D\x1b[D\x1b[D\x15\x05\n", []string{"bar"}},
+		{"Ctrl-C discards the line", "deactivate-user\x03logs\n", []string{"logs"}},
+		{"single completion", "lo\t-since 1h\n", []string{"logs -since 1h"}},
+		{"common prefix completion", "li\tg\t\n", []string{"list-groups "}},
+		{"history", "logs\nlist-groups\n\x1b[A\x1b[A\n\x1b[A\x1b[A\x1b[A\x1b[B\n", []string{"logs", "list-groups", "logs", "list-groups"}},
+		{"history keeps the line being typed", "logs\nlist\x1b[A\x1b[B-groups\n", []string{"logs", "list-groups"}},
+		{"input ending without newline", "logs", []string{"logs"}},
+	}
+
+	for _, tc := range testCases {
+		out := &bytes.Buffer{}
+		e := newLineEditor(strings.NewReader(tc.input), out)
+		e.complete = complete
+
+		var lines []string
+		for {
+			line, err := e.readLine("> ")
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error %v", tc.name, err)
+			}
+			e.addHistory(line)
+			lines = append(lines, line)
+		}
+		if !testEq(lines, tc.expected) {
+			t.Errorf("%s: expected lines %q, received %q", tc.name, tc.expected, lines)
+		}
+	}
+}
+
+func TestLineEditor_readLineListsCandidates(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+	e := newLineEditor(strings.NewReader("l\t\x04"), out)
+	e.complete = func(line []rune) (int, []string) {
+		return 0, []string{"logs", "list-groups"}
+	}
+
+	line, err := e.readLine("> ")
+	if err != nil || line != "l" {
+		t.Errorf("Expected line %q, received %q (error %v)", "l", line, err)
+	}
+	if !strings.Contains(out.String(), "\nlist-groups  logs\n") {
+		t.Errorf("Expected candidates to be listed, received %q", out)
+	}
+}
+
+func TestCommonPrefix(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input    []string
+		expected string
+	}{
+		{nil, ""},
+		{[]string{"list-groups"}, "list-groups"},
+		{[]string{"list-groups", "list-policies", "list-group-rules"}, "list-"},
+		{[]string{"logs", "audit-admins"}, ""},
+	}
+	for _, tc := range testCases {
+		if res := commonPrefix(tc.input); res != tc.expected {
+			t.Errorf("Expected common prefix of %q to be %q, received %q", tc.input, tc.expected, res)
+		}
+	}
+}
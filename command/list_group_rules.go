@@ -0,0 +1,98 @@
+package command
+
+import (
+	"strings"
+)
+
+type ListGroupRulesCommand struct {
+	*Command
+}
+
+type ListGroupRulesCommandConfig struct {
+	Detailed bool
+}
+
+func (c *ListGroupRulesCommand) Synopsis() string {
+	return "List group rules in the organization"
+}
+
+func (c *ListGroupRulesCommand) Help() string {
+	helpText := `
+Usage: okta-admin list-group-rules [options]
+
+  Lists the rules which dynamically assign users to groups.
+  If no arguments are specified, this command lists the names
+  and statuses of all rules.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -detailed Whether to display detailed information about the
+            rules, including their expressions and the groups
+            they assign users to.
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *ListGroupRulesCommand) ParseArgs(args []string) (*ListGroupRulesCommandConfig, error) {
+	var cfg ListGroupRulesCommandConfig
+
+	flags := c.Meta.FlagSet
+	flags.BoolVar(&cfg.Detailed, "detailed", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+	)
+	return &cfg, err
+}
+
+func (c *ListGroupRulesCommand) Run(args []string) int {
+	var groups OktaGroups
+
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+
+	rules, err := listAllGroupRules(client, nil)
+	if err != nil {
+		c.logError("Failed to fetch group rules list", err)
+		return exitCode(err)
+	}
+
+	// Group names are only displayed in detailed output
+	if cfg.Detailed {
+		if groups, err = listAllGroups(client, nil); err != nil {
+			c.logError("Failed to fetch groups list", err)
+			return exitCode(err)
+		}
+	}
+
+	for _, r := range rules {
+		if cfg.Detailed {
+			c.Logger.Println(getGroupRuleDetailsPretty(r, groups))
+			c.Logger.Println(strings.Repeat("=", 75))
+		} else {
+			c.Logger.Printf("%s [%s]\n", r.Name, r.Status)
+		}
+	}
+
+	return 0
+}
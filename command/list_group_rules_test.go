@@ -0,0 +1,69 @@
+package command
+
+import (
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta"
+	"strings"
+	"testing"
+)
+
+func createTestListGroupRulesCommand(globalOptsHelpText string) *ListGroupRulesCommand {
+	return &ListGroupRulesCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_list_group_rules_cmd"),
+	}
+}
+
+func TestListGroupRulesCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestListGroupRulesCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestListGroupRulesCommand_ParseArgs(t *testing.T) {
+	t.Parallel()
+
+	c := createTestListGroupRulesCommand("")
+	cfg, err := c.ParseArgs([]string{"-detailed"})
+	if err != nil {
+		t.Fatalf("Failed to parse arguments: %v", err)
+	}
+
+	if !cfg.Detailed {
+		t.Errorf("Expected -detailed flag to be set")
+	}
+}
+
+func TestListGroupRulesCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor")}
+	client.rules = []*okta.GroupRule{
+		fakeGroupRule("0pr1", "Seekers", groupRuleStatusActive, `user.title == "Seeker"`, "00g1"),
+		fakeGroupRule("0pr2", "Keepers", "INACTIVE", `user.title == "Keeper"`),
+	}
+
+	c, out := createTestCommandWithClient("test_list_group_rules_cmd", client)
+	if code := (&ListGroupRulesCommand{Command: c}).Run(nil); code != ExitOK {
+		t.Fatalf("Expected exit code %d, received %d: %s", ExitOK, code, out)
+	}
+	if expected := "Seekers [ACTIVE]\nKeepers [INACTIVE]\n"; out.String() != expected {
+		t.Errorf("Expected output %q, received %q", expected, out)
+	}
+
+	c, out = createTestCommandWithClient("test_list_group_rules_cmd", client)
+	if code := (&ListGroupRulesCommand{Command: c}).Run([]string{"-detailed"}); code != ExitOK {
+		t.Fatalf("Expected exit code %d, received %d: %s", ExitOK, code, out)
+	}
+	for _, expected := range []string{"ID:     0pr1", "Gryffindor (00g1)", "ID:     0pr2", strings.Repeat("=", 75)} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected detailed output to contain %q, received %q", expected, out)
+		}
+	}
+
+	client.errs["ListGroupRules"] = &oktaapi.ApiError{StatusCode: 403, Summary: "You do not have permission"}
+	c, out = createTestCommandWithClient("test_list_group_rules_cmd", client)
+	if code := (&ListGroupRulesCommand{Command: c}).Run(nil); code != ExitAuth {
+		t.Errorf("Expected exit code %d, received %d: %s", ExitAuth, code, out)
+	}
+}
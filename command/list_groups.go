@@ -68,24 +68,24 @@ func (c *ListGroupsCommand) ParseArgs(args []string) (*ListGroupsCommandConfig,
 func (c *ListGroupsCommand) Run(args []string) int {
 	cfg, err := c.ParseArgs(args)
 	if err != nil {
-		c.Logger.Printf("Failed to parse arguments: %v\n", err)
-		return 1
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
 	}
 
 	client, err := c.OktaClient()
 	if err != nil {
-		c.Logger.Printf("Failed to initialize Okta client: %v\n", err)
-		return 1
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
 	}
 
-	groups, resp, err := client.Group.ListGroups(nil)
+	groups, resp, err := client.ListGroups(nil)
 	if err != nil {
-		c.Logger.Printf("Failed to fetch groups list: %v\n", err)
-		return 1
+		c.logError("Failed to fetch groups list", err)
+		return exitCode(err)
 	}
 	if resp.StatusCode != http.StatusOK {
 		c.Logger.Printf("Failed to fetch groups list: %s\n", resp.Status)
-		return 1
+		return ExitFailure
 	}
 
 	// Filter groups if names are supplied
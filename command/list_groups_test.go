@@ -1,6 +1,7 @@
 package command
 
 import (
+	"github.com/okta/okta-sdk-golang/okta"
 	"testing"
 )
 
@@ -63,3 +64,19 @@ func TestListGroupsCommand_ParseArgs(t *testing.T) {
 		}
 	})
 }
+
+func TestListGroupsCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor"), fakeGroup("00g2", "Slytherin"), fakeGroup("00g3", "Hufflepuff")}
+	c, out := createTestCommandWithClient("test_list_groups_cmd", client)
+	cmd := &ListGroupsCommand{Command: c}
+
+	if code := cmd.Run([]string{"-groups", "Hufflepuff,Gryffindor"}); code != 0 {
+		t.Fatalf("Expected exit code 0, received %d: %s", code, out)
+	}
+	if out.String() != "Gryffindor\nHufflepuff\n" {
+		t.Errorf("Expected only the specified groups to be listed, received %q", out)
+	}
+}
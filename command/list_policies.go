@@ -0,0 +1,111 @@
+package command
+
+import (
+	"github.com/okta/okta-sdk-golang/okta"
+	"strings"
+)
+
+type ListPoliciesCommand struct {
+	*Command
+}
+
+type ListPoliciesCommandConfig struct {
+	Type   string
+	Format string
+}
+
+func (c *ListPoliciesCommand) Synopsis() string {
+	return "List sign-on, password or MFA enrollment policies"
+}
+
+func (c *ListPoliciesCommand) Help() string {
+	helpText := `
+Usage: okta-admin list-policies [options]
+
+  Lists the policies of a type in the order Okta evaluates them,
+  along with the groups and users each policy applies to. A user
+  is governed by the first active policy that applies to them.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -type   Type of the policies, one of OKTA_SIGN_ON, PASSWORD or
+          MFA_ENROLL
+  -format Output format, either text or json. The json format
+          contains the policies exactly as returned by Okta.
+          (Default: text)
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *ListPoliciesCommand) ParseArgs(args []string) (*ListPoliciesCommandConfig, error) {
+	var cfg ListPoliciesCommandConfig
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.Type, "type", "", "")
+	flags.StringVar(&cfg.Format, "format", policyFormatText, "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "format", Required: true, Value: cfg.Format, ValidationFunc: ValidateOneOf(policyFormatText, reportFormatJSON)},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+		&parameter{Name: "type", Required: true, Value: cfg.Type, ValidationFunc: ValidateOneOf(policyTypes...)},
+	)
+	return &cfg, err
+}
+
+func (c *ListPoliciesCommand) Run(args []string) int {
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+
+	policies, err := listPolicies(client, cfg.Type)
+	if err != nil {
+		c.logError("Failed to fetch list of policies", err)
+		return exitCode(err)
+	}
+
+	if cfg.Format == reportFormatJSON {
+		if err := writePoliciesJSON(c.Logger.Writer(), policies); err != nil {
+			c.logError("Failed to write policies", err)
+			return exitCode(err)
+		}
+		return 0
+	}
+
+	groups, err := listAllGroups(client, nil)
+	if err != nil {
+		c.logError("Failed to fetch groups list", err)
+		return exitCode(err)
+	}
+
+	for _, p := range policies {
+		c.Logger.Printf("%d. %s [%s]\n", p.Priority, p.Name, p.Status)
+		var people *okta.PolicyPeopleCondition
+		if p.Conditions != nil {
+			people = p.Conditions.People
+		}
+		for _, l := range describePeopleCondition(people, groups) {
+			c.Logger.Printf("   %s\n", strings.Replace(l, "\t", " ", 1))
+		}
+	}
+
+	return 0
+}
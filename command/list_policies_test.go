@@ -0,0 +1,76 @@
+package command
+
+import (
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta"
+	"testing"
+)
+
+func createTestListPoliciesCommand(globalOptsHelpText string) *ListPoliciesCommand {
+	return &ListPoliciesCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_list_policies_cmd"),
+	}
+}
+
+func TestListPoliciesCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestListPoliciesCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestListPoliciesCommand_ParseArgs(t *testing.T) {
+	t.Run("with type", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestListPoliciesCommand("")
+		cfg, err := c.ParseArgs([]string{"-type", policyTypePassword})
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.Type != policyTypePassword {
+			t.Errorf("Expected type to be %s, received %s", policyTypePassword, cfg.Type)
+		}
+		if cfg.Format != policyFormatText {
+			t.Errorf("Expected format to be %s, received %s", policyFormatText, cfg.Format)
+		}
+	})
+
+	t.Run("with invalid arguments", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := [][]string{
+			{},
+			{"-type", "IDP_DISCOVERY"},
+			{"-type", policyTypeSignOn, "-format", "csv"},
+		}
+		for _, tc := range testCases {
+			c := createTestListPoliciesCommand("")
+			if _, err := c.ParseArgs(tc); err == nil {
+				t.Errorf("Expected args %v to be invalid", tc)
+			}
+		}
+	})
+}
+
+func TestListPoliciesCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.groups = []*okta.Group{fakeGroup("00g1", "Everyone")}
+	client.policies = []*oktaapi.Policy{
+		{Id: "00p2", Name: "Staff", Type: policyTypePassword, Status: "ACTIVE", Priority: 2},
+		{Id: "00p1", Name: "Default", Type: policyTypePassword, Status: "ACTIVE", Priority: 1},
+		{Id: "00p3", Name: "Sign-on", Type: policyTypeSignOn, Status: "ACTIVE", Priority: 1},
+	}
+	c, out := createTestCommandWithClient("test_list_policies_cmd", client)
+	cmd := &ListPoliciesCommand{Command: c}
+
+	if code := cmd.Run([]string{"-type", policyTypePassword}); code != 0 {
+		t.Fatalf("Expected exit code 0, received %d: %s", code, out)
+	}
+	expected := "1. Default [ACTIVE]\n   People: Everyone\n2. Staff [ACTIVE]\n   People: Everyone\n"
+	if out.String() != expected {
+		t.Errorf("Expected output %q, received %q", expected, out)
+	}
+}
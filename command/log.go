@@ -0,0 +1,144 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"strings"
+	"time"
+)
+
+const (
+	logFormatJSON    = "json"
+	logFormatCompact = "compact"
+
+	// logsPageSize is the maximum number of events Okta
+	// returns in a single page of the System Log.
+	logsPageSize = 1000
+
+	// logsTimeLayout formats timestamps with the millisecond
+	// precision of System Log events.
+	logsTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// createLogsFilter combines System Log filter expressions into a
+// single expression that matches events satisfying all of them.
+// Empty expressions are ignored.
+func createLogsFilter(exprs ...string) string {
+	res := make([]string, 0, len(exprs))
+	for _, e := range exprs {
+		if strings.TrimSpace(e) != "" {
+			res = append(res, fmt.Sprintf("(%s)", e))
+		}
+	}
+	if len(res) == 1 {
+		return strings.TrimSuffix(strings.TrimPrefix(res[0], "("), ")")
+	}
+	return strings.Join(res, " and ")
+}
+
+// listAllLogs fetches every page of System Log events matching
+// the query params supplied to it. The query must specify an upper
+// bound (until), otherwise Okta keeps advertising a next page.
+func listAllLogs(client oktaapi.Client, qp *query.Params) ([]*oktaapi.LogEvent, error) {
+	var res []*oktaapi.LogEvent
+
+	for {
+		events, resp, err := client.GetLogs(qp)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, events...)
+
+		cursor := oktaapi.NextPageCursor(resp)
+		if len(events) == 0 || cursor == "" {
+			return res, nil
+		}
+		qp.After = cursor
+	}
+}
+
+// formatLogEventJSON returns the event exactly as received from
+// Okta API, condensed to a single line.
+func formatLogEventJSON(e *oktaapi.LogEvent) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, e.Raw); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// formatLogEventCompact returns a human-readable, single line
+// summary of the event.
+func formatLogEventCompact(e *oktaapi.LogEvent) string {
+	var actor, ip, outcome string
+
+	if e.Actor != nil {
+		actor = Coalesce(e.Actor.AlternateId, e.Actor.DisplayName, e.Actor.Id)
+	}
+	if e.Client != nil {
+		ip = e.Client.IpAddress
+	}
+	if e.Outcome != nil {
+		outcome = e.Outcome.Result
+		if e.Outcome.Reason != "" {
+			outcome = fmt.Sprintf("%s (%s)", outcome, e.Outcome.Reason)
+		}
+	}
+
+	return fmt.Sprintf("%s %-8s %s actor=%s ip=%s %q",
+		e.Published.UTC().Format(time.RFC3339),
+		Coalesce(outcome, "-"),
+		e.EventType,
+		Coalesce(actor, "-"),
+		Coalesce(ip, "-"),
+		e.DisplayMessage,
+	)
+}
+
+func logEventOutcome(e *oktaapi.LogEvent) string {
+	if e.Outcome == nil {
+		return ""
+	}
+	return e.Outcome.Result
+}
+
+func logEventIP(e *oktaapi.LogEvent) string {
+	if e.Client == nil {
+		return ""
+	}
+	return e.Client.IpAddress
+}
+
+// logEventLocation returns the city, state and country the event
+// originated from, as determined by Okta from the client's IP.
+func logEventLocation(e *oktaapi.LogEvent) string {
+	if e.Client == nil || e.Client.GeographicalContext == nil {
+		return ""
+	}
+
+	geo := e.Client.GeographicalContext
+	parts := make([]string, 0, 3)
+	for _, p := range []string{geo.City, geo.State, geo.Country} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// logEventClient returns the browser and OS of the client the
+// event originated from, falling back to the raw user agent.
+func logEventClient(e *oktaapi.LogEvent) string {
+	if e.Client == nil || e.Client.UserAgent == nil {
+		return ""
+	}
+
+	ua := e.Client.UserAgent
+	if ua.Browser != "" && ua.Browser != "UNKNOWN" && ua.Os != "" {
+		return fmt.Sprintf("%s on %s", ua.Browser, ua.Os)
+	}
+	return ua.RawUserAgent
+}
@@ -0,0 +1,230 @@
+package command
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	logArchiveCheckpointFile = ".export-logs-checkpoint.json"
+	logArchiveFilePrefix     = "okta-logs-"
+	logArchiveFileSuffix     = ".jsonl.gz"
+	logArchiveDayLayout      = "2006-01-02"
+)
+
+// logArchiveCheckpoint records how far the System Log has been
+// exported to an archive. Since multiple events can be published
+// at the same instant, the IDs of events published at the
+// checkpoint's timestamp are recorded to avoid exporting them
+// again. File and Size describe the last archive file written
+// when the checkpoint was saved.
+type logArchiveCheckpoint struct {
+	Published time.Time `json:"published"`
+	Uuids     []string  `json:"uuids"`
+	File      string    `json:"file"`
+	Size      int64     `json:"size"`
+}
+
+// logArchive is a directory containing System Log events as
+// gzip-compressed, newline-delimited JSON, with one file per
+// day (UTC) in which the events were published.
+type logArchive struct {
+	dir        string
+	checkpoint *logArchiveCheckpoint
+}
+
+// openLogArchive opens the archive in the specified directory,
+// creating the directory if it doesn't exist. Any data written
+// after the last checkpoint, for eg- by an interrupted export,
+// is discarded so that the export can resume from the checkpoint
+// without duplicating events.
+func openLogArchive(dir string) (*logArchive, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	a := &logArchive{dir: dir}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, logArchiveCheckpointFile))
+	if os.IsNotExist(err) {
+		return a, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cp logArchiveCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, errors.New(fmt.Sprintf("failed to read checkpoint: %v", err))
+	}
+	a.checkpoint = &cp
+	return a, a.discardUncheckpointed()
+}
+
+// Checkpoint returns the last saved checkpoint of the archive or
+// nil if nothing has been exported to it yet.
+func (a *logArchive) Checkpoint() *logArchiveCheckpoint {
+	return a.checkpoint
+}
+
+// Write appends events to the archive files of the days they
+// were published in and saves a new checkpoint. Events must be
+// supplied in chronological order. Events already present in the
+// archive are skipped. Write returns the number of events added.
+func (a *logArchive) Write(events []*oktaapi.LogEvent) (int, error) {
+	var (
+		days      []string
+		eventsFor = map[string][]*oktaapi.LogEvent{}
+		cp        = a.checkpoint
+	)
+
+	for _, e := range events {
+		if a.isArchived(e) {
+			continue
+		}
+		day := e.Published.UTC().Format(logArchiveDayLayout)
+		if _, ok := eventsFor[day]; !ok {
+			days = append(days, day)
+		}
+		eventsFor[day] = append(eventsFor[day], e)
+	}
+	if len(days) == 0 {
+		return 0, nil
+	}
+
+	written := 0
+	for _, day := range days {
+		if err := a.appendToFile(logArchiveFileName(day), eventsFor[day]); err != nil {
+			return written, err
+		}
+		written += len(eventsFor[day])
+	}
+
+	// Checkpoint the last event and any events published at
+	// the same instant.
+	dayEvents := eventsFor[days[len(days)-1]]
+	last := dayEvents[len(dayEvents)-1]
+	next := &logArchiveCheckpoint{Published: last.Published, File: logArchiveFileName(days[len(days)-1])}
+	if cp != nil && cp.Published.Equal(last.Published) {
+		next.Uuids = append(next.Uuids, cp.Uuids...)
+	}
+	for _, e := range dayEvents {
+		if e.Published.Equal(last.Published) {
+			next.Uuids = append(next.Uuids, e.Uuid)
+		}
+	}
+
+	info, err := os.Stat(filepath.Join(a.dir, next.File))
+	if err != nil {
+		return written, err
+	}
+	next.Size = info.Size()
+	return written, a.saveCheckpoint(next)
+}
+
+// isArchived returns true if the event was exported to the
+// archive before the last checkpoint was saved.
+func (a *logArchive) isArchived(e *oktaapi.LogEvent) bool {
+	cp := a.checkpoint
+	if cp == nil {
+		return false
+	}
+	if e.Published.Before(cp.Published) {
+		return true
+	}
+	if e.Published.Equal(cp.Published) {
+		for _, id := range cp.Uuids {
+			if id == e.Uuid {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// appendToFile writes events to the end of an archive file as a
+// new gzip member. Readers like gzip -d and zcat transparently
+// decompress files made up of multiple members.
+func (a *logArchive) appendToFile(name string, events []*oktaapi.LogEvent) error {
+	f, err := os.OpenFile(filepath.Join(a.dir, name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	for _, e := range events {
+		line, err := formatLogEventJSON(e)
+		if err != nil {
+			return errors.New(fmt.Sprintf("failed to format event %s: %v", e.Uuid, err))
+		}
+		if _, err := gz.Write([]byte(line + "\n")); err != nil {
+			return err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// saveCheckpoint atomically replaces the checkpoint file.
+func (a *logArchive) saveCheckpoint(cp *logArchiveCheckpoint) error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(a.dir, logArchiveCheckpointFile)
+	if err := ioutil.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return err
+	}
+	a.checkpoint = cp
+	return nil
+}
+
+// discardUncheckpointed truncates the last checkpointed file to
+// its recorded size and removes archive files of later days.
+func (a *logArchive) discardUncheckpointed() error {
+	cp := a.checkpoint
+	files, err := ioutil.ReadDir(a.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		name := f.Name()
+		if !strings.HasPrefix(name, logArchiveFilePrefix) || !strings.HasSuffix(name, logArchiveFileSuffix) {
+			continue
+		}
+		path := filepath.Join(a.dir, name)
+
+		switch {
+		case name == cp.File && f.Size() > cp.Size:
+			if err := os.Truncate(path, cp.Size); err != nil {
+				return err
+			}
+		case name > cp.File:
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// logArchiveFileName returns the name of the archive file
+// containing events published on the specified day.
+func logArchiveFileName(day string) string {
+	return logArchiveFilePrefix + day + logArchiveFileSuffix
+}
@@ -0,0 +1,128 @@
+package command
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func createTestArchiveEvent(uuid, published string) *oktaapi.LogEvent {
+	p, _ := time.Parse(time.RFC3339, published)
+	raw := fmt.Sprintf(`{"uuid": "%s", "published": "%s"}`, uuid, published)
+	return &oktaapi.LogEvent{Uuid: uuid, Published: p, Raw: json.RawMessage(raw)}
+}
+
+func readTestArchiveFile(t *testing.T, path string) []string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open archive file: %v", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("Failed to read archive file: %v", err)
+	}
+
+	var uuids []string
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		var e map[string]string
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("Failed to decode archived event: %v", err)
+		}
+		uuids = append(uuids, e["uuid"])
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Failed to read archive file: %v", err)
+	}
+	return uuids
+}
+
+func TestLogArchive(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "okta-admin-logs")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	archive, err := openLogArchive(dir)
+	if err != nil {
+		t.Fatalf("Failed to open archive: %v", err)
+	}
+	if archive.Checkpoint() != nil {
+		t.Fatal("Expected new archive to not have a checkpoint")
+	}
+
+	n, err := archive.Write([]*oktaapi.LogEvent{
+		createTestArchiveEvent("a", "2019-10-18T23:59:59Z"),
+		createTestArchiveEvent("b", "2019-10-19T08:00:00Z"),
+		createTestArchiveEvent("c", "2019-10-19T09:00:00Z"),
+	})
+	if err != nil || n != 3 {
+		t.Fatalf("Expected 3 events to be written, received %d (error: %v)", n, err)
+	}
+
+	// Re-open the archive and supply overlapping events, as is the
+	// case when resuming an export from the checkpoint.
+	archive, err = openLogArchive(dir)
+	if err != nil {
+		t.Fatalf("Failed to re-open archive: %v", err)
+	}
+	n, err = archive.Write([]*oktaapi.LogEvent{
+		createTestArchiveEvent("c", "2019-10-19T09:00:00Z"),
+		createTestArchiveEvent("d", "2019-10-19T09:00:00Z"),
+		createTestArchiveEvent("e", "2019-10-20T00:00:00Z"),
+	})
+	if err != nil || n != 2 {
+		t.Fatalf("Expected 2 events to be written, received %d (error: %v)", n, err)
+	}
+
+	cp := archive.Checkpoint()
+	if cp.File != logArchiveFileName("2019-10-20") || len(cp.Uuids) != 1 || cp.Uuids[0] != "e" {
+		t.Errorf("Unexpected checkpoint %+v", cp)
+	}
+
+	expected := map[string][]string{
+		"2019-10-18": {"a"},
+		"2019-10-19": {"b", "c", "d"},
+		"2019-10-20": {"e"},
+	}
+	for day, uuids := range expected {
+		res := readTestArchiveFile(t, filepath.Join(dir, logArchiveFileName(day)))
+		if !testEq(res, uuids) {
+			t.Errorf("Expected events %v on %s, received %v", uuids, day, res)
+		}
+	}
+
+	// Simulate an export interrupted after writing events but
+	// before saving the checkpoint.
+	if err := archive.appendToFile(logArchiveFileName("2019-10-20"),
+		[]*oktaapi.LogEvent{createTestArchiveEvent("f", "2019-10-20T01:00:00Z")}); err != nil {
+		t.Fatalf("Failed to append to archive file: %v", err)
+	}
+	if err := archive.appendToFile(logArchiveFileName("2019-10-21"),
+		[]*oktaapi.LogEvent{createTestArchiveEvent("g", "2019-10-21T01:00:00Z")}); err != nil {
+		t.Fatalf("Failed to append to archive file: %v", err)
+	}
+
+	if _, err := openLogArchive(dir); err != nil {
+		t.Fatalf("Failed to re-open archive: %v", err)
+	}
+	if res := readTestArchiveFile(t, filepath.Join(dir, logArchiveFileName("2019-10-20"))); !testEq(res, []string{"e"}) {
+		t.Errorf("Expected events written after checkpoint to be discarded, received %v", res)
+	}
+	if _, err := os.Stat(filepath.Join(dir, logArchiveFileName("2019-10-21"))); !os.IsNotExist(err) {
+		t.Error("Expected file created after checkpoint to be removed")
+	}
+}
@@ -0,0 +1,71 @@
+package command
+
+import (
+	"encoding/json"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"testing"
+)
+
+const testLogEvent = `{
+  "uuid": "a1b2c3",
+  "published": "2019-10-19T08:15:02.123Z",
+  "eventType": "user.session.start",
+  "displayMessage": "User login to Okta",
+  "actor": {"id": "00u1", "alternateId": "harry.potter@hogwarts.co.uk"},
+  "client": {"ipAddress": "10.0.0.1"},
+  "outcome": {"result": "FAILURE", "reason": "INVALID_CREDENTIALS"},
+  "authenticationContext": {"credentialType": "PASSWORD"}
+}`
+
+func createTestLogEvent(t *testing.T) *oktaapi.LogEvent {
+	t.Helper()
+	var e oktaapi.LogEvent
+	if err := json.Unmarshal([]byte(testLogEvent), &e); err != nil {
+		t.Fatalf("Failed to decode test event: %v", err)
+	}
+	e.Raw = json.RawMessage(testLogEvent)
+	return &e
+}
+
+func TestCreateLogsFilter(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		exprs    []string
+		expected string
+	}{
+		{[]string{}, ""},
+		{[]string{"", "  "}, ""},
+		{[]string{`eventType eq "a"`}, `eventType eq "a"`},
+		{[]string{"", `eventType eq "a"`}, `eventType eq "a"`},
+		{[]string{`eventType eq "a"`, `actor.id eq "b"`}, `(eventType eq "a") and (actor.id eq "b")`},
+	}
+
+	for _, tc := range testCases {
+		if res := createLogsFilter(tc.exprs...); res != tc.expected {
+			t.Errorf("Expected %s, received %s for %v", tc.expected, res, tc.exprs)
+		}
+	}
+}
+
+func TestFormatLogEvent(t *testing.T) {
+	t.Parallel()
+	e := createTestLogEvent(t)
+
+	expected := `2019-10-19T08:15:02Z FAILURE (INVALID_CREDENTIALS) user.session.start actor=harry.potter@hogwarts.co.uk ip=10.0.0.1 "User login to Okta"`
+	if res := formatLogEventCompact(e); res != expected {
+		t.Errorf("Expected compact event to be %s, received %s", expected, res)
+	}
+
+	res, err := formatLogEventJSON(e)
+	if err != nil {
+		t.Fatalf("Failed to format event as JSON: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(res), &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, received %s", res)
+	}
+	if decoded["authenticationContext"] == nil {
+		t.Errorf("Expected JSON event to retain all fields, received %s", res)
+	}
+}
@@ -0,0 +1,163 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/duaraghav8/okta-admin/tokenstore"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"net/http"
+	"strings"
+)
+
+// Values of the -store option of login.
+const (
+	tokenStoreAuto    = "auto"
+	tokenStoreKeyring = "keyring"
+	tokenStoreFile    = "file"
+)
+
+type LoginCommand struct {
+	*Command
+}
+
+type LoginCommandConfig struct {
+	Store    string
+	NoVerify bool
+}
+
+func (c *LoginCommand) Synopsis() string {
+	return "Store the API token of an organization"
+}
+
+func (c *LoginCommand) Help() string {
+	helpText := `
+Usage: okta-admin login [options]
+
+  Stores the API token of the organization, so that commands run
+  against it use the token without -api-token or OKTA_API_TOKEN,
+  which can end up in the shell's history. The token is read from
+  -api-token-file, from standard input with -api-token-stdin, from
+  -api-token or OKTA_API_TOKEN, or else prompted for without
+  echoing it. It's checked by fetching a group of the organization
+  before it's stored.
+
+  Tokens are stored in the system keyring: the Keychain on macOS,
+  the Credential Manager on Windows, or the Secret Service, eg-
+  GNOME Keyring or KWallet, on Linux. Where it isn't available,
+  eg- over SSH, tokens are stored in a file encrypted with a
+  passphrase, which is prompted for when a token is stored or
+  used, unless the OKTA_ADMIN_PASSPHRASE environment variable
+  contains it. The file is tokens.enc in the okta-admin directory
+  of the user's configuration directory, eg- ~/.config/okta-admin
+  on Linux, unless the OKTA_ADMIN_TOKEN_FILE environment variable
+  specifies another file. Tokens specified explicitly are used
+  instead of the stored ones.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -store      Where to store the token, either keyring, file or auto
+              to use the keyring if it's available (Default: auto)
+  -no-verify  Store the token without checking it
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *LoginCommand) ParseArgs(args []string) (*LoginCommandConfig, error) {
+	var cfg LoginCommandConfig
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.Store, "store", tokenStoreAuto, "")
+	flags.BoolVar(&cfg.NoVerify, "no-verify", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+	if rest := flags.Args(); len(rest) > 0 {
+		return &cfg, errors.New(fmt.Sprintf("unexpected arguments: %s", strings.Join(rest, " ")))
+	}
+
+	// The API token isn't validated as a parameter, since that
+	// would look up the stored one
+	return &cfg, c.Command.validateParameters(
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+		&parameter{Name: "store", Required: true, Value: cfg.Store, ValidationFunc: ValidateOneOf(tokenStoreAuto, tokenStoreKeyring, tokenStoreFile)},
+	)
+}
+
+func (c *LoginCommand) Run(args []string) int {
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	opts := c.Meta.GlobalOptions
+	if err := c.readApiTokenStdin(); err != nil {
+		c.logError("Failed to read the API token", err)
+		return ExitUsage
+	}
+	if opts.ApiToken == "" {
+		token, err := readSecret(fmt.Sprintf("API token of %s: ", opts.OrgUrl))
+		if err == nil {
+			token, err = ReadApiToken(strings.NewReader(token))
+		}
+		if err != nil {
+			c.logError("Failed to read the API token", err)
+			c.Logger.Println("Hint: specify the token with -api-token-file or -api-token-stdin")
+			return ExitUsage
+		}
+		opts.ApiToken = token
+	}
+
+	if !cfg.NoVerify {
+		client, err := c.OktaClient()
+		if err != nil {
+			c.logError("Failed to initialize Okta client", err)
+			return exitCode(err)
+		}
+		_, resp, err := client.ListGroups(query.NewQueryParams(query.WithLimit(1)))
+		if err != nil {
+			c.logError("Failed to verify the API token", err)
+			return exitCode(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			c.Logger.Printf("Failed to verify the API token: %s\n", resp.Status)
+			return ExitFailure
+		}
+	}
+
+	keyring := systemKeyring
+	var store tokenstore.Store = keyring
+	useKeyring := cfg.Store == tokenStoreKeyring || cfg.Store == tokenStoreAuto && keyring.Available()
+	if !useKeyring {
+		if store, err = newFileStore(); err != nil {
+			c.logError("Failed to store the API token", err)
+			return ExitFailure
+		}
+	}
+	if err := store.Set(opts.OrgUrl, opts.ApiToken); err != nil {
+		c.logError(fmt.Sprintf("Failed to store the API token in %s", store), err)
+		return ExitFailure
+	}
+	c.Logger.Printf("Stored the API token of %s in %s\n", opts.OrgUrl, store)
+
+	// Tokens in the keyring are looked up before the ones in the
+	// file, so a token stored in the keyring before would be used
+	if !useKeyring && keyring.Available() {
+		switch err := keyring.Delete(opts.OrgUrl); err {
+		case nil:
+			c.Logger.Printf("Deleted the API token stored before in %s\n", keyring)
+		case tokenstore.ErrNotStored:
+		default:
+			c.logError(fmt.Sprintf("Failed to delete the API token stored before in %s, which is used instead", keyring), err)
+			return ExitFailure
+		}
+	}
+	return ExitOK
+}
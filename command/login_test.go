@@ -0,0 +1,165 @@
+package command
+
+import (
+	"errors"
+	"github.com/okta/okta-sdk-golang/okta"
+	"strings"
+	"testing"
+)
+
+func createTestLoginCommand(globalOptsHelpText string) *LoginCommand {
+	return &LoginCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_login_cmd"),
+	}
+}
+
+func TestLoginCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestLoginCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestLoginCommand_ParseArgs(t *testing.T) {
+	t.Parallel()
+
+	c := createTestLoginCommand("")
+	cfg, err := c.ParseArgs([]string{"-store", "file", "-no-verify"})
+	if err != nil {
+		t.Fatalf("Failed to parse arguments: %v", err)
+	}
+	if cfg.Store != tokenStoreFile || !cfg.NoVerify {
+		t.Errorf("Unexpected config %+v", cfg)
+	}
+
+	for _, args := range [][]string{
+		{"-store", "vault"},
+		{"hogwarts"},
+	} {
+		c := createTestLoginCommand("")
+		if _, err := c.ParseArgs(args); err == nil {
+			t.Errorf("Expected parsing of %q to fail", args)
+		}
+	}
+}
+
+func TestLoginCommand_Run(t *testing.T) {
+	const orgUrl = "https://login.okta.com/"
+
+	client := newFakeOktaClient()
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor")}
+	run := func(cmd func(c *Command) int, token string) (int, string) {
+		t.Helper()
+		c, out := createTestCommandWithClient("test_login_cmd", client)
+		c.Meta.GlobalOptions.OrgUrl, c.Meta.GlobalOptions.ApiToken = orgUrl, token
+		return cmd(c), out.String()
+	}
+	login := func(token string, args ...string) (int, string) {
+		return run(func(c *Command) int { return (&LoginCommand{Command: c}).Run(args) }, token)
+	}
+	logout := func() (int, string) {
+		return run(func(c *Command) int { return (&LogoutCommand{Command: c}).Run(nil) }, "")
+	}
+
+	// The keyring isn't available in tests, so tokens are stored
+	// in the encrypted file
+	if code, out := login("123abc"); code != ExitOK || !strings.Contains(out, "tokens.enc") {
+		t.Fatalf("Expected the token to be stored in the file, received %d: %s", code, out)
+	}
+	if token, err := StoredApiToken("https://LOGIN.okta.com"); err != nil || token != "123abc" {
+		t.Errorf("Expected the stored token to be looked up, received %q (error %v)", token, err)
+	}
+
+	// The token is prompted for if it isn't specified
+	defer func(f func(string) (string, error)) { readSecret = f }(readSecret)
+	readSecret = func(prompt string) (string, error) {
+		return "456def\n", nil
+	}
+	if code, out := login("", "-no-verify", "-store", "file"); code != ExitOK {
+		t.Fatalf("Expected the prompted token to be stored, received %d: %s", code, out)
+	}
+	if token, _ := StoredApiToken(orgUrl); token != "456def" {
+		t.Errorf("Expected the prompted token to replace the stored one, received %q", token)
+	}
+	readSecret = func(prompt string) (string, error) {
+		return "", errors.New("there's no terminal to prompt on")
+	}
+	if code, out := login(""); code != ExitUsage || !strings.Contains(out, "-api-token-stdin") {
+		t.Errorf("Expected failing to prompt for the token to be reported, received %d: %s", code, out)
+	}
+
+	// Tokens which don't work aren't stored
+	client.errs["ListGroups"] = errors.New("invalid token")
+	if code, _ := login("789ghi"); code == ExitOK {
+		t.Errorf("Expected the invalid token not to be stored")
+	}
+	if token, _ := StoredApiToken(orgUrl); token != "456def" {
+		t.Errorf("Expected the stored token to be kept, received %q", token)
+	}
+
+	if code, out := logout(); code != ExitOK {
+		t.Errorf("Expected the token to be deleted, received %d: %s", code, out)
+	}
+	if token, err := StoredApiToken(orgUrl); err != nil || token != "" {
+		t.Errorf("Expected no token to be stored, received %q (error %v)", token, err)
+	}
+	if code, out := logout(); code != ExitNotFound {
+		t.Errorf("Expected exit code %d once no token is stored, received %d: %s", ExitNotFound, code, out)
+	}
+}
+
+func TestLoginCommand_RunKeyring(t *testing.T) {
+	const orgUrl = "https://keyring.okta.com/"
+
+	keyring := &fakeKeyring{available: true}
+	defer func(k keyringStore) { systemKeyring = k }(systemKeyring)
+	systemKeyring = keyring
+
+	run := func(cmd func(c *Command) int, token string) (int, string) {
+		t.Helper()
+		c, out := createTestCommandWithClient("test_login_cmd", newFakeOktaClient())
+		c.Meta.GlobalOptions.OrgUrl, c.Meta.GlobalOptions.ApiToken = orgUrl, token
+		return cmd(c), out.String()
+	}
+	login := func(token string, args ...string) (int, string) {
+		return run(func(c *Command) int { return (&LoginCommand{Command: c}).Run(args) }, token)
+	}
+
+	// Tokens are stored in the keyring if it's available
+	if code, out := login("123abc", "-no-verify"); code != ExitOK || !strings.Contains(out, "the fake keyring") {
+		t.Fatalf("Expected the token to be stored in the keyring, received %d: %s", code, out)
+	}
+	if token, err := StoredApiToken(orgUrl); err != nil || token != "123abc" {
+		t.Errorf("Expected the token to be looked up in the keyring, received %q (error %v)", token, err)
+	}
+
+	// Storing the token in the file deletes the one in the keyring,
+	// which would be used instead
+	code, out := login("456def", "-no-verify", "-store", "file")
+	if code != ExitOK || !strings.Contains(out, "Deleted the API token stored before in the fake keyring") {
+		t.Fatalf("Expected the token to replace the one in the keyring, received %d: %s", code, out)
+	}
+	if token, err := StoredApiToken(orgUrl); err != nil || token != "456def" {
+		t.Errorf("Expected the token to be looked up in the file, received %q (error %v)", token, err)
+	}
+
+	if code, out := login("789ghi", "-no-verify"); code != ExitOK {
+		t.Fatalf("Expected the token to be stored in the keyring, received %d: %s", code, out)
+	}
+	code, out = run(func(c *Command) int { return (&LogoutCommand{Command: c}).Run(nil) }, "")
+	if code != ExitOK || !strings.Contains(out, "from the fake keyring") || !strings.Contains(out, "tokens.enc") {
+		t.Errorf("Expected the token to be deleted from both stores, received %d: %s", code, out)
+	}
+	if token, err := StoredApiToken(orgUrl); err != nil || token != "" {
+		t.Errorf("Expected no token to be stored, received %q (error %v)", token, err)
+	}
+}
+
+func TestLogoutCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := &LogoutCommand{Command: createTestCommand(testHelpMessage, "test_logout_cmd")}
+	// The global options end the help message, so their trailing
+	// newline is trimmed
+	if help := c.Help(); !strings.HasSuffix(help, strings.TrimSpace(testHelpMessage)) {
+		t.Errorf("Expected help message to end with the global options, received %q", help)
+	}
+}
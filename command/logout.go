@@ -0,0 +1,81 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/duaraghav8/okta-admin/tokenstore"
+	"strings"
+)
+
+type LogoutCommand struct {
+	*Command
+}
+
+func (c *LogoutCommand) Synopsis() string {
+	return "Delete the stored API token of an organization"
+}
+
+func (c *LogoutCommand) Help() string {
+	helpText := `
+Usage: okta-admin logout [options]
+
+  Deletes the API token of the organization stored by login, from
+  both the system keyring and the encrypted file. The token itself
+  isn't revoked, which is done in the Admin Console.
+{{.GlobalOptionsHelpText}}`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *LogoutCommand) ParseArgs(args []string) error {
+	flags := c.Meta.FlagSet
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if rest := flags.Args(); len(rest) > 0 {
+		return errors.New(fmt.Sprintf("unexpected arguments: %s", strings.Join(rest, " ")))
+	}
+	return c.Command.validateParameters(
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+	)
+}
+
+func (c *LogoutCommand) Run(args []string) int {
+	if err := c.ParseArgs(args); err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	orgUrl := c.Meta.GlobalOptions.OrgUrl
+	var stores []tokenstore.Store
+	if systemKeyring.Available() {
+		stores = append(stores, systemKeyring)
+	}
+	if f, err := newFileStore(); err == nil && f.Exists() {
+		stores = append(stores, f)
+	}
+
+	deleted := 0
+	var failures []error
+	for _, store := range stores {
+		switch err := store.Delete(orgUrl); err {
+		case nil:
+			c.Logger.Printf("Deleted the API token of %s from %s\n", orgUrl, store)
+			deleted++
+		case tokenstore.ErrNotStored:
+		default:
+			c.logError(fmt.Sprintf("Failed to delete the API token from %s", store), err)
+			failures = append(failures, err)
+		}
+	}
+	if deleted == 0 && len(failures) == 0 {
+		c.Logger.Printf("No API token is stored for %s\n", orgUrl)
+		return ExitNotFound
+	}
+	return fanOutExitCode(deleted+len(failures), failures)
+}
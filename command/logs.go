@@ -0,0 +1,186 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"time"
+)
+
+type LogsCommand struct {
+	*Command
+}
+
+type LogsCommandConfig struct {
+	Since, Until  time.Time
+	Filter, Actor string
+	Format        string
+	Follow        bool
+	Interval      time.Duration
+}
+
+func (c *LogsCommand) Synopsis() string {
+	return "Query or tail the organization's System Log"
+}
+
+func (c *LogsCommand) Help() string {
+	helpText := `
+Usage: okta-admin logs [options]
+
+  Queries events from the System Log of the organization.
+  Events are printed in chronological order. With -follow,
+  this command keeps polling Okta for new events and prints
+  them as they arrive until it is interrupted, which isn't
+  treated as a failure.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -since    Only return events published after this point in time.
+            Either an RFC3339 timestamp or a duration in the past,
+            like 30m, 12h or 7d (Default: 15m)
+  -until    Only return events published before this point in time,
+            specified the same way as -since. Cannot be combined
+            with -follow.
+  -filter   System Log filter expression, eg-
+            'eventType eq "user.session.start"'
+  -actor    Only return events performed by the user with this
+            login, which is usually their email ID
+  -follow   Continuously poll for and print new events
+  -interval Time to wait between polls when there are no new
+            events (Default: 10s)
+  -format   Format to print events in. Either json, to print each
+            event as a single line of raw JSON, or compact for a
+            human-readable summary (Default: compact)
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *LogsCommand) ParseArgs(args []string) (*LogsCommandConfig, error) {
+	var cfg LogsCommandConfig
+	var since, until string
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&since, "since", "15m", "")
+	flags.StringVar(&until, "until", "", "")
+	flags.StringVar(&cfg.Filter, "filter", "", "")
+	flags.StringVar(&cfg.Actor, "actor", "", "")
+	flags.BoolVar(&cfg.Follow, "follow", false, "")
+	flags.DurationVar(&cfg.Interval, "interval", 10*time.Second, "")
+	flags.StringVar(&cfg.Format, "format", logFormatCompact, "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "actor", Value: cfg.Actor, ValidationFunc: ValidateUserIdentifier},
+		&parameter{Name: "format", Required: true, Value: cfg.Format, ValidationFunc: ValidateOneOf(logFormatCompact, logFormatJSON)},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+	)
+	if err != nil {
+		return &cfg, err
+	}
+
+	now := time.Now()
+	if cfg.Since, err = ParseRelativeTime(since, now); err != nil {
+		return &cfg, errors.New(fmt.Sprintf("since: %v", err))
+	}
+	if until != "" {
+		if cfg.Follow {
+			return &cfg, errors.New("until cannot be combined with follow")
+		}
+		if cfg.Until, err = ParseRelativeTime(until, now); err != nil {
+			return &cfg, errors.New(fmt.Sprintf("until: %v", err))
+		}
+	}
+	if cfg.Interval <= 0 {
+		return &cfg, errors.New("interval must be positive")
+	}
+	return &cfg, nil
+}
+
+func (c *LogsCommand) Run(args []string) int {
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	var actorFilter string
+	if cfg.Actor != "" {
+		actorFilter = "actor.alternateId eq " + QuoteFilterValue(cfg.Actor)
+	}
+
+	qp := query.NewQueryParams(
+		query.WithSince(cfg.Since.UTC().Format(time.RFC3339)),
+		query.WithFilter(createLogsFilter(cfg.Filter, actorFilter)),
+		query.WithSortOrder("ASCENDING"),
+		query.WithLimit(logsPageSize),
+	)
+	if !cfg.Until.IsZero() {
+		qp.Until = cfg.Until.UTC().Format(time.RFC3339)
+	}
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+
+	ctx := c.operationContext()
+	for {
+		events, resp, err := client.GetLogs(qp)
+		if err != nil {
+			if cfg.Follow && ctx.Err() == context.Canceled {
+				return ExitOK
+			}
+			c.logError("Failed to fetch logs", err)
+			return exitCode(err)
+		}
+
+		for _, e := range events {
+			if cfg.Format == logFormatJSON {
+				line, err := formatLogEventJSON(e)
+				if err != nil {
+					c.Logger.Printf("Failed to format event %s: %v\n", e.Uuid, err)
+					return ExitFailure
+				}
+				c.Logger.Println(line)
+			} else {
+				c.Logger.Println(formatLogEventCompact(e))
+			}
+		}
+
+		// Without an upper bound, Okta always advertises a next
+		// page, which is empty once all events have been read.
+		cursor := oktaapi.NextPageCursor(resp)
+		if cursor != "" {
+			qp.After = cursor
+		}
+		if len(events) == 0 || cursor == "" {
+			if !cfg.Follow {
+				return ExitOK
+			}
+			timer := time.NewTimer(cfg.Interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				if ctx.Err() == context.Canceled {
+					return ExitOK
+				}
+				err := operationError(ctx, c.Meta.GlobalOptions.Timeout)
+				c.logError("Stopped following logs", err)
+				return exitCode(err)
+			case <-timer.C:
+			}
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta"
+	"strings"
+	"testing"
+	"time"
+)
+
+func createTestLogsCommand(globalOptsHelpText string) *LogsCommand {
+	return &LogsCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_logs_cmd"),
+	}
+}
+
+func TestLogsCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestLogsCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestLogsCommand_ParseArgs(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestLogsCommand("")
+		cfg, err := c.ParseArgs([]string{})
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.Format != logFormatCompact {
+			t.Errorf("Expected format to be %s, received %s", logFormatCompact, cfg.Format)
+		}
+		if cfg.Follow {
+			t.Errorf("Expected -follow flag to be unset")
+		}
+		if d := time.Since(cfg.Since); d < 15*time.Minute || d > 16*time.Minute {
+			t.Errorf("Expected since to be 15 minutes ago, received %s", cfg.Since)
+		}
+		if !cfg.Until.IsZero() {
+			t.Errorf("Expected until to be unset, received %s", cfg.Until)
+		}
+	})
+
+	t.Run("with options", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestLogsCommand("")
+		args := []string{
+			"-since", "2019-10-01T00:00:00Z",
+			"-until", "2019-10-02T00:00:00Z",
+			"-filter", `eventType eq "user.session.start"`,
+			"-actor", "harry.potter@hogwarts.co.uk",
+			"-format", "json",
+		}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.Since.Format(time.RFC3339) != args[1] {
+			t.Errorf("Expected since to be %s, received %s", args[1], cfg.Since)
+		}
+		if cfg.Until.Format(time.RFC3339) != args[3] {
+			t.Errorf("Expected until to be %s, received %s", args[3], cfg.Until)
+		}
+		if cfg.Filter != args[5] {
+			t.Errorf("Expected filter to be %s, received %s", args[5], cfg.Filter)
+		}
+		if cfg.Actor != args[7] {
+			t.Errorf("Expected actor to be %s, received %s", args[7], cfg.Actor)
+		}
+		if cfg.Format != args[9] {
+			t.Errorf("Expected format to be %s, received %s", args[9], cfg.Format)
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := [][]string{
+			{"-since", "yesterday"},
+			{"-follow", "-until", "1h"},
+			{"-format", "xml"},
+			{"-actor", "harry potter"},
+			{"-follow", "-interval", "0s"},
+		}
+		for _, tc := range testCases {
+			c := createTestLogsCommand("")
+			if _, err := c.ParseArgs(tc); err == nil {
+				t.Errorf("Expected args %v to be invalid", tc)
+			}
+		}
+	})
+}
+
+func TestLogsCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	published, _ := time.Parse(time.RFC3339, "2019-10-01T10:00:00Z")
+	client := newFakeOktaClient()
+	client.logs = []*oktaapi.LogEvent{{
+		Uuid:           "e1",
+		EventType:      "user.session.start",
+		DisplayMessage: "User login to Okta",
+		Published:      published,
+		Actor:          &okta.LogActor{AlternateId: "harry.potter@hogwarts.co.uk"},
+		Client:         &okta.LogClient{IpAddress: "10.0.0.1"},
+		Outcome:        &okta.LogOutcome{Result: "SUCCESS"},
+		Raw:            json.RawMessage(`{"uuid": "e1", "eventType": "user.session.start"}`),
+	}}
+	const compact = `2019-10-01T10:00:00Z SUCCESS  user.session.start actor=harry.potter@hogwarts.co.uk ip=10.0.0.1 "User login to Okta"` + "\n"
+
+	t.Run("compact", func(t *testing.T) {
+		c, out := createTestCommandWithClient("test_logs_cmd", client)
+		code := (&LogsCommand{Command: c}).Run([]string{"-actor", "harry.potter@hogwarts.co.uk"})
+		if code != ExitOK || out.String() != compact {
+			t.Errorf("Expected exit code %d and output %q, received %d: %q", ExitOK, compact, code, out)
+		}
+	})
+
+	t.Run("quoted actor", func(t *testing.T) {
+		client := newFakeOktaClient()
+		c, out := createTestCommandWithClient("test_logs_cmd", client)
+		if code := (&LogsCommand{Command: c}).Run([]string{"-actor", `harry"or"1`}); code != ExitOK {
+			t.Fatalf("Expected exit code %d, received %d: %s", ExitOK, code, out)
+		}
+		if expected := []string{`GetLogs actor.alternateId eq "harry\"or\"1"`}; !testEq(client.calls, expected) {
+			t.Errorf("Expected calls %v, received %v", expected, client.calls)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		c, out := createTestCommandWithClient("test_logs_cmd", client)
+		code := (&LogsCommand{Command: c}).Run([]string{"-format", "json"})
+		if expected := `{"uuid":"e1","eventType":"user.session.start"}` + "\n"; code != ExitOK || out.String() != expected {
+			t.Errorf("Expected exit code %d and output %q, received %d: %q", ExitOK, expected, code, out)
+		}
+	})
+
+	t.Run("follow interrupted", func(t *testing.T) {
+		// The interrupt is already pending, so following stops
+		// instead of waiting for the interval to elapse
+		c, out := createTestCommandWithClient("test_logs_cmd", client)
+		c.operationContext()
+		c.cancel()
+		code := (&LogsCommand{Command: c}).Run([]string{"-follow", "-interval", "1h"})
+		if code != ExitOK || out.String() != compact {
+			t.Errorf("Expected exit code %d and output %q, received %d: %q", ExitOK, compact, code, out)
+		}
+	})
+
+	t.Run("follow timed out", func(t *testing.T) {
+		c, out := createTestCommandWithClient("test_logs_cmd", client)
+		c.Meta.GlobalOptions.Timeout = time.Millisecond
+		code := (&LogsCommand{Command: c}).Run([]string{"-follow", "-interval", "1h"})
+		if code != ExitFailure || !strings.Contains(out.String(), "operation timed out after 1ms") {
+			t.Errorf("Expected exit code %d for the timeout, received %d: %s", ExitFailure, code, out)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		client := newFakeOktaClient()
+		client.errs["GetLogs"] = &oktaapi.ApiError{StatusCode: 403, Summary: "You do not have permission"}
+		c, out := createTestCommandWithClient("test_logs_cmd", client)
+		code := (&LogsCommand{Command: c}).Run(nil)
+		if code != ExitAuth || !strings.Contains(out.String(), "Failed to fetch logs") {
+			t.Errorf("Expected exit code %d, received %d: %s", ExitAuth, code, out)
+		}
+
+		// Requests aborted by the interrupt end following too
+		client.errs["GetLogs"] = errors.New("operation was interrupted")
+		c, out = createTestCommandWithClient("test_logs_cmd", client)
+		c.operationContext()
+		c.cancel()
+		if code := (&LogsCommand{Command: c}).Run([]string{"-follow"}); code != ExitOK {
+			t.Errorf("Expected exit code %d once interrupted, received %d: %s", ExitOK, code, out)
+		}
+	})
+}
@@ -0,0 +1,366 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	policyTypeSignOn    = "OKTA_SIGN_ON"
+	policyTypePassword  = "PASSWORD"
+	policyTypeMfaEnroll = "MFA_ENROLL"
+
+	policyFormatText = "text"
+)
+
+// policyTypes contains the policy types supported by the CLI
+var policyTypes = []string{policyTypeSignOn, policyTypePassword, policyTypeMfaEnroll}
+
+// listPolicies fetches the policies of the specified type, sorted
+// by priority.
+func listPolicies(client oktaapi.Client, policyType string) ([]*oktaapi.Policy, error) {
+	policies, _, err := client.ListPolicies(query.NewQueryParams(query.WithType(policyType)))
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(policies, func(i, j int) bool {
+		return policies[i].Priority < policies[j].Priority
+	})
+	return policies, nil
+}
+
+// findPolicy returns the policy with the specified ID or, if the
+// ID is empty, the one with the specified name. Policies with
+// the name are searched among the specified type or, if the type
+// is empty, all supported types. An error is returned if no
+// policy or more than one policy has the name.
+func findPolicy(client oktaapi.Client, id, name, policyType string) (*oktaapi.Policy, error) {
+	if id != "" {
+		policy, _, err := client.GetPolicy(id)
+		return policy, err
+	}
+
+	types := policyTypes
+	if policyType != "" {
+		types = []string{policyType}
+	}
+
+	var res *oktaapi.Policy
+	for _, t := range types {
+		policies, err := listPolicies(client, t)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range policies {
+			if p.Name != name {
+				continue
+			}
+			if res != nil {
+				return nil, errors.New(fmt.Sprintf("multiple policies are named %s, specify the policy ID or type instead", name))
+			}
+			res = p
+		}
+	}
+	if res == nil {
+		return nil, newNotFoundError("%s does not exist", name)
+	}
+	return res, nil
+}
+
+// describePolicyGroup returns the name of a group along with its
+// ID, as referenced in the conditions of a policy.
+func describePolicyGroup(id string, groups OktaGroups) string {
+	return fmt.Sprintf("%s (%s)", Coalesce(groups.GetName(id), "[Unknown]"), id)
+}
+
+// describePeopleCondition returns lines describing the groups and
+// users a policy or rule applies to.
+func describePeopleCondition(c *okta.PolicyPeopleCondition, groups OktaGroups) []string {
+	var res []string
+	describe := func(label string, ids []string, resolve bool) {
+		if len(ids) == 0 {
+			return
+		}
+		names := make([]string, len(ids), len(ids))
+		for i, id := range ids {
+			names[i] = id
+			if resolve {
+				names[i] = describePolicyGroup(id, groups)
+			}
+		}
+		res = append(res, fmt.Sprintf("%s:\t%s", label, strings.Join(names, ", ")))
+	}
+
+	if c != nil && c.Groups != nil {
+		describe("Groups", c.Groups.Include, true)
+		describe("Excluded groups", c.Groups.Exclude, true)
+	}
+	if c != nil && c.Users != nil {
+		describe("Users", c.Users.Include, false)
+		describe("Excluded users", c.Users.Exclude, false)
+	}
+	if len(res) == 0 {
+		res = append(res, "People:\tEveryone")
+	}
+	return res
+}
+
+// describeConditions returns lines describing all conditions of a
+// policy or a rule.
+func describeConditions(c *oktaapi.PolicyConditions, groups OktaGroups) []string {
+	if c == nil {
+		return describePeopleCondition(nil, groups)
+	}
+
+	res := describePeopleCondition(c.People, groups)
+	if n := c.Network; n != nil && n.Connection != "" {
+		network := n.Connection
+		if len(n.Include) > 0 {
+			network += ", zones " + strings.Join(n.Include, ", ")
+		}
+		if len(n.Exclude) > 0 {
+			network += ", except zones " + strings.Join(n.Exclude, ", ")
+		}
+		res = append(res, "Network:\t"+network)
+	}
+	if c.AuthContext != nil && c.AuthContext.AuthType != "" {
+		res = append(res, "Authentication type:\t"+c.AuthContext.AuthType)
+	}
+	if c.AuthProvider != nil && c.AuthProvider.Provider != "" {
+		res = append(res, "Authentication provider:\t"+c.AuthProvider.Provider)
+	}
+	return res
+}
+
+// describePasswordSettings returns lines describing the password
+// complexity, age and lockout settings of a Password policy.
+func describePasswordSettings(s *okta.PasswordPolicyPasswordSettings) []string {
+	var res []string
+	if s == nil {
+		return res
+	}
+
+	if c := s.Complexity; c != nil {
+		common := c.Dictionary != nil && c.Dictionary.Common != nil && c.Dictionary.Common.Exclude != nil && *c.Dictionary.Common.Exclude
+		res = append(res,
+			fmt.Sprintf("Minimum length:\t%d", c.MinLength),
+			fmt.Sprintf("Minimum lowercase letters:\t%d", c.MinLowerCase),
+			fmt.Sprintf("Minimum uppercase letters:\t%d", c.MinUpperCase),
+			fmt.Sprintf("Minimum numbers:\t%d", c.MinNumber),
+			fmt.Sprintf("Minimum symbols:\t%d", c.MinSymbol),
+			fmt.Sprintf("Disallow username:\t%s", formatYesNo(c.ExcludeUsername != nil && *c.ExcludeUsername)),
+			fmt.Sprintf("Disallow common passwords:\t%s", formatYesNo(common)),
+		)
+		if len(c.ExcludeAttributes) > 0 {
+			res = append(res, "Disallow attributes:\t"+strings.Join(c.ExcludeAttributes, ", "))
+		}
+	}
+
+	if a := s.Age; a != nil {
+		maxAge := "Never expires"
+		if a.MaxAgeDays > 0 {
+			maxAge = formatMinutes(a.MaxAgeDays * 24 * 60)
+		}
+		res = append(res,
+			"Maximum age:\t"+maxAge,
+			"Minimum age:\t"+Coalesce(formatMinutes(a.MinAgeMinutes), "None"),
+			"Expiry warning:\t"+Coalesce(formatMinutes(a.ExpireWarnDays*24*60), "None"),
+			fmt.Sprintf("Password history:\t%d", a.HistoryCount),
+		)
+	}
+
+	if l := s.Lockout; l != nil {
+		attempts, unlock := "Never locked out", "Never"
+		if l.MaxAttempts > 0 {
+			attempts = fmt.Sprintf("%d", l.MaxAttempts)
+		}
+		if l.AutoUnlockMinutes > 0 {
+			unlock = "After " + formatMinutes(l.AutoUnlockMinutes)
+		}
+		res = append(res,
+			"Lock out after failed attempts:\t"+attempts,
+			"Auto unlock:\t"+unlock,
+			"Show lockout failures:\t"+formatYesNo(l.ShowLockoutFailures != nil && *l.ShowLockoutFailures),
+		)
+	}
+	return res
+}
+
+// describeFactorSettings returns lines describing whether each
+// factor can be enrolled under an MFA Enrollment policy.
+func describeFactorSettings(factors map[string]*oktaapi.PolicyFactorSettings) []string {
+	var res []string
+	for name, f := range factors {
+		enroll := "-"
+		if f != nil && f.Enroll != nil {
+			enroll = f.Enroll.Self
+		}
+		res = append(res, fmt.Sprintf("%s:\t%s", name, enroll))
+	}
+	sort.Strings(res)
+	return res
+}
+
+// describeRuleActions returns lines describing the actions of a
+// policy rule.
+func describeRuleActions(a *oktaapi.PolicyRuleActions) []string {
+	var res []string
+	if a == nil {
+		return res
+	}
+
+	if s := a.Signon; s != nil {
+		res = append(res, "Access:\t"+s.Access)
+		if s.RequireFactor != nil && *s.RequireFactor {
+			mfa := "Required"
+			switch s.FactorPromptMode {
+			case "ALWAYS":
+				mfa += " at every sign-in"
+			case "DEVICE":
+				mfa += " once per device"
+			case "SESSION":
+				mfa += " once per session"
+			}
+			if s.FactorLifetime > 0 {
+				mfa += ", remembered for " + formatMinutes(s.FactorLifetime)
+			}
+			res = append(res, "MFA:\t"+mfa)
+		} else {
+			res = append(res, "MFA:\tNot required")
+		}
+		if s.Session != nil {
+			res = append(res,
+				"Session idle timeout:\t"+Coalesce(formatMinutes(s.Session.MaxSessionIdleMinutes), "None"),
+				"Session lifetime:\t"+Coalesce(formatMinutes(s.Session.MaxSessionLifetimeMinutes), "Unlimited"),
+				"Persistent session cookie:\t"+formatYesNo(s.Session.UsePersistentCookie != nil && *s.Session.UsePersistentCookie),
+			)
+		}
+	}
+
+	for _, action := range []struct {
+		label  string
+		action *okta.PasswordPolicyRuleAction
+	}{
+		{"Password change", a.PasswordChange},
+		{"Self-service password reset", a.SelfServicePasswordReset},
+		{"Self-service unlock", a.SelfServiceUnlock},
+	} {
+		if action.action != nil {
+			res = append(res, fmt.Sprintf("%s:\t%s", action.label, action.action.Access))
+		}
+	}
+
+	if a.Enroll != nil {
+		res = append(res, "Enrollment:\t"+a.Enroll.Self)
+	}
+	return res
+}
+
+// getPolicyDetailsPretty returns a pretty string describing the
+// policy and rules passed to it.
+func getPolicyDetailsPretty(p *oktaapi.Policy, rules []*oktaapi.PolicyRule, groups OktaGroups) string {
+	builder := &strings.Builder{}
+	w := tabwriter.NewWriter(builder, 0, 4, 1, ' ', 0)
+
+	section := func(title string, lines []string, indent string) {
+		if title != "" {
+			fmt.Fprintf(w, "\n%s\n", title)
+		}
+		for _, l := range lines {
+			fmt.Fprintf(w, "%s%s\n", indent, l)
+		}
+	}
+
+	section("", []string{
+		"Name:\t" + p.Name,
+		"ID:\t" + p.Id,
+		"Type:\t" + p.Type,
+		"Status:\t" + p.Status,
+		fmt.Sprintf("Priority:\t%d", p.Priority),
+		"Description:\t" + Coalesce(p.Description, "[None]"),
+	}, "")
+	section("Applies to", describeConditions(p.Conditions, groups), "  ")
+
+	if p.Settings != nil {
+		if p.Type == policyTypePassword {
+			section("Password settings", describePasswordSettings(p.Settings.Password), "  ")
+		}
+		if p.Type == policyTypeMfaEnroll {
+			section("Factor enrollment", describeFactorSettings(p.Settings.Factors), "  ")
+		}
+	}
+
+	fmt.Fprintln(w, "\nRules")
+	if len(rules) == 0 {
+		fmt.Fprintln(w, "  [None]")
+	}
+	for i, r := range rules {
+		fmt.Fprintf(w, "  %d. %s [%s]\n", i+1, r.Name, r.Status)
+		section("", describeConditions(r.Conditions, groups), "     ")
+		section("", describeRuleActions(r.Actions), "     ")
+	}
+
+	w.Flush()
+	return builder.String()
+}
+
+// writePoliciesJSON writes the policies, exactly as returned by
+// the API, as a JSON array.
+func writePoliciesJSON(w io.Writer, policies []*oktaapi.Policy) error {
+	raw := make([]json.RawMessage, len(policies), len(policies))
+	for i, p := range policies {
+		raw[i] = p.Raw
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(raw)
+}
+
+// writePolicyJSON writes a policy and its rules, exactly as
+// returned by the API, as a JSON object.
+func writePolicyJSON(w io.Writer, p *oktaapi.Policy, rules []*oktaapi.PolicyRule) error {
+	rawRules := make([]json.RawMessage, len(rules), len(rules))
+	for i, r := range rules {
+		rawRules[i] = r.Raw
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]interface{}{
+		"policy": p.Raw,
+		"rules":  rawRules,
+	})
+}
+
+// formatMinutes returns a readable form of a number of minutes,
+// using the largest whole unit. Zero is formatted as an empty
+// string so that callers can supply their own meaning for it.
+func formatMinutes(m int64) string {
+	unit := "minute"
+	switch {
+	case m == 0:
+		return ""
+	case m%(24*60) == 0:
+		m, unit = m/(24*60), "day"
+	case m%60 == 0:
+		m, unit = m/60, "hour"
+	}
+	if m != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", m, unit)
+}
+
+func formatYesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
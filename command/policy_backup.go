@@ -0,0 +1,158 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+const policyBackupVersion = 1
+
+// policyReadOnlyFields contains the fields of policies and rules
+// which are set by Okta and therefore not restored. Status is
+// restored separately, via the lifecycle operations.
+var policyReadOnlyFields = []string{"id", "created", "lastUpdated", "status", "system", "_links", "_embedded"}
+
+// policyBackup contains every policy and rule of an organization,
+// exactly as returned by the API.
+type policyBackup struct {
+	Version   int                  `json:"version"`
+	CreatedAt time.Time            `json:"createdAt"`
+	OrgUrl    string               `json:"orgUrl"`
+	Policies  []*policyBackupEntry `json:"policies"`
+}
+
+type policyBackupEntry struct {
+	Policy json.RawMessage   `json:"policy"`
+	Rules  []json.RawMessage `json:"rules"`
+}
+
+// loadPolicyBackup reads a backup created by backup-policies. The
+// URL of the organization it was created for is returned, along
+// with the policies in the order they should be restored in, ie-
+// by type and then priority, and their rules sorted by priority.
+func loadPolicyBackup(file string) (string, []*oktaapi.Policy, map[string][]*oktaapi.PolicyRule, error) {
+	var backup policyBackup
+
+	f, err := os.Open(file)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(&backup); err != nil {
+		return "", nil, nil, errors.New(fmt.Sprintf("failed to read backup: %v", err))
+	}
+	if backup.Version != policyBackupVersion {
+		return "", nil, nil, errors.New(fmt.Sprintf("unsupported backup version %d", backup.Version))
+	}
+
+	policies := make([]*oktaapi.Policy, 0, len(backup.Policies))
+	rules := map[string][]*oktaapi.PolicyRule{}
+	for _, e := range backup.Policies {
+		var p oktaapi.Policy
+		if err := json.Unmarshal(e.Policy, &p); err != nil {
+			return "", nil, nil, errors.New(fmt.Sprintf("failed to read policy: %v", err))
+		}
+		p.Raw = e.Policy
+		policies = append(policies, &p)
+
+		for _, raw := range e.Rules {
+			var r oktaapi.PolicyRule
+			if err := json.Unmarshal(raw, &r); err != nil {
+				return "", nil, nil, errors.New(fmt.Sprintf("failed to read rule of policy %s: %v", p.Name, err))
+			}
+			r.Raw = raw
+			rules[p.Id] = append(rules[p.Id], &r)
+		}
+		sort.SliceStable(rules[p.Id], func(i, j int) bool {
+			return rules[p.Id][i].Priority < rules[p.Id][j].Priority
+		})
+	}
+
+	sort.SliceStable(policies, func(i, j int) bool {
+		ti, tj := policyTypeIndex(policies[i].Type), policyTypeIndex(policies[j].Type)
+		if ti != tj {
+			return ti < tj
+		}
+		return policies[i].Priority < policies[j].Priority
+	})
+	return backup.OrgUrl, policies, rules, nil
+}
+
+// policyTypeIndex returns the position of the policy type among
+// the supported types, or -1 if the type isn't supported.
+func policyTypeIndex(policyType string) int {
+	for i, t := range policyTypes {
+		if t == policyType {
+			return i
+		}
+	}
+	return -1
+}
+
+// restorableFields returns the fields of a policy or rule which
+// can be written back to Okta.
+func restorableFields(raw json.RawMessage) (map[string]interface{}, error) {
+	var res map[string]interface{}
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return nil, err
+	}
+	for _, f := range policyReadOnlyFields {
+		delete(res, f)
+	}
+	return res, nil
+}
+
+// diffJSON returns lines describing how the decoded JSON value
+// desired differs from current. Objects are compared field by
+// field, while any other values are compared as a whole.
+func diffJSON(path string, current, desired interface{}) []string {
+	cur, curOk := current.(map[string]interface{})
+	des, desOk := desired.(map[string]interface{})
+	if !curOk || !desOk {
+		if reflect.DeepEqual(current, desired) {
+			return nil
+		}
+		switch {
+		case current == nil:
+			return []string{fmt.Sprintf("+ %s: %s", path, formatJSONValue(desired))}
+		case desired == nil:
+			return []string{fmt.Sprintf("- %s: %s", path, formatJSONValue(current))}
+		}
+		return []string{fmt.Sprintf("~ %s: %s => %s", path, formatJSONValue(current), formatJSONValue(desired))}
+	}
+
+	keys := map[string]bool{}
+	for k := range cur {
+		keys[k] = true
+	}
+	for k := range des {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var res []string
+	for _, k := range sorted {
+		res = append(res, diffJSON(strings.TrimPrefix(path+"."+k, "."), cur[k], des[k])...)
+	}
+	return res
+}
+
+func formatJSONValue(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
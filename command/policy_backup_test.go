@@ -0,0 +1,100 @@
+package command
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadPolicyBackup(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "okta-admin-policy-backup")
+	if err != nil {
+		t.Fatalf("Failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	backup := `{
+  "version": 1,
+  "orgUrl": "https://hogwarts.okta.com/",
+  "policies": [
+    {"policy": {"id": "p1", "name": "Contractors", "type": "PASSWORD", "priority": 2}, "rules": [
+      {"id": "r2", "name": "Second", "priority": 2},
+      {"id": "r1", "name": "First", "priority": 1}
+    ]},
+    {"policy": {"id": "p2", "name": "Staff", "type": "PASSWORD", "priority": 1}, "rules": []},
+    {"policy": {"id": "p3", "name": "Default", "type": "OKTA_SIGN_ON", "priority": 1}, "rules": []}
+  ]
+}`
+	file := filepath.Join(dir, "policies.json")
+	if err := ioutil.WriteFile(file, []byte(backup), 0600); err != nil {
+		t.Fatalf("Failed to write backup: %v", err)
+	}
+
+	orgUrl, policies, rules, err := loadPolicyBackup(file)
+	if err != nil {
+		t.Fatalf("Failed to load backup: %v", err)
+	}
+	if orgUrl != "https://hogwarts.okta.com/" {
+		t.Errorf("Expected the organization of the backup to be returned, received %q", orgUrl)
+	}
+
+	var names []string
+	for _, p := range policies {
+		names = append(names, p.Name)
+	}
+	if expected := []string{"Default", "Staff", "Contractors"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected policies in order %v, received %v", expected, names)
+	}
+	if len(rules["p1"]) != 2 || rules["p1"][0].Name != "First" {
+		t.Errorf("Expected rules of p1 to be sorted by priority")
+	}
+	if len(policies[0].Raw) == 0 {
+		t.Errorf("Expected raw policy to be retained")
+	}
+
+	if err := ioutil.WriteFile(file, []byte(`{"version": 2, "policies": []}`), 0600); err != nil {
+		t.Fatalf("Failed to write backup: %v", err)
+	}
+	if _, _, _, err := loadPolicyBackup(file); err == nil {
+		t.Errorf("Expected error for unsupported backup version")
+	}
+}
+
+func TestDiffJSON(t *testing.T) {
+	t.Parallel()
+
+	current, err := restorableFields(json.RawMessage(`{
+  "id": "p1", "status": "ACTIVE", "lastUpdated": "2019-10-19T08:15:00.000Z", "name": "Default Policy",
+  "settings": {"password": {"lockout": {"maxAttempts": 3}, "age": {"maxAgeDays": 90}}},
+  "conditions": {"people": {"groups": {"include": ["00g1"]}}}
+}`))
+	if err != nil {
+		t.Fatalf("Failed to decode policy: %v", err)
+	}
+	desired, err := restorableFields(json.RawMessage(`{
+  "id": "p2", "status": "INACTIVE", "name": "Default Policy", "description": "Everyone",
+  "settings": {"password": {"lockout": {"maxAttempts": 10}}},
+  "conditions": {"people": {"groups": {"include": ["00g1", "00g2"]}}}
+}`))
+	if err != nil {
+		t.Fatalf("Failed to decode policy: %v", err)
+	}
+
+	expected := []string{
+		`~ conditions.people.groups.include: ["00g1"] => ["00g1","00g2"]`,
+		`+ description: "Everyone"`,
+		`- settings.password.age: {"maxAgeDays":90}`,
+		`~ settings.password.lockout.maxAttempts: 3 => 10`,
+	}
+	if res := diffJSON("", current, desired); !reflect.DeepEqual(res, expected) {
+		t.Errorf("Expected diff:\n%q\nreceived:\n%q", expected, res)
+	}
+	if res := diffJSON("", current, current); len(res) != 0 {
+		t.Errorf("Expected no diff between identical values, received %q", res)
+	}
+}
@@ -0,0 +1,74 @@
+package command
+
+import (
+	"github.com/okta/okta-sdk-golang/okta"
+	"reflect"
+	"testing"
+)
+
+func TestFormatMinutes(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[int64]string{
+		0:     "",
+		1:     "1 minute",
+		45:    "45 minutes",
+		60:    "1 hour",
+		150:   "150 minutes",
+		720:   "12 hours",
+		1440:  "1 day",
+		43200: "30 days",
+	}
+	for m, expected := range testCases {
+		if res := formatMinutes(m); res != expected {
+			t.Errorf("Expected %d minutes to be formatted as %q, received %q", m, expected, res)
+		}
+	}
+}
+
+func TestDescribePeopleCondition(t *testing.T) {
+	t.Parallel()
+
+	groups := OktaGroups{
+		{Id: "00g1emaKYZTWRYYRRTSK", Profile: &okta.GroupProfile{Name: "Staff"}},
+	}
+
+	res := describePeopleCondition(&okta.PolicyPeopleCondition{
+		Groups: &okta.GroupCondition{
+			Include: []string{"00g1emaKYZTWRYYRRTSK"},
+			Exclude: []string{"00g000000000000000ZZ"},
+		},
+	}, groups)
+	expected := []string{
+		"Groups:\tStaff (00g1emaKYZTWRYYRRTSK)",
+		"Excluded groups:\t[Unknown] (00g000000000000000ZZ)",
+	}
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("Expected %q, received %q", expected, res)
+	}
+
+	if res := describePeopleCondition(nil, groups); !reflect.DeepEqual(res, []string{"People:\tEveryone"}) {
+		t.Errorf("Expected policy without conditions to apply to everyone, received %q", res)
+	}
+}
+
+func TestDescribePasswordSettings(t *testing.T) {
+	t.Parallel()
+
+	res := describePasswordSettings(&okta.PasswordPolicyPasswordSettings{
+		Age:     &okta.PasswordPolicyPasswordSettingsAge{MaxAgeDays: 0, MinAgeMinutes: 60, HistoryCount: 4},
+		Lockout: &okta.PasswordPolicyPasswordSettingsLockout{MaxAttempts: 10},
+	})
+	expected := []string{
+		"Maximum age:\tNever expires",
+		"Minimum age:\t1 hour",
+		"Expiry warning:\tNone",
+		"Password history:\t4",
+		"Lock out after failed attempts:\t10",
+		"Auto unlock:\tNever",
+		"Show lockout failures:\tNo",
+	}
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("Expected %q, received %q", expected, res)
+	}
+}
@@ -0,0 +1,61 @@
+package command
+
+import (
+	"context"
+	"sync"
+)
+
+// runPool calls fn for every index in [0, n), running at most
+// parallelism calls at a time. The error returned by each call is
+// stored at its index, so callers can collect results in order by
+// writing them to a slice of size n from within fn.
+//
+// Once ctx is cancelled no further calls are started and the
+// indices which weren't processed are assigned ctx.Err(). Calls
+// already in progress receive the cancelled ctx and runPool waits
+// for them to return, so no goroutines outlive it.
+func runPool(ctx context.Context, n, parallelism int, fn func(ctx context.Context, i int) error) []error {
+	errs := make([]error, n, n)
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	if parallelism > n {
+		parallelism = n
+	}
+
+	var (
+		indices = make(chan int)
+		wg      sync.WaitGroup
+	)
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				errs[i] = fn(ctx, i)
+			}
+		}()
+	}
+
+	i := 0
+feed:
+	for ; i < n; i++ {
+		// Checked first so that no call is started after
+		// cancellation even if a worker is ready.
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case indices <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indices)
+	wg.Wait()
+
+	for ; i < n; i++ {
+		errs[i] = ctx.Err()
+	}
+	return errs
+}
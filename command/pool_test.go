@@ -0,0 +1,131 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunPool(t *testing.T) {
+	t.Run("collects results in order", func(t *testing.T) {
+		t.Parallel()
+
+		results := make([]int, 20, 20)
+		errs := runPool(context.Background(), len(results), 4, func(ctx context.Context, i int) error {
+			// Later items finish first
+			time.Sleep(time.Duration(len(results)-i) * time.Millisecond)
+			results[i] = i * i
+			if i%5 == 0 {
+				return errors.New(fmt.Sprint(i))
+			}
+			return nil
+		})
+
+		for i := range results {
+			if results[i] != i*i {
+				t.Errorf("Expected results[%d] to be %d, received %d", i, i*i, results[i])
+			}
+			if i%5 == 0 {
+				if errs[i] == nil || errs[i].Error() != fmt.Sprint(i) {
+					t.Errorf("Expected errs[%d] to be %d, received %v", i, i, errs[i])
+				}
+			} else if errs[i] != nil {
+				t.Errorf("Expected errs[%d] to be nil, received %v", i, errs[i])
+			}
+		}
+	})
+
+	t.Run("limits parallelism", func(t *testing.T) {
+		t.Parallel()
+
+		var running, max int32
+		runPool(context.Background(), 30, 3, func(ctx context.Context, i int) error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&max)
+				if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+					break
+				}
+			}
+			time.Sleep(2 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return nil
+		})
+
+		if max > 3 {
+			t.Errorf("Expected at most 3 concurrent calls, received %d", max)
+		}
+	})
+
+	t.Run("without items", func(t *testing.T) {
+		t.Parallel()
+
+		errs := runPool(context.Background(), 0, 5, func(ctx context.Context, i int) error {
+			t.Errorf("Expected no calls, received call for %d", i)
+			return nil
+		})
+		if len(errs) != 0 {
+			t.Errorf("Expected no errors, received %v", errs)
+		}
+	})
+
+	t.Run("stops on cancellation without leaking", func(t *testing.T) {
+		before := runtime.NumGoroutine()
+
+		var (
+			started int32
+			mu      sync.Mutex
+			seen    = map[int]bool{}
+		)
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := runPool(ctx, 50, 2, func(ctx context.Context, i int) error {
+			mu.Lock()
+			seen[i] = true
+			mu.Unlock()
+			if atomic.AddInt32(&started, 1) == 2 {
+				cancel()
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		// A worker freed by the cancellation may race the feeder
+		// for one more item.
+		if n := atomic.LoadInt32(&started); n < 2 || n > 3 {
+			t.Errorf("Expected 2 or 3 calls to start before cancellation, received %d", n)
+		}
+		for i, err := range errs {
+			if err != context.Canceled {
+				t.Errorf("Expected errs[%d] to be %v, received %v", i, context.Canceled, err)
+			}
+			if i >= 3 && seen[i] {
+				t.Errorf("Expected item %d not to be started after cancellation", i)
+			}
+		}
+
+		// Goroutines exiting may take a moment to be accounted for
+		for i := 0; i < 100 && runtime.NumGoroutine() > before; i++ {
+			time.Sleep(time.Millisecond)
+		}
+		if after := runtime.NumGoroutine(); after > before {
+			t.Errorf("Expected no goroutines to be left running, %d before and %d after", before, after)
+		}
+	})
+}
+
+func TestWithInterrupt(t *testing.T) {
+	ctx, cancel := withInterrupt(context.Background())
+	if ctx.Err() != nil {
+		t.Fatalf("Expected context not to be cancelled, received %v", ctx.Err())
+	}
+	cancel()
+	<-ctx.Done()
+	if ctx.Err() != context.Canceled {
+		t.Errorf("Expected context to be cancelled, received %v", ctx.Err())
+	}
+}
@@ -1,7 +1,8 @@
 package command
 
 import (
-	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"errors"
+	"fmt"
 	"net/http"
 )
 
@@ -10,7 +11,7 @@ type ResetUserMultifactorsCommand struct {
 }
 
 type ResetUserMultifactorsCommandConfig struct {
-	EmailID string
+	userBatchConfig
 }
 
 func (c *ResetUserMultifactorsCommand) Synopsis() string {
@@ -25,10 +26,24 @@ Usage: okta-admin reset-user-mfa [options]
   Okta doesn't notify the user of this reset explicity,
   but rather lets them setup their Multifactors when they
   log into the domain post MFA reset.
+
+  Multifactors of multiple members can be reset at once by
+  combining -user, -users and -file. A summary is printed at
+  the end and the command fails if any member's Multifactors
+  couldn't be reset.
 {{.GlobalOptionsHelpText}}
 Options:
 
-  -email Email ID of the organization member
+  -user        ID, login or email ID of the organization member.
+               -email is accepted as an alias.
+  -users       Comma-separated list of IDs, logins or email IDs
+               of organization members. -emails is accepted as an
+               alias.
+  -file        File containing one ID, login or email ID per
+               line. Specify - (or pass - as the argument) to read
+               from standard input.
+  -parallelism Maximum number of members whose Multifactors are
+               reset concurrently (Default: 5)
 `
 
 	return c.Command.prepareHelpMessage(
@@ -41,58 +56,38 @@ Options:
 
 func (c *ResetUserMultifactorsCommand) ParseArgs(args []string) (*ResetUserMultifactorsCommandConfig, error) {
 	var cfg ResetUserMultifactorsCommandConfig
-
-	flags := c.Meta.FlagSet
-	flags.StringVar(&cfg.EmailID, "email", "", "")
-
-	if err := flags.Parse(args); err != nil {
-		return &cfg, err
-	}
-	err := c.Command.validateParameters(
-		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
-		&parameter{Name: "email", Required: true, Value: cfg.EmailID, ValidationFunc: ValidateEmailID},
-		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
-	)
+	err := c.Command.parseUserBatchArgs(args, &cfg.userBatchConfig)
 	return &cfg, err
 }
 
 func (c *ResetUserMultifactorsCommand) Run(args []string) int {
 	cfg, err := c.ParseArgs(args)
 	if err != nil {
-		c.Logger.Printf("Failed to parse arguments: %v\n", err)
-		return 1
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
 	}
 
 	client, err := c.OktaClient()
 	if err != nil {
-		c.Logger.Printf("Failed to initialize Okta client: %v\n", err)
-		return 1
-	}
-
-	// Fetch user ID
-	user, _, err := oktaapi.GetUserByEmail(
-		&oktaapi.Credentials{
-			OrgUrl:   c.Meta.GlobalOptions.OrgUrl,
-			ApiToken: c.Meta.GlobalOptions.ApiToken,
-		},
-		cfg.EmailID,
-	)
-	if err != nil {
-		c.Logger.Printf("Failed to resolve user ID: %v\n", err)
-		return 1
-	}
-
-	// Reset all Multifactors
-	resp, err := client.User.ResetAllFactors(user["id"].(string))
-	if err != nil {
-		c.Logger.Printf("Failed to reset member's multifactors: %v\n", err)
-		return 1
-	}
-	if resp.StatusCode != http.StatusOK {
-		c.Logger.Printf("Failed to reset member's multifactors: %s\n", resp.Status)
-		return 1
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
 	}
 
-	c.Logger.Printf("All multifactors for %s have been reset\n", cfg.EmailID)
-	return 0
+	return c.runUserBatch(&cfg.userBatchConfig, "reset multifactors of", func(user string) (string, error) {
+		// Fetch user ID
+		uid, err := resolveUser(client, user)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve user ID: %w", err)
+		}
+
+		// Reset all Multifactors
+		resp, err := client.ResetAllFactors(uid)
+		if err != nil {
+			return "", err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return "", errors.New(resp.Status)
+		}
+		return fmt.Sprintf("All multifactors for %s have been reset", user), nil
+	})
 }
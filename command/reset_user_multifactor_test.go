@@ -1,6 +1,8 @@
 package command
 
 import (
+	"errors"
+	"github.com/okta/okta-sdk-golang/okta"
 	"testing"
 )
 
@@ -29,7 +31,25 @@ func TestResetUserMultifactorsCommand_ParseArgs(t *testing.T) {
 		t.Fatalf("Failed to parse arguments: %v", err)
 	}
 
-	if cfg.EmailID != args[1] {
-		t.Errorf("Expected email id to be %s, received %s", args[1], cfg.EmailID)
+	if cfg.User != args[1] {
+		t.Errorf("Expected user to be %s, received %s", args[1], cfg.User)
+	}
+}
+
+func TestResetUserMultifactorsCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.users = []*okta.User{fakeUser("00u1", "harry.potter@hogwarts.co.uk", "ACTIVE")}
+	client.errs["ResetAllFactors"] = errors.New("the API returned an error: forbidden")
+	c, out := createTestCommandWithClient("test_reset_user_mfa_cmd", client)
+	cmd := &ResetUserMultifactorsCommand{Command: c}
+
+	if code := cmd.Run([]string{"-email", "harry.potter@hogwarts.co.uk"}); code != 1 {
+		t.Errorf("Expected exit code 1, received %d", code)
+	}
+	expected := "Failed to reset multifactors of harry.potter@hogwarts.co.uk: the API returned an error: forbidden\n"
+	if out.String() != expected {
+		t.Errorf("Expected output %q, received %q", expected, out)
 	}
 }
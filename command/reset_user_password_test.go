@@ -1,6 +1,7 @@
 package command
 
 import (
+	"github.com/okta/okta-sdk-golang/okta"
 	"testing"
 )
 
@@ -29,7 +30,26 @@ func TestResetUserPasswordCommand_ParseArgs(t *testing.T) {
 		t.Fatalf("Failed to parse arguments: %v", err)
 	}
 
-	if cfg.EmailID != args[1] {
-		t.Errorf("Expected email id to be %s, received %s", args[1], cfg.EmailID)
+	if cfg.User != args[1] {
+		t.Errorf("Expected user to be %s, received %s", args[1], cfg.User)
+	}
+}
+
+func TestResetUserPasswordCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.users = []*okta.User{fakeUser("00u1", "harry.potter@hogwarts.co.uk", "ACTIVE")}
+	c, out := createTestCommandWithClient("test_reset_user_password_cmd", client)
+	cmd := &ResetUserPasswordCommand{Command: c}
+
+	if code := cmd.Run([]string{"-email", "harry.potter@hogwarts.co.uk"}); code != 0 {
+		t.Fatalf("Expected exit code 0, received %d: %s", code, out)
+	}
+	if out.String() != "Reset link sent to harry.potter@hogwarts.co.uk\n" {
+		t.Errorf("Unexpected output %q", out)
+	}
+	if !testEq(client.calls, []string{"GetUser harry.potter@hogwarts.co.uk", "ResetPassword 00u1"}) {
+		t.Errorf("Unexpected API calls %v", client.calls)
 	}
 }
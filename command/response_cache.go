@@ -0,0 +1,271 @@
+package command
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// cacheGroupsTTL is how long listings of groups are cached.
+	cacheGroupsTTL = 10 * time.Minute
+
+	// cacheUsersTTL is how long the IDs users are resolved to are
+	// cached.
+	cacheUsersTTL = time.Hour
+)
+
+// diskCache stores the responses cached by a cachingClient in files
+// in a directory, each of which expires ttl after being stored.
+// Failing to read or write a file is treated as a cache miss, since
+// the cache only saves requests.
+type diskCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// cachedResponse is the content of a file of a diskCache.
+type cachedResponse struct {
+	Key     string      `json:"key"`
+	Expires time.Time   `json:"expires"`
+	Status  int         `json:"status"`
+	Header  http.Header `json:"header"`
+	Body    []byte      `json:"body"`
+}
+
+func (d *diskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load returns the response cached with the key, unless it has
+// expired.
+func (d *diskCache) load(key string) *cachedResponse {
+	data, err := ioutil.ReadFile(d.path(key))
+	if err != nil {
+		return nil
+	}
+	var r cachedResponse
+	if err := json.Unmarshal(data, &r); err != nil || r.Key != key || time.Now().After(r.Expires) {
+		return nil
+	}
+	return &r
+}
+
+func (d *diskCache) Get(key string) *http.Response {
+	r := d.load(key)
+	if r == nil {
+		return nil
+	}
+	return &http.Response{
+		StatusCode: r.Status,
+		Status:     fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		Header:     r.Header,
+		Body:       ioutil.NopCloser(bytes.NewReader(r.Body)),
+	}
+}
+
+// Set stores the response with the key. Its body is read, and
+// replaced so that it can still be read by the caller.
+func (d *diskCache) Set(key string, value *http.Response) {
+	body, err := ioutil.ReadAll(value.Body)
+	value.Body.Close()
+	value.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+
+	data, err := json.Marshal(&cachedResponse{
+		Key:     key,
+		Expires: time.Now().Add(d.ttl),
+		Status:  value.StatusCode,
+		Header:  value.Header,
+		Body:    body,
+	})
+	if err == nil {
+		_ = writeFileAtomic(d.path(key), data)
+	}
+}
+
+func (d *diskCache) Delete(key string) {
+	_ = os.Remove(d.path(key))
+}
+
+func (d *diskCache) Clear() {
+	_ = os.RemoveAll(d.dir)
+}
+
+func (d *diskCache) Has(key string) bool {
+	return d.load(key) != nil
+}
+
+// deleteMatching deletes the responses for which match returns
+// true, along with the expired ones.
+func (d *diskCache) deleteMatching(match func(r *cachedResponse) bool) {
+	files, err := filepath.Glob(filepath.Join(d.dir, "*.json"))
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		var r cachedResponse
+		if err := json.Unmarshal(data, &r); err != nil || time.Now().After(r.Expires) || match(&r) {
+			_ = os.Remove(f)
+		}
+	}
+}
+
+// userIDCache is implemented by clients which cache the IDs users
+// are resolved to by resolveUser.
+type userIDCache interface {
+	cachedUserID(user string) (string, bool)
+	cacheUserID(user, id string)
+}
+
+// groupCache is implemented by clients which cache groups.
+type groupCache interface {
+	forgetGroups()
+}
+
+// cachingClient caches listings of groups and the IDs users are
+// resolved to on disk, in the organization's cache directory, so
+// that commands run one after another, eg- by scripts, don't have
+// to fetch them each time. Mutations made via the client delete
+// the cached data they change.
+type cachingClient struct {
+	oktaapi.Client
+
+	orgUrl string
+	groups *diskCache
+	users  *diskCache
+}
+
+func newCachingClient(client oktaapi.Client, orgUrl string) (*cachingClient, error) {
+	dir, err := orgCacheDir(orgUrl)
+	if err != nil {
+		return nil, err
+	}
+	return &cachingClient{
+		Client: client,
+		orgUrl: strings.TrimRight(orgUrl, "/"),
+		groups: &diskCache{dir: filepath.Join(dir, "groups"), ttl: cacheGroupsTTL},
+		users:  &diskCache{dir: filepath.Join(dir, "users"), ttl: cacheUsersTTL},
+	}, nil
+}
+
+// key returns the cache key of a request to the API path, which
+// is its URL, like the keys of the SDK's cache.
+func (c *cachingClient) key(path string, qp *query.Params) string {
+	key := c.orgUrl + path
+	if qp != nil {
+		key += qp.String()
+	}
+	return key
+}
+
+func (c *cachingClient) userKey(user string) string {
+	return c.key("/api/v1/users/"+url.PathEscape(strings.ToLower(user)), nil)
+}
+
+// ListGroups returns the cached page of groups, or fetches it and
+// caches it along with the link to the next page.
+func (c *cachingClient) ListGroups(qp *query.Params) ([]*okta.Group, *okta.Response, error) {
+	key := c.key("/api/v1/groups", qp)
+	if resp := c.groups.Get(key); resp != nil {
+		var groups []*okta.Group
+		if err := json.NewDecoder(resp.Body).Decode(&groups); err == nil {
+			return groups, &okta.Response{Response: resp}, nil
+		}
+	}
+
+	groups, resp, err := c.Client.ListGroups(qp)
+	if err != nil || resp == nil || resp.StatusCode != http.StatusOK {
+		return groups, resp, err
+	}
+	if body, err := json.Marshal(groups); err == nil {
+		c.groups.Set(key, &http.Response{
+			StatusCode: resp.StatusCode,
+			Header:     http.Header{"Link": resp.Header["Link"]},
+			Body:       ioutil.NopCloser(bytes.NewReader(body)),
+		})
+	}
+	return groups, resp, err
+}
+
+func (c *cachingClient) forgetGroups() {
+	c.groups.Clear()
+}
+
+func (c *cachingClient) cachedUserID(user string) (string, bool) {
+	resp := c.users.Get(c.userKey(user))
+	if resp == nil {
+		return "", false
+	}
+	var u struct {
+		Id string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&u); err != nil || u.Id == "" {
+		return "", false
+	}
+	return u.Id, true
+}
+
+func (c *cachingClient) cacheUserID(user, id string) {
+	body, err := json.Marshal(map[string]string{"id": id})
+	if err != nil {
+		return
+	}
+	c.users.Set(c.userKey(user), &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(bytes.NewReader(body)),
+	})
+}
+
+// forgetUser deletes the cached lookups which resolved to the user
+// with the ID.
+func (c *cachingClient) forgetUser(id string) {
+	c.users.deleteMatching(func(r *cachedResponse) bool {
+		var u struct {
+			Id string `json:"id"`
+		}
+		return json.Unmarshal(r.Body, &u) != nil || u.Id == id
+	})
+}
+
+// CreateUser deletes the cached lookups of the new user's login and
+// email ID, since they may have resolved to another user, eg- one
+// with the same email ID.
+func (c *cachingClient) CreateUser(user okta.User, qp *query.Params) (*okta.User, *okta.Response, error) {
+	res, resp, err := c.Client.CreateUser(user, qp)
+	for _, u := range []*okta.User{&user, res} {
+		p := oktaapi.ProfileOf(u)
+		for _, v := range []string{p.Login(), p.Email()} {
+			if v != "" {
+				c.users.Delete(c.userKey(v))
+			}
+		}
+	}
+	return res, resp, err
+}
+
+func (c *cachingClient) DeactivateUser(userID string, qp *query.Params) (*okta.Response, error) {
+	resp, err := c.Client.DeactivateUser(userID, qp)
+	c.forgetUser(userID)
+	return resp, err
+}
@@ -0,0 +1,124 @@
+package command
+
+import (
+	"bytes"
+	"github.com/okta/okta-sdk-golang/okta"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDiskCache(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "okta-admin-disk-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &diskCache{dir: filepath.Join(dir, "groups"), ttl: time.Minute}
+	defer d.Clear()
+
+	const key = "https://foo.okta.com/api/v1/groups?limit=2"
+	if d.Has(key) || d.Get(key) != nil {
+		t.Errorf("Expected nothing to be cached")
+	}
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Link": []string{`<https://foo.okta.com/api/v1/groups?after=00g2>; rel="next"`}},
+		Body:       ioutil.NopCloser(bytes.NewBufferString(`[{"id":"00g1"}]`)),
+	}
+	d.Set(key, resp)
+	if body, _ := ioutil.ReadAll(resp.Body); string(body) != `[{"id":"00g1"}]` {
+		t.Errorf("Expected the body to still be readable once cached, received %q", body)
+	}
+
+	cached := d.Get(key)
+	if cached == nil {
+		t.Fatalf("Expected response to be cached")
+	}
+	body, _ := ioutil.ReadAll(cached.Body)
+	if cached.StatusCode != http.StatusOK || string(body) != `[{"id":"00g1"}]` || cached.Header.Get("Link") != resp.Header.Get("Link") {
+		t.Errorf("Expected the cached response to match, received %d %v %q", cached.StatusCode, cached.Header, body)
+	}
+
+	d.Delete(key)
+	if d.Has(key) {
+		t.Errorf("Expected response to be deleted")
+	}
+
+	// Responses expire
+	d.ttl = -time.Second
+	d.Set(key, &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(&bytes.Buffer{})})
+	if d.Has(key) {
+		t.Errorf("Expected response to have expired")
+	}
+}
+
+func TestCachingClient(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.users = []*okta.User{fakeUser("00u1", "harry.potter@hogwarts.co.uk", "ACTIVE")}
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor")}
+	cc, err := newCachingClient(client, "https://caching.okta.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.groups.Clear()
+	defer cc.users.Clear()
+
+	countCalls := func(op string) int {
+		n := 0
+		for _, call := range client.calls {
+			if call == op || len(call) > len(op) && call[:len(op)+1] == op+" " {
+				n++
+			}
+		}
+		return n
+	}
+
+	// Groups are fetched once per query
+	for i := 0; i < 2; i++ {
+		groups, resp, err := cc.ListGroups(nil)
+		if err != nil || resp.StatusCode != http.StatusOK || len(groups) != 1 || groups[0].Profile.Name != "Gryffindor" {
+			t.Errorf("Expected Gryffindor to be listed, received %v, %v", groups, err)
+		}
+	}
+	if _, _, err := cc.ListGroups(query.NewQueryParams(query.WithQ("Gry"))); err != nil {
+		t.Fatal(err)
+	}
+	if n := countCalls("ListGroups"); n != 2 {
+		t.Errorf("Expected groups to be listed twice, calls made: %v", client.calls)
+	}
+
+	// Users are resolved once, until they're deactivated
+	for i := 0; i < 2; i++ {
+		if uid, err := resolveUser(cc, "harry.potter@hogwarts.co.uk"); err != nil || uid != "00u1" {
+			t.Errorf("Expected user to be resolved to 00u1, received %q (error %v)", uid, err)
+		}
+	}
+	if n := countCalls("GetUser"); n != 1 {
+		t.Errorf("Expected user to be looked up once, calls made: %v", client.calls)
+	}
+	if _, err := cc.DeactivateUser("00u1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resolveUser(cc, "harry.potter@hogwarts.co.uk"); err != nil {
+		t.Fatal(err)
+	}
+	if n := countCalls("GetUser"); n != 2 {
+		t.Errorf("Expected user to be looked up again once deactivated, calls made: %v", client.calls)
+	}
+
+	// Creating a user forgets the lookups of their email ID
+	if _, _, err := cc.CreateUser(okta.User{Profile: &okta.UserProfile{"login": "harry.potter@hogwarts.co.uk", "email": "harry.potter@hogwarts.co.uk"}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cc.cachedUserID("harry.potter@hogwarts.co.uk"); ok {
+		t.Errorf("Expected lookup of the new user's email ID to be forgotten")
+	}
+}
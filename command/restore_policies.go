@@ -0,0 +1,408 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"log"
+)
+
+const policyStatusActive = "ACTIVE"
+
+type RestorePoliciesCommand struct {
+	*Command
+}
+
+type RestorePoliciesCommandConfig struct {
+	File          string
+	DryRun        bool
+	AllowOtherOrg bool
+}
+
+func (c *RestorePoliciesCommand) Synopsis() string {
+	return "Restore policies and their rules from a backup"
+}
+
+func (c *RestorePoliciesCommand) Help() string {
+	helpText := `
+Usage: okta-admin restore-policies [options]
+
+  Restores policies and rules from a backup created by
+  backup-policies. The backup is compared against the policies
+  currently in the organization and every difference is printed.
+  Policies and rules missing from the organization are recreated,
+  while those which differ from the backup are updated, in order
+  of priority.
+
+  Policies and rules are matched by ID or, if the ID doesn't
+  exist (eg- they were deleted), by name. Each policy or rule in
+  the organization is matched at most once, so backed up ones
+  sharing a name aren't restored onto the same one. Policies and
+  rules which aren't in the backup are left unchanged.
+
+  Backups of other organizations aren't restored, unless
+  -allow-other-org is specified, eg- to copy the policies of a
+  preview organization to production.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -file             Backup file created by backup-policies
+  -dry-run          Only print the changes which would be made,
+                    without making them
+  -allow-other-org  Restore the backup even if it was created for
+                    another organization
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *RestorePoliciesCommand) ParseArgs(args []string) (*RestorePoliciesCommandConfig, error) {
+	var cfg RestorePoliciesCommandConfig
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.File, "file", "", "")
+	flags.BoolVar(&cfg.DryRun, "dry-run", false, "")
+	flags.BoolVar(&cfg.AllowOtherOrg, "allow-other-org", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "file", Required: true, Value: cfg.File},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+	)
+	return &cfg, err
+}
+
+func (c *RestorePoliciesCommand) Run(args []string) int {
+	var (
+		restored int
+		failures []error
+	)
+
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	orgUrl, policies, rules, err := loadPolicyBackup(cfg.File)
+	if err != nil {
+		c.logError("Failed to load backup", err)
+		return exitCode(err)
+	}
+	if orgUrl != "" && !sameOrgUrl(orgUrl, c.Meta.GlobalOptions.OrgUrl) {
+		if !cfg.AllowOtherOrg {
+			c.Logger.Printf("The backup was created for %s, not %s. Specify -allow-other-org to restore it anyway.\n", orgUrl, c.Meta.GlobalOptions.OrgUrl)
+			return ExitUsage
+		}
+		c.Logger.Printf("Restoring the backup of %s to %s\n", orgUrl, c.Meta.GlobalOptions.OrgUrl)
+	}
+
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+	r := &policyRestorer{
+		client: client,
+		logger: c.Logger,
+		dryRun: cfg.DryRun,
+	}
+
+	live := map[string][]*oktaapi.Policy{}
+	var supported []*oktaapi.Policy
+	for _, p := range policies {
+		if policyTypeIndex(p.Type) < 0 {
+			c.Logger.Printf("Skipping policy %s of unsupported type %s\n", p.Name, p.Type)
+			continue
+		}
+		if _, ok := live[p.Type]; !ok {
+			if live[p.Type], err = listPolicies(r.client, p.Type); err != nil {
+				c.logError(fmt.Sprintf("Failed to fetch list of %s policies", p.Type), err)
+				return exitCode(err)
+			}
+		}
+		supported = append(supported, p)
+	}
+
+	matches := map[*oktaapi.Policy]*oktaapi.Policy{}
+	for _, t := range policyTypes {
+		var backedUp []*oktaapi.Policy
+		for _, p := range supported {
+			if p.Type == t {
+				backedUp = append(backedUp, p)
+			}
+		}
+		for p, lp := range matchPolicies(backedUp, live[t]) {
+			matches[p] = lp
+		}
+	}
+
+	matched := map[string]bool{}
+	for _, p := range supported {
+		restored++
+		lp, err := r.restorePolicy(p, rules[p.Id], matches[p])
+		if err != nil {
+			c.logError(fmt.Sprintf("Failed to restore %s policy %s", p.Type, p.Name), err)
+			failures = append(failures, err)
+		}
+		if lp != nil {
+			matched[lp.Id] = true
+		}
+	}
+
+	for _, t := range policyTypes {
+		for _, lp := range live[t] {
+			if !matched[lp.Id] {
+				c.Logger.Printf("%s policy %q is not in the backup, leaving it unchanged\n", lp.Type, lp.Name)
+			}
+		}
+	}
+
+	if cfg.DryRun {
+		c.Logger.Printf("\nDry run: %d change(s) would be made\n", r.changes)
+	} else {
+		c.Logger.Printf("\nMade %d change(s)\n", r.changes)
+	}
+	if len(failures) > 0 {
+		c.Logger.Printf("Failed to restore %d policies\n", len(failures))
+	}
+	return fanOutExitCode(restored, failures)
+}
+
+// matchByIdOrName matches backed up policies or rules, identified
+// by their IDs and names, with live ones with the same ID or, if
+// there is none, the same name. Matches by ID take precedence, and
+// each live one is matched at most once. The index of the live one
+// matched with each backed up one is returned, or -1.
+func matchByIdOrName(ids, names, liveIds, liveNames []string) []int {
+	res := make([]int, len(ids))
+	taken := make([]bool, len(liveIds))
+	for i, id := range ids {
+		res[i] = -1
+		for j, liveId := range liveIds {
+			if !taken[j] && liveId == id {
+				res[i], taken[j] = j, true
+				break
+			}
+		}
+	}
+	for i, name := range names {
+		if res[i] >= 0 {
+			continue
+		}
+		for j, liveName := range liveNames {
+			if !taken[j] && liveName == name {
+				res[i], taken[j] = j, true
+				break
+			}
+		}
+	}
+	return res
+}
+
+// matchPolicies returns the live policies matched with the backed
+// up ones, by ID or name. Backed up policies without a match
+// aren't in the map.
+func matchPolicies(policies, live []*oktaapi.Policy) map[*oktaapi.Policy]*oktaapi.Policy {
+	var ids, names, liveIds, liveNames []string
+	for _, p := range policies {
+		ids, names = append(ids, p.Id), append(names, p.Name)
+	}
+	for _, lp := range live {
+		liveIds, liveNames = append(liveIds, lp.Id), append(liveNames, lp.Name)
+	}
+	res := map[*oktaapi.Policy]*oktaapi.Policy{}
+	for i, j := range matchByIdOrName(ids, names, liveIds, liveNames) {
+		if j >= 0 {
+			res[policies[i]] = live[j]
+		}
+	}
+	return res
+}
+
+// matchPolicyRules returns the live rules matched with the backed
+// up ones, by ID or name. Backed up rules without a match aren't
+// in the map.
+func matchPolicyRules(rules, live []*oktaapi.PolicyRule) map[*oktaapi.PolicyRule]*oktaapi.PolicyRule {
+	var ids, names, liveIds, liveNames []string
+	for _, r := range rules {
+		ids, names = append(ids, r.Id), append(names, r.Name)
+	}
+	for _, lr := range live {
+		liveIds, liveNames = append(liveIds, lr.Id), append(liveNames, lr.Name)
+	}
+	res := map[*oktaapi.PolicyRule]*oktaapi.PolicyRule{}
+	for i, j := range matchByIdOrName(ids, names, liveIds, liveNames) {
+		if j >= 0 {
+			res[rules[i]] = live[j]
+		}
+	}
+	return res
+}
+
+// policyRestorer prints and, unless running dry, makes the changes
+// required to bring policies in line with a backup.
+type policyRestorer struct {
+	client  oktaapi.Client
+	logger  *log.Logger
+	dryRun  bool
+	changes int
+}
+
+// restorePolicy recreates or updates a live policy and its rules
+// from the backup. The live policy is returned, which is nil if
+// it didn't exist and the restorer is running dry.
+func (r *policyRestorer) restorePolicy(p *oktaapi.Policy, rules []*oktaapi.PolicyRule, live *oktaapi.Policy) (*oktaapi.Policy, error) {
+	var liveRules []*oktaapi.PolicyRule
+
+	body, err := restorableFields(p.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if live == nil {
+		if p.System {
+			return nil, errors.New("system policies cannot be recreated")
+		}
+		r.logger.Printf("+ %s policy %q\n", p.Type, p.Name)
+		r.changes++
+		if !r.dryRun {
+			if live, _, err = r.client.CreatePolicy(body, p.Status == policyStatusActive); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		current, err := restorableFields(live.Raw)
+		if err != nil {
+			return live, err
+		}
+		if err := r.restore(fmt.Sprintf("%s policy %q", p.Type, p.Name), current, body, live.Status, p.Status,
+			func() error {
+				_, err := r.client.UpdatePolicy(live.Id, body)
+				return err
+			},
+			func(active bool) (err error) {
+				if active {
+					_, err = r.client.ActivatePolicy(live.Id)
+				} else {
+					_, err = r.client.DeactivatePolicy(live.Id)
+				}
+				return err
+			},
+		); err != nil {
+			return live, err
+		}
+	}
+
+	// Rules of a policy that doesn't exist yet are all created
+	if live != nil {
+		if liveRules, _, err = r.client.ListPolicyRules(live.Id); err != nil {
+			return live, err
+		}
+	}
+
+	matches := matchPolicyRules(rules, liveRules)
+	matched := map[string]bool{}
+	for _, rule := range rules {
+		lr := matches[rule]
+		if lr != nil {
+			matched[lr.Id] = true
+		}
+		if err := r.restoreRule(p, live, rule, lr); err != nil {
+			return live, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+	}
+	for _, lr := range liveRules {
+		if !matched[lr.Id] {
+			r.logger.Printf("%s policy %q rule %q is not in the backup, leaving it unchanged\n", p.Type, p.Name, lr.Name)
+		}
+	}
+
+	return live, nil
+}
+
+// restoreRule recreates or updates a live rule of the backed up
+// policy p from the backup.
+func (r *policyRestorer) restoreRule(p, livePolicy *oktaapi.Policy, rule, live *oktaapi.PolicyRule) error {
+	label := fmt.Sprintf("%s policy %q rule %q", p.Type, p.Name, rule.Name)
+
+	body, err := restorableFields(rule.Raw)
+	if err != nil {
+		return err
+	}
+
+	if live == nil {
+		if rule.System {
+			return errors.New("system rules cannot be recreated")
+		}
+		r.logger.Printf("+ %s\n", label)
+		r.changes++
+		if r.dryRun {
+			return nil
+		}
+		_, err := r.client.CreatePolicyRule(livePolicy.Id, body, rule.Status == policyStatusActive)
+		return err
+	}
+
+	current, err := restorableFields(live.Raw)
+	if err != nil {
+		return err
+	}
+	return r.restore(label, current, body, live.Status, rule.Status,
+		func() error {
+			_, err := r.client.UpdatePolicyRule(livePolicy.Id, live.Id, body)
+			return err
+		},
+		func(active bool) (err error) {
+			if active {
+				_, err = r.client.ActivatePolicyRule(livePolicy.Id, live.Id)
+			} else {
+				_, err = r.client.DeactivatePolicyRule(livePolicy.Id, live.Id)
+			}
+			return err
+		},
+	)
+}
+
+// restore prints the differences between the current and desired
+// fields and status of a policy or rule and, unless running dry,
+// applies them using the functions supplied to it.
+func (r *policyRestorer) restore(label string, current, desired map[string]interface{}, currentStatus, desiredStatus string,
+	update func() error, setStatus func(active bool) error) error {
+	fieldsDiff := diffJSON("", current, desired)
+	diff := fieldsDiff
+	if currentStatus != desiredStatus {
+		diff = append(diff, fmt.Sprintf("~ status: %s => %s", currentStatus, desiredStatus))
+	}
+	if len(diff) == 0 {
+		return nil
+	}
+
+	r.logger.Printf("~ %s\n", label)
+	for _, d := range diff {
+		r.logger.Printf("    %s\n", d)
+	}
+	r.changes++
+	if r.dryRun {
+		return nil
+	}
+
+	if len(fieldsDiff) > 0 {
+		if err := update(); err != nil {
+			return err
+		}
+	}
+	if currentStatus != desiredStatus {
+		return setStatus(desiredStatus == policyStatusActive)
+	}
+	return nil
+}
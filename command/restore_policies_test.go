@@ -0,0 +1,244 @@
+package command
+
+import (
+	"encoding/json"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func createTestRestorePoliciesCommand(globalOptsHelpText string) *RestorePoliciesCommand {
+	return &RestorePoliciesCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_restore_policies_cmd"),
+	}
+}
+
+func TestRestorePoliciesCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestRestorePoliciesCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestRestorePoliciesCommand_ParseArgs(t *testing.T) {
+	t.Run("with file", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestRestorePoliciesCommand("")
+		args := []string{"-file", "policies.json", "-dry-run", "-allow-other-org"}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.File != args[1] {
+			t.Errorf("Expected file to be %s, received %s", args[1], cfg.File)
+		}
+		if !cfg.DryRun {
+			t.Errorf("Expected -dry-run flag to be set")
+		}
+		if !cfg.AllowOtherOrg {
+			t.Errorf("Expected -allow-other-org flag to be set")
+		}
+	})
+
+	t.Run("without file", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestRestorePoliciesCommand("")
+		if _, err := c.ParseArgs([]string{"-dry-run"}); err == nil {
+			t.Errorf("Expected error when file is not specified")
+		}
+	})
+}
+
+// writeTestPolicyBackup writes a backup of the policies and their
+// rules, created for the organization, to a file in dir.
+func writeTestPolicyBackup(t *testing.T, dir, orgUrl string, policies []*oktaapi.Policy, rules map[string][]*oktaapi.PolicyRule) string {
+	t.Helper()
+	backup := &policyBackup{Version: policyBackupVersion, OrgUrl: orgUrl}
+	for _, p := range policies {
+		e := &policyBackupEntry{Policy: p.Raw, Rules: []json.RawMessage{}}
+		for _, r := range rules[p.Id] {
+			e.Rules = append(e.Rules, r.Raw)
+		}
+		backup.Policies = append(backup.Policies, e)
+	}
+	data, err := json.Marshal(backup)
+	if err != nil {
+		t.Fatalf("Failed to encode backup: %v", err)
+	}
+	file := filepath.Join(dir, "policies.json")
+	if err := ioutil.WriteFile(file, data, 0600); err != nil {
+		t.Fatalf("Failed to write backup: %v", err)
+	}
+	return file
+}
+
+func TestMatchPolicies(t *testing.T) {
+	t.Parallel()
+
+	live := []*oktaapi.Policy{
+		fakePolicy("00p1", "Staff", policyTypePassword, ""),
+		fakePolicy("00p2", "Staff", policyTypePassword, ""),
+		fakePolicy("00p3", "Contractors", policyTypePassword, ""),
+	}
+	backedUp := []*oktaapi.Policy{
+		// Matched by name, to the policy not matched by ID
+		fakePolicy("00p8", "Staff", policyTypePassword, ""),
+		fakePolicy("00p1", "Staff", policyTypePassword, ""),
+		// Every policy of the same name is matched already
+		fakePolicy("00p9", "Staff", policyTypePassword, ""),
+		fakePolicy("00p3", "Interns", policyTypePassword, ""),
+	}
+
+	matches := matchPolicies(backedUp, live)
+	for i, expected := range []*oktaapi.Policy{live[1], live[0], nil, live[2]} {
+		if matches[backedUp[i]] != expected {
+			t.Errorf("Expected backed up policy %s to match %v, received %v", backedUp[i].Id, expected, matches[backedUp[i]])
+		}
+	}
+}
+
+func TestRestorePoliciesCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "okta-admin-restore-policies")
+	if err != nil {
+		t.Fatalf("Failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	client := newFakeOktaClient()
+	client.policies = []*oktaapi.Policy{
+		fakePolicy("00p1", "Staff", policyTypePassword, "Employees"),
+		fakePolicy("00p2", "Staff", policyTypePassword, "Temporary staff"),
+	}
+	client.policyRules["00p1"] = []*oktaapi.PolicyRule{
+		fakePolicyRule("0pr1", "Default rule", 1),
+		fakePolicyRule("0pr2", "Default rule", 2),
+	}
+	run := func(args ...string) (int, string) {
+		c, out := createTestCommandWithClient("test_restore_policies_cmd", client)
+		return (&RestorePoliciesCommand{Command: c}).Run(args), out.String()
+	}
+
+	// Two backed up policies of the same name, one of which was
+	// deleted, are restored onto different policies
+	file := writeTestPolicyBackup(t, dir, "https://FOO.okta.com", []*oktaapi.Policy{
+		fakePolicy("00p1", "Staff", policyTypePassword, "Employees"),
+		fakePolicy("00p9", "Staff", policyTypePassword, "Contractors"),
+	}, map[string][]*oktaapi.PolicyRule{
+		"00p1": {fakePolicyRule("0pr1", "Default rule", 1), fakePolicyRule("0pr9", "Default rule", 3)},
+	})
+	code, out := run("-file", file, "-dry-run")
+	if code != ExitOK {
+		t.Fatalf("Expected exit code %d, received %d: %s", ExitOK, code, out)
+	}
+	for _, expected := range []string{
+		`~ PASSWORD policy "Staff"` + "\n" + `    ~ description: "Temporary staff" => "Contractors"`,
+		`~ PASSWORD policy "Staff" rule "Default rule"` + "\n" + `    ~ priority: 2 => 3`,
+		"Dry run: 2 change(s) would be made",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected output to contain %q, received %q", expected, out)
+		}
+	}
+	if strings.Contains(out, "not in the backup") {
+		t.Errorf("Expected every live policy and rule to be matched, received %q", out)
+	}
+
+	// Backups of other organizations aren't restored by default
+	file = writeTestPolicyBackup(t, dir, "https://bar.okta.com/", []*oktaapi.Policy{
+		fakePolicy("00p1", "Staff", policyTypePassword, "Contractors"),
+	}, nil)
+	client.calls = nil
+	code, out = run("-file", file, "-dry-run")
+	if code != ExitUsage || !strings.Contains(out, "https://bar.okta.com/") || len(client.calls) != 0 {
+		t.Errorf("Expected the backup of another organization to be refused, received %d: %s (calls %v)", code, out, client.calls)
+	}
+	code, out = run("-file", file, "-dry-run", "-allow-other-org")
+	if code != ExitOK || !strings.Contains(out, `~ description: "Employees" => "Contractors"`) {
+		t.Errorf("Expected -allow-other-org to restore the backup, received %d: %s", code, out)
+	}
+}
+
+func TestRestorePoliciesCommand_RunRestore(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "okta-admin-restore-policies")
+	if err != nil {
+		t.Fatalf("Failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "policies.json")
+
+	client := newFakeOktaClient()
+	client.policies = []*oktaapi.Policy{
+		fakePolicy("00p1", "Staff", policyTypePassword, "Employees"),
+		fakePolicy("00p2", "Contractors", policyTypePassword, "Contractors"),
+		fakePolicy("00p3", "Interns", policyTypePassword, "Interns"),
+	}
+	client.policyRules["00p1"] = []*oktaapi.PolicyRule{
+		fakePolicyRule("0pr1", "Default rule", 1),
+		fakePolicyRule("0pr2", "Admins", 2),
+	}
+	client.policyRules["00p3"] = []*oktaapi.PolicyRule{fakePolicyRule("0pr3", "Default rule", 1)}
+
+	c, buf := createTestCommandWithClient("test_backup_policies_cmd", client)
+	if code := (&BackupPoliciesCommand{Command: c}).Run([]string{"-out", file}); code != ExitOK {
+		t.Fatalf("Failed to back up policies, received %d: %s", code, buf)
+	}
+
+	// Misconfigure the policies: change one, deactivate one, and
+	// delete a policy and a rule
+	client.policies[0] = fakePolicy("00p1", "Staff", policyTypePassword, "Everyone")
+	client.DeactivatePolicy("00p2")
+	client.policies = client.policies[:2]
+	delete(client.policyRules, "00p3")
+	client.policyRules["00p1"] = client.policyRules["00p1"][:1]
+	client.calls = nil
+
+	run := func(args ...string) (int, string) {
+		c, out := createTestCommandWithClient("test_restore_policies_cmd", client)
+		return (&RestorePoliciesCommand{Command: c}).Run(append([]string{"-file", file}, args...)), out.String()
+	}
+	code, out := run()
+	if code != ExitOK || !strings.Contains(out, "Made 5 change(s)") {
+		t.Fatalf("Expected the policies to be restored, received %d: %s", code, out)
+	}
+	for _, call := range []string{
+		"UpdatePolicy 00p1",
+		"CreatePolicyRule 00p1",
+		"ActivatePolicy 00p2",
+		"CreatePolicy",
+		"CreatePolicyRule 00pnew2",
+	} {
+		var found bool
+		for _, c := range client.calls {
+			found = found || c == call
+		}
+		if !found {
+			t.Errorf("Expected call %q, received %v", call, client.calls)
+		}
+	}
+	if code, out := run("-dry-run"); code != ExitOK || !strings.Contains(out, "Dry run: 0 change(s) would be made") {
+		t.Errorf("Expected the policies to match the backup, received %d: %s", code, out)
+	}
+
+	// Failures to restore a policy don't stop the others from being
+	// restored
+	client.policies[0] = fakePolicy("00p1", "Staff", policyTypePassword, "Everyone")
+	client.DeactivatePolicy("00p2")
+	client.errs["UpdatePolicy"] = &oktaapi.ApiError{StatusCode: 400, Summary: "Api validation failed"}
+	code, out = run()
+	if code != ExitPartialFailure || !strings.Contains(out, "Failed to restore 1 policies") {
+		t.Errorf("Expected exit code %d, received %d: %s", ExitPartialFailure, code, out)
+	}
+	if client.policies[1].Status != "ACTIVE" {
+		t.Errorf("Expected Contractors to be activated despite the failure")
+	}
+}
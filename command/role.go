@@ -0,0 +1,116 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta"
+	"net/http"
+)
+
+const (
+	roleTypeSuperAdmin = "SUPER_ADMIN"
+
+	userStatusSuspended     = "SUSPENDED"
+	userStatusDeprovisioned = "DEPROVISIONED"
+	factorStatusActive      = "ACTIVE"
+)
+
+// groupScopedRoleTypes contains the types of administrator roles
+// which can be restricted to a set of groups. Other roles always
+// apply to the whole organization.
+var groupScopedRoleTypes = map[string]bool{
+	"USER_ADMIN":             true,
+	"HELP_DESK_ADMIN":        true,
+	"GROUP_MEMBERSHIP_ADMIN": true,
+}
+
+// adminRole describes an administrator role assigned to a user
+// along with the names of the groups the role is scoped to.
+// An empty list of groups means that the role applies to the
+// whole organization.
+type adminRole struct {
+	ID     string   `json:"id"`
+	Type   string   `json:"type"`
+	Label  string   `json:"label"`
+	Groups []string `json:"groups"`
+}
+
+// adminAuditRecord contains the audit findings for a single
+// user holding one or more administrator roles.
+type adminAuditRecord struct {
+	UserID      string      `json:"userId"`
+	Login       string      `json:"login"`
+	Email       string      `json:"email"`
+	Status      string      `json:"status"`
+	MfaEnrolled bool        `json:"mfaEnrolled"`
+	Roles       []adminRole `json:"roles"`
+	Flags       []string    `json:"flags"`
+}
+
+// createAdminAuditRecord fetches the administrator roles assigned
+// to a user, the groups they're scoped to and the user's enrolled
+// factors from Okta API. It returns nil if the user doesn't hold
+// any administrator roles.
+func createAdminAuditRecord(client oktaapi.Client, user *okta.User) (*adminAuditRecord, error) {
+	roles, resp, err := client.ListAssignedRoles(user.Id, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(fmt.Sprintf("failed to list roles: %s", resp.Status))
+	}
+	if len(roles) == 0 {
+		return nil, nil
+	}
+
+	record := &adminAuditRecord{
+		UserID: user.Id,
+		Status: user.Status,
+		Roles:  make([]adminRole, 0, len(roles)),
+		Login:  oktaapi.ProfileOf(user).Login(),
+		Email:  oktaapi.ProfileOf(user).Email(),
+		Flags:  []string{},
+	}
+
+	for _, r := range roles {
+		role := adminRole{ID: r.Id, Type: r.Type, Label: r.Label, Groups: []string{}}
+
+		if groupScopedRoleTypes[r.Type] {
+			groups, resp, err := client.ListGroupTargetsForRole(user.Id, r.Id, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list group targets of %s: %w", r.Type, err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				return nil, errors.New(fmt.Sprintf("failed to list group targets of %s: %s", r.Type, resp.Status))
+			}
+			for _, g := range groups {
+				role.Groups = append(role.Groups, g.Profile.Name)
+			}
+		}
+
+		if r.Type == roleTypeSuperAdmin {
+			record.Flags = append(record.Flags, roleTypeSuperAdmin)
+		}
+		record.Roles = append(record.Roles, role)
+	}
+
+	factors, _, err := client.ListUserFactors(user.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list factors: %w", err)
+	}
+	for _, f := range factors {
+		if f.Status == factorStatusActive {
+			record.MfaEnrolled = true
+			break
+		}
+	}
+
+	if !record.MfaEnrolled {
+		record.Flags = append(record.Flags, "NO_MFA")
+	}
+	if user.Status == userStatusSuspended || user.Status == userStatusDeprovisioned {
+		record.Flags = append(record.Flags, user.Status)
+	}
+	return record, nil
+}
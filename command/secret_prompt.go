@@ -0,0 +1,20 @@
+package command
+
+import (
+	"errors"
+	"github.com/mitchellh/cli"
+	"os"
+	"strings"
+)
+
+// readSecret prompts for a secret, like an API token or passphrase,
+// on the terminal and reads it without echoing it. Secrets aren't
+// read when standard input isn't a terminal, since echoing can't be
+// turned off then. It is a variable so that tests can replace it.
+var readSecret = func(prompt string) (string, error) {
+	if !isTerminal(os.Stdin) {
+		return "", errors.New("there's no terminal to prompt on")
+	}
+	ui := &cli.BasicUi{Reader: os.Stdin, Writer: os.Stderr}
+	return ui.AskSecret(strings.TrimSuffix(prompt, " "))
+}
@@ -0,0 +1,49 @@
+package command
+
+import (
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"strings"
+	"time"
+)
+
+// getSessionDetailsPretty returns a pretty string describing the
+// session passed to it.
+func getSessionDetailsPretty(s *oktaapi.Session) string {
+	tpl := `
+ID:                         {{.Id}}
+User:                       {{.User}}
+Status:                     {{.Status}}
+Created:                    {{.CreatedAt}}
+Expires:                    {{.ExpiresAt}}
+Last password verification: {{.LastPasswordVerification}}
+Last factor verification:   {{.LastFactorVerification}}
+Authentication methods:     {{.Amr}}
+Identity provider:          {{.Idp}}
+`
+
+	idp := ""
+	if s.Idp != nil {
+		idp = fmt.Sprintf("%s (%s)", s.Idp.Type, s.Idp.Id)
+	}
+
+	res, _ := FillTemplateMessage(tpl, map[string]interface{}{
+		"Id":                       s.Id,
+		"User":                     fmt.Sprintf("%s (%s)", Coalesce(s.Login, "[Unknown]"), s.UserId),
+		"Status":                   s.Status,
+		"CreatedAt":                formatSessionTime(s.CreatedAt),
+		"ExpiresAt":                formatSessionTime(s.ExpiresAt),
+		"LastPasswordVerification": formatSessionTime(s.LastPasswordVerification),
+		"LastFactorVerification":   formatSessionTime(s.LastFactorVerification),
+		"Amr":                      Coalesce(strings.Join(s.Amr, ", "), "[None]"),
+		"Idp":                      Coalesce(idp, "[None]"),
+	})
+	return res
+}
+
+func formatSessionTime(t *time.Time) string {
+	if t == nil {
+		return "[Never]"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
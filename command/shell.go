@@ -0,0 +1,404 @@
+package command
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/mitchellh/cli"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+// shellInput is the standard input commands are read from.
+var shellInput io.Reader = os.Stdin
+
+// shellBuiltins are the commands handled by the shell itself.
+var shellBuiltins = []string{"exit", "help", "quit"}
+
+type ShellCommand struct {
+	*Command
+
+	// Commands contains the commands which can be run in the
+	// shell, keyed by their names.
+	Commands map[string]cli.CommandFactory
+}
+
+func (c *ShellCommand) Synopsis() string {
+	return "Run commands interactively against an organization"
+}
+
+func (c *ShellCommand) Help() string {
+	helpText := `
+Usage: okta-admin shell [options]
+
+  Opens an interactive prompt which runs okta-admin commands
+  against the organization, eg- "assign-groups -user jane -groups
+  Tech", without having to supply the global options each time.
+  The prompt shows the organization's host. Global options can
+  still be specified to override them for a single command.
+
+  Tab completes the names of commands and their options, group
+  names after -groups and, after -user and -users, email IDs used
+  in the session or by earlier commands which succeeded against
+  the organization. Group names are fetched the first time they
+  are completed. Type help to list the commands, and exit or quit
+  to leave the shell.
+
+  If standard input isn't a terminal, commands are read from it
+  one per line, without a prompt.
+{{.GlobalOptionsHelpText}}
+Keys:
+
+  Up, Down         Recall the previous or next command
+  Left, Right      Move the cursor
+  Home, End        Move to the start or end of the line
+  Tab              Complete the word being typed, or list the
+                   candidates if there are several
+  Ctrl-W, Ctrl-U   Delete the previous word, or up to the cursor
+  Ctrl-C           Discard the line, or abort the running command
+  Ctrl-D           Leave the shell
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *ShellCommand) ParseArgs(args []string) error {
+	flags := c.Meta.FlagSet
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if rest := flags.Args(); len(rest) > 0 {
+		return errors.New(fmt.Sprintf("unexpected arguments: %s", strings.Join(rest, " ")))
+	}
+	if c.Meta.GlobalOptions.ApiTokenStdin {
+		return errors.New("-api-token-stdin cannot be used with the shell, which reads commands from standard input")
+	}
+
+	return c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+	)
+}
+
+func (c *ShellCommand) Run(args []string) int {
+	if err := c.ParseArgs(args); err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	s := newShellSession(c.Command, c.Commands)
+
+	// Every command is parsed with a new FlagSet which defines
+	// the global options too, bound to the same values. Standard
+	// input is read by the shell, so it can't contain API tokens.
+	globals := c.Meta.FlagSet
+	defer func() {
+		c.Meta.FlagSet = globals
+	}()
+	globals.VisitAll(func(f *flag.Flag) {
+		if f.Name != "api-token-stdin" {
+			s.globals = append(s.globals, f)
+		}
+	})
+
+	// Ctrl-C aborts the command being run, not the shell
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	defer signal.Stop(sig)
+
+	read := s.lineReader()
+	prompt := fmt.Sprintf("okta-admin %s> ", orgHost(c.Meta.GlobalOptions.OrgUrl))
+	for {
+		line, err := read(prompt)
+		if err == io.EOF {
+			return ExitOK
+		}
+		if err != nil {
+			c.logError("Failed to read command", err)
+			return ExitFailure
+		}
+
+		words, _, quote := shellWords([]rune(line))
+		if quote != 0 {
+			c.Logger.Printf("Failed to parse command: unterminated %c quote\n", quote)
+			continue
+		}
+		if len(words) == 0 {
+			continue
+		}
+
+		switch words[0] {
+		case "exit", "quit":
+			return ExitOK
+		case "help":
+			// Lists the commands, or describes the one specified
+			words = append(words[1:], "-help")
+		}
+		s.run(words)
+	}
+}
+
+// shellSession contains the state of the shell while it runs.
+type shellSession struct {
+	*Command
+
+	commands  map[string]cli.CommandFactory
+	globals   []*flag.Flag
+	completer *completer
+
+	// groups caches the names of groups, once fetched
+	groups []string
+	// recentUsers contains the email IDs used in the session,
+	// most recent first
+	recentUsers []string
+}
+
+// newShellSession returns the state of a shell which runs the
+// commands, except for the shell itself and hidden ones.
+func newShellSession(c *Command, commands map[string]cli.CommandFactory) *shellSession {
+	s := &shellSession{
+		Command:  c,
+		commands: map[string]cli.CommandFactory{},
+	}
+	for name, f := range commands {
+		if name != "shell" && name != CompleteCommandName {
+			s.commands[name] = f
+		}
+	}
+
+	s.completer = newCompleter(s.commands)
+	s.completer.builtins = shellBuiltins
+	s.completer.groups = s.groupNames
+	s.completer.users = s.users
+	return s
+}
+
+// lineReader returns the func the shell reads commands with. If
+// standard input is a terminal, commands are typed at a prompt
+// in a lineEditor. Otherwise they're read one per line.
+func (s *shellSession) lineReader() func(prompt string) (string, error) {
+	if f, ok := shellInput.(*os.File); ok && isTerminal(f) {
+		// Make sure stty works before relying on it
+		if restore, err := rawTerminal(f); err == nil {
+			restore()
+
+			editor := newLineEditor(f, s.Logger.Writer())
+			editor.complete = s.complete
+			s.Logger.Printf("Type help for the list of commands, exit or Ctrl-D to leave\n")
+			return func(prompt string) (string, error) {
+				restore, err := rawTerminal(f)
+				if err != nil {
+					return "", err
+				}
+				line, err := editor.readLine(prompt)
+				restore()
+				editor.addHistory(line)
+				return line, err
+			}
+		}
+	}
+
+	scanner := bufio.NewScanner(shellInput)
+	return func(string) (string, error) {
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+		return scanner.Text(), nil
+	}
+}
+
+// run runs the command whose name and arguments are in words. The
+// global options can be overridden by its arguments, and are
+// restored once it ends.
+func (s *shellSession) run(words []string) {
+	saved := *s.Meta.GlobalOptions
+	defer func() {
+		*s.Meta.GlobalOptions = saved
+	}()
+
+	flags := flag.NewFlagSet(words[0], flag.ContinueOnError)
+	for _, f := range s.globals {
+		flags.Var(f.Value, f.Name, f.Usage)
+	}
+	s.Meta.FlagSet = flags
+
+	c := cli.CLI{
+		Name:       "okta-admin",
+		Commands:   s.commands,
+		Args:       words,
+		HelpWriter: s.Logger.Writer(),
+	}
+	status, err := c.Run()
+	if err != nil {
+		s.Logger.Println(err)
+	}
+	if !c.IsHelp() {
+		s.RecordAudit(c.Subcommand(), c.SubcommandArgs(), status)
+		s.RecordJournal(c.Subcommand(), c.SubcommandArgs())
+	}
+	s.EndOperation()
+	s.recordUsers(words[1:])
+	if status == ExitOK {
+		s.RecordRecentUsers(words[1:])
+	}
+}
+
+// recordUsers adds the email IDs specified as values of user flags
+// in args to the recently used ones.
+func (s *shellSession) recordUsers(args []string) {
+	s.recentUsers = prependRecent(s.recentUsers, userFlagValues(args))
+}
+
+// users returns the email IDs used in the session followed by the
+// ones used recently by commands run against the organization, as
+// saved in the completion cache.
+func (s *shellSession) users() []string {
+	res := append([]string{}, s.recentUsers...)
+	seen := map[string]bool{}
+	for _, u := range res {
+		seen[u] = true
+	}
+	for _, u := range loadCompletionCache().org(s.Meta.GlobalOptions.OrgUrl).RecentUsers {
+		if !seen[u] && len(res) < maxRecentUsers {
+			res = append(res, u)
+		}
+	}
+	return res
+}
+
+// complete returns the candidates for completing the word being
+// typed at the end of line, and the position that word starts at.
+// Each candidate replaces the whole word.
+func (s *shellSession) complete(line []rune) (int, []string) {
+	words, start, _ := shellWords(line)
+	cur := ""
+	if start < len(line) {
+		cur, words = words[len(words)-1], words[:len(words)-1]
+	}
+
+	prefix, candidates := s.completer.complete(words, cur)
+	return start, quoteShellWords(prefix, candidates)
+}
+
+// quoteShellWords quotes the words if any of them contains
+// whitespace, quotes or backslashes, and prepends the prefix to
+// them. Either every word is quoted or none is, so that they keep
+// sharing the prefix the user has typed.
+func quoteShellWords(prefix string, words []string) []string {
+	quote := false
+	for _, w := range words {
+		if strings.ContainsAny(w, " \t\"'\\") {
+			quote = true
+		}
+	}
+
+	if len(words) == 0 {
+		return nil
+	}
+	res := make([]string, len(words), len(words))
+	for i, w := range words {
+		if quote {
+			w = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(w) + `"`
+		}
+		res[i] = prefix + w
+	}
+	return res
+}
+
+// groupNames returns the names of the groups in the organization.
+// They are fetched the first time this method is called, and an
+// empty list is returned if they can't be.
+func (s *shellSession) groupNames() []string {
+	if s.groups != nil {
+		return s.groups
+	}
+
+	client, err := s.OktaClient()
+	if err != nil {
+		return nil
+	}
+	groups, err := listAllGroups(client, nil)
+	s.EndOperation()
+	if err != nil {
+		return nil
+	}
+
+	s.groups = []string{}
+	for _, g := range groups {
+		s.groups = append(s.groups, g.Profile.Name)
+	}
+	return s.groups
+}
+
+// orgHost returns the host of the organization URL, which the
+// shell's prompt shows.
+func orgHost(orgUrl string) string {
+	if u, err := url.Parse(orgUrl); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return orgUrl
+}
+
+// shellWords splits a line into words the way a POSIX shell does,
+// supporting single and double quotes and backslash escapes but no
+// expansions. It also returns the position the last word starts
+// at, which is the length of the line if it ends with whitespace,
+// and the quote left open at the end of the line, if any.
+func shellWords(line []rune) (words []string, lastStart int, openQuote rune) {
+	var (
+		word    strings.Builder
+		inWord  bool
+		escaped bool
+	)
+	lastStart = len(line)
+
+	for i, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && openQuote != '\'':
+			escaped = true
+		case openQuote != 0:
+			if r == openQuote {
+				openQuote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+			continue
+		case r == '"' || r == '\'':
+			openQuote = r
+		default:
+			word.WriteRune(r)
+		}
+
+		if !inWord {
+			inWord, lastStart = true, i
+		}
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	} else {
+		lastStart = len(line)
+	}
+	return words, lastStart, openQuote
+}
@@ -0,0 +1,215 @@
+package command
+
+import (
+	"github.com/mitchellh/cli"
+	"github.com/okta/okta-sdk-golang/okta"
+	"strings"
+	"testing"
+)
+
+func createTestShellCommand(globalOptsHelpText string) *ShellCommand {
+	return &ShellCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_shell_cmd"),
+	}
+}
+
+// testShellCommands returns the commands run by the shell in tests,
+// which share the shell's Command.
+func testShellCommands(c *Command) map[string]cli.CommandFactory {
+	commands := map[string]cli.CommandFactory{
+		"list-groups": func() (cli.Command, error) {
+			return &ListGroupsCommand{Command: c}, nil
+		},
+		"assign-groups": func() (cli.Command, error) {
+			return &AssignUserGroupsCommand{Command: c}, nil
+		},
+	}
+	commands["shell"] = func() (cli.Command, error) {
+		return &ShellCommand{Command: c, Commands: commands}, nil
+	}
+	return commands
+}
+
+func TestShellCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestShellCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestShellCommand_ParseArgs(t *testing.T) {
+	t.Parallel()
+
+	c := createTestShellCommand("")
+	if err := c.ParseArgs([]string{}); err != nil {
+		t.Errorf("Expected arguments to be valid, received %v", err)
+	}
+
+	c = createTestShellCommand("")
+	if err := c.ParseArgs([]string{"list-groups"}); err == nil {
+		t.Errorf("Expected parsing to fail with a command as argument")
+	}
+
+	c = createTestShellCommand("")
+	c.Meta.GlobalOptions.ApiToken = ""
+	if err := c.ParseArgs([]string{}); err == nil {
+		t.Errorf("Expected parsing to fail without an API token")
+	}
+
+	// Commands are read from standard input
+	c = createTestShellCommand("")
+	c.Meta.GlobalOptions.ApiTokenStdin = true
+	if err := c.ParseArgs([]string{}); err == nil {
+		t.Errorf("Expected parsing to fail with -api-token-stdin")
+	}
+}
+
+func TestShellCommand_Run(t *testing.T) {
+	client := newFakeOktaClient()
+	client.users = []*okta.User{fakeUser("00u1", "harry.potter@hogwarts.co.uk", "ACTIVE")}
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor"), fakeGroup("00g2", "Quidditch Team")}
+	c, out := createTestCommandWithClient("test_shell_cmd", client)
+	opts := c.Meta.GlobalOptions
+	c.Meta.FlagSet.StringVar(&opts.OrgUrl, "org-url", opts.OrgUrl, "")
+	c.Meta.FlagSet.StringVar(&opts.ApiToken, "api-token", opts.ApiToken, "")
+	cmd := &ShellCommand{Command: c, Commands: testShellCommands(c)}
+
+	defer func(r interface{}) { shellInput = strings.NewReader("") }(shellInput)
+	shellInput = strings.NewReader(`
+list-groups
+assign-groups -user harry.potter@hogwarts.co.uk -groups "Quidditch Team"
+assign-groups -user harry.potter@hogwarts.co.uk -groups Gryffindor -org-url https://bar.okta.com/
+help assign-groups
+shell
+list-groups -groups "Gryffindor
+exit
+list-groups
+`)
+
+	if code := cmd.Run([]string{}); code != ExitOK {
+		t.Errorf("Expected exit code 0, received %d: %s", code, out)
+	}
+
+	for _, expected := range []string{
+		"Gryffindor\nQuidditch Team\n",
+		"Added to Quidditch Team\n",
+		"Added to Gryffindor\n",
+		"Usage: okta-admin assign-groups",
+		"Failed to parse command: unterminated \" quote\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected output to contain %q, received:\n%s", expected, out)
+		}
+	}
+	if !testEq(client.members["00g2"], []string{"00u1"}) || !testEq(client.members["00g1"], []string{"00u1"}) {
+		t.Errorf("Expected user to be added to both groups, members are %v", client.members)
+	}
+	// Commands after exit aren't run and the shell can't be nested,
+	// so groups were only listed by the first three commands
+	listed := 0
+	for _, call := range client.calls {
+		if strings.HasPrefix(call, "ListGroups") {
+			listed++
+		}
+	}
+	if listed != 3 {
+		t.Errorf("Expected groups to be listed 3 times, received %d: %v", listed, client.calls)
+	}
+	if opts.OrgUrl != "https://foo.okta.com/" {
+		t.Errorf("Expected overridden org URL to be restored, received %s", opts.OrgUrl)
+	}
+}
+
+func TestShellSession_complete(t *testing.T) {
+	client := newFakeOktaClient()
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor"), fakeGroup("00g2", "Quidditch Team")}
+	c, _ := createTestCommandWithClient("test_shell_complete", client)
+	// Email IDs used by earlier commands are offered after the ones
+	// used in the session
+	c.Meta.GlobalOptions.OrgUrl = "https://shell.okta.com/"
+	c.RecordRecentUsers([]string{"-user", "ron.weasley@hogwarts.co.uk"})
+	s := newShellSession(c, testShellCommands(c))
+	s.recordUsers([]string{"-users", "harry.potter@hogwarts.co.uk,00u2", "-groups", "Tech"})
+	s.recordUsers([]string{"-email=hermione.granger@hogwarts.co.uk"})
+
+	testCases := []struct {
+		line       string
+		start      int
+		candidates []string
+	}{
+		{"", 0, []string{"assign-groups", "exit", "help", "list-groups", "quit"}},
+		{"li", 0, []string{"list-groups"}},
+		{"help a", 5, []string{"assign-groups"}},
+		{"assign-groups -gr", 14, []string{"-groups"}},
+		{"assign-groups -groups gry", 22, []string{"Gryffindor"}},
+		{"assign-groups -groups ", 22, []string{`"Gryffindor"`, `"Quidditch Team"`}},
+		{"assign-groups -groups Gryffindor,q", 22, []string{`"Gryffindor,Quidditch Team"`}},
+		{`assign-groups -groups "Quidditch T`, 22, []string{`"Quidditch Team"`}},
+		{"assign-groups -groups=Q", 14, []string{`-groups="Quidditch Team"`}},
+		{"assign-groups -user ", 20, []string{"harry.potter@hogwarts.co.uk", "hermione.granger@hogwarts.co.uk", "ron.weasley@hogwarts.co.uk"}},
+		{"assign-groups -user he", 20, []string{"hermione.granger@hogwarts.co.uk"}},
+		{"assign-groups -user harry ", 26, nil},
+		{"list-groups -detailed ", 22, nil},
+	}
+
+	for _, tc := range testCases {
+		start, candidates := s.complete([]rune(tc.line))
+		if start != tc.start || !testEq(candidates, tc.candidates) {
+			t.Errorf("Expected completions of %q to start at %d and be %q, received %d and %q", tc.line, tc.start, tc.candidates, start, candidates)
+		}
+	}
+
+	flags := s.completer.commandFlags("assign-groups")
+	for _, f := range []string{"-help", "-user", "-email", "-groups", "-parallelism"} {
+		found := false
+		for _, g := range flags {
+			found = found || f == g
+		}
+		if !found {
+			t.Errorf("Expected flags of assign-groups to contain %s, received %v", f, flags)
+		}
+	}
+
+	// Group names are fetched once
+	s.complete([]rune("list-groups -groups "))
+	if len(client.calls) != 1 {
+		t.Errorf("Expected groups to be fetched once, calls made: %v", client.calls)
+	}
+}
+
+func TestShellWords(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		line      string
+		words     []string
+		lastStart int
+		openQuote rune
+	}{
+		{"", nil, 0, 0},
+		{"list-groups  -detailed ", []string{"list-groups", "-detailed"}, 23, 0},
+		{"assign-groups -groups \"Quidditch Team\",Tech", []string{"assign-groups", "-groups", "Quidditch Team,Tech"}, 22, 0},
+		{`logs -actor 'jane doe' -since=1h`, []string{"logs", "-actor", "jane doe", "-since=1h"}, 23, 0},
+		{`create-user -fname Ron\ Bilius -team "\"Q\""`, []string{"create-user", "-fname", "Ron Bilius", "-team", `"Q"`}, 37, 0},
+		{`list-groups -groups "Quid`, []string{"list-groups", "-groups", "Quid"}, 20, '"'},
+		{`a ""`, []string{"a", ""}, 2, 0},
+	}
+
+	for _, tc := range testCases {
+		words, lastStart, openQuote := shellWords([]rune(tc.line))
+		if !testEq(words, tc.words) || lastStart != tc.lastStart || openQuote != tc.openQuote {
+			t.Errorf("Expected %q to be split into %q, %d, %q, received %q, %d, %q",
+				tc.line, tc.words, tc.lastStart, tc.openQuote, words, lastStart, openQuote)
+		}
+	}
+}
+
+func TestOrgHost(t *testing.T) {
+	t.Parallel()
+
+	if h := orgHost("https://hogwarts.okta.com/"); h != "hogwarts.okta.com" {
+		t.Errorf("Expected host hogwarts.okta.com, received %s", h)
+	}
+	if h := orgHost("hogwarts"); h != "hogwarts" {
+		t.Errorf("Expected host hogwarts, received %s", h)
+	}
+}
@@ -0,0 +1,84 @@
+package command
+
+type ShowGroupRuleCommand struct {
+	*Command
+}
+
+type ShowGroupRuleCommandConfig struct {
+	RuleID, RuleName string
+}
+
+func (c *ShowGroupRuleCommand) Synopsis() string {
+	return "Show details of a group rule"
+}
+
+func (c *ShowGroupRuleCommand) Help() string {
+	helpText := `
+Usage: okta-admin show-group-rule [options]
+
+  Displays a group rule, including its expression, the groups
+  it assigns users to and the users excluded from it.
+  Either the ID or the name of the rule must be specified.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -id   ID of the group rule
+  -name Name of the group rule
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *ShowGroupRuleCommand) ParseArgs(args []string) (*ShowGroupRuleCommandConfig, error) {
+	var cfg ShowGroupRuleCommandConfig
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.RuleID, "id", "", "")
+	flags.StringVar(&cfg.RuleName, "name", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+	if err := validateGroupRuleIdentifier(cfg.RuleID, cfg.RuleName); err != nil {
+		return &cfg, err
+	}
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+	)
+	return &cfg, err
+}
+
+func (c *ShowGroupRuleCommand) Run(args []string) int {
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+
+	rule, err := findGroupRule(client, cfg.RuleID, cfg.RuleName)
+	if err != nil {
+		c.logError("Failed to fetch group rule", err)
+		return exitCode(err)
+	}
+	groups, err := listAllGroups(client, nil)
+	if err != nil {
+		c.logError("Failed to fetch groups list", err)
+		return exitCode(err)
+	}
+
+	c.Logger.Println(getGroupRuleDetailsPretty(rule, groups))
+	return 0
+}
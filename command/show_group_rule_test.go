@@ -0,0 +1,105 @@
+package command
+
+import (
+	"github.com/okta/okta-sdk-golang/okta"
+	"testing"
+)
+
+func createTestShowGroupRuleCommand(globalOptsHelpText string) *ShowGroupRuleCommand {
+	return &ShowGroupRuleCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_show_group_rule_cmd"),
+	}
+}
+
+func TestShowGroupRuleCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestShowGroupRuleCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestShowGroupRuleCommand_ParseArgs(t *testing.T) {
+	t.Run("with name", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestShowGroupRuleCommand("")
+		args := []string{"-name", "Platform engineers"}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.RuleName != args[1] {
+			t.Errorf("Expected rule name to be %s, received %s", args[1], cfg.RuleName)
+		}
+	})
+
+	t.Run("with id", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestShowGroupRuleCommand("")
+		args := []string{"-id", "0pr3f7zMZZHPgUoWO0g4"}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.RuleID != args[1] {
+			t.Errorf("Expected rule id to be %s, received %s", args[1], cfg.RuleID)
+		}
+	})
+
+	t.Run("without or with both identifiers", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := [][]string{
+			{},
+			{"-id", "0pr3f7zMZZHPgUoWO0g4", "-name", "Platform engineers"},
+		}
+		for _, tc := range testCases {
+			c := createTestShowGroupRuleCommand("")
+			if _, err := c.ParseArgs(tc); err == nil {
+				t.Errorf("Expected args %v to be invalid", tc)
+			}
+		}
+	})
+}
+
+func TestShowGroupRuleCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor")}
+	client.rules = []*okta.GroupRule{
+		fakeGroupRule("0pr1", "Seekers", groupRuleStatusActive, `user.title == "Seeker"`, "00g1"),
+	}
+	expected := `Name:   Seekers
+ID:     0pr1
+Status: ACTIVE
+
+Expression
+  user.title == "Seeker"
+
+Assigns users to
+  Gryffindor (00g1)
+
+Excluded users
+  [None]
+`
+
+	for _, args := range [][]string{{"-name", "Seekers"}, {"-id", "0pr1"}} {
+		c, out := createTestCommandWithClient("test_show_group_rule_cmd", client)
+		if code := (&ShowGroupRuleCommand{Command: c}).Run(args); code != ExitOK {
+			t.Fatalf("Expected exit code %d for %q, received %d: %s", ExitOK, args, code, out)
+		}
+		if out.String() != expected {
+			t.Errorf("Expected output %q for %q, received %q", expected, args, out)
+		}
+	}
+
+	c, out := createTestCommandWithClient("test_show_group_rule_cmd", client)
+	if code := (&ShowGroupRuleCommand{Command: c}).Run([]string{"-name", "Chasers"}); code != ExitNotFound {
+		t.Errorf("Expected exit code %d for a missing rule, received %d: %s", ExitNotFound, code, out)
+	}
+}
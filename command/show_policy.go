@@ -0,0 +1,116 @@
+package command
+
+import (
+	"errors"
+)
+
+type ShowPolicyCommand struct {
+	*Command
+}
+
+type ShowPolicyCommandConfig struct {
+	PolicyID, PolicyName string
+	Type                 string
+	Format               string
+}
+
+func (c *ShowPolicyCommand) Synopsis() string {
+	return "Show details of a policy and its rules"
+}
+
+func (c *ShowPolicyCommand) Help() string {
+	helpText := `
+Usage: okta-admin show-policy [options]
+
+  Displays a sign-on, password or MFA enrollment policy and its
+  rules. Groups the policy and rules apply to are listed by name.
+  For password policies, the complexity, age and lockout settings
+  are displayed as well.
+
+  Either the ID or the name of the policy must be specified.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -id     ID of the policy
+  -name   Name of the policy
+  -type   Type of the policy, one of OKTA_SIGN_ON, PASSWORD or
+          MFA_ENROLL. Narrows down the search for a policy by name.
+  -format Output format, either text or json. The json format
+          contains the policy and its rules exactly as returned by
+          Okta. (Default: text)
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *ShowPolicyCommand) ParseArgs(args []string) (*ShowPolicyCommandConfig, error) {
+	var cfg ShowPolicyCommandConfig
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.PolicyID, "id", "", "")
+	flags.StringVar(&cfg.PolicyName, "name", "", "")
+	flags.StringVar(&cfg.Type, "type", "", "")
+	flags.StringVar(&cfg.Format, "format", policyFormatText, "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+	if (cfg.PolicyID == "") == (cfg.PolicyName == "") {
+		return &cfg, errors.New("either id or name of the policy is required")
+	}
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "format", Required: true, Value: cfg.Format, ValidationFunc: ValidateOneOf(policyFormatText, reportFormatJSON)},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+		&parameter{Name: "type", Required: false, Value: cfg.Type, ValidationFunc: ValidateOneOf(policyTypes...)},
+	)
+	return &cfg, err
+}
+
+func (c *ShowPolicyCommand) Run(args []string) int {
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+
+	policy, err := findPolicy(client, cfg.PolicyID, cfg.PolicyName, cfg.Type)
+	if err != nil {
+		c.logError("Failed to fetch policy", err)
+		return exitCode(err)
+	}
+	rules, _, err := client.ListPolicyRules(policy.Id)
+	if err != nil {
+		c.logError("Failed to fetch policy rules", err)
+		return exitCode(err)
+	}
+
+	if cfg.Format == reportFormatJSON {
+		if err := writePolicyJSON(c.Logger.Writer(), policy, rules); err != nil {
+			c.logError("Failed to write policy", err)
+			return exitCode(err)
+		}
+		return 0
+	}
+
+	groups, err := listAllGroups(client, nil)
+	if err != nil {
+		c.logError("Failed to fetch groups list", err)
+		return exitCode(err)
+	}
+
+	c.Logger.Println(getPolicyDetailsPretty(policy, rules, groups))
+	return 0
+}
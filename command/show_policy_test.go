@@ -0,0 +1,123 @@
+package command
+
+import (
+	"encoding/json"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"strings"
+	"testing"
+)
+
+func createTestShowPolicyCommand(globalOptsHelpText string) *ShowPolicyCommand {
+	return &ShowPolicyCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_show_policy_cmd"),
+	}
+}
+
+func TestShowPolicyCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestShowPolicyCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestShowPolicyCommand_ParseArgs(t *testing.T) {
+	t.Run("with name and type", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestShowPolicyCommand("")
+		args := []string{"-name", "Default Policy", "-type", policyTypePassword, "-format", "json"}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.PolicyName != args[1] {
+			t.Errorf("Expected policy name to be %s, received %s", args[1], cfg.PolicyName)
+		}
+		if cfg.Type != args[3] {
+			t.Errorf("Expected type to be %s, received %s", args[3], cfg.Type)
+		}
+		if cfg.Format != args[5] {
+			t.Errorf("Expected format to be %s, received %s", args[5], cfg.Format)
+		}
+	})
+
+	t.Run("with id", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestShowPolicyCommand("")
+		args := []string{"-id", "00p1emaKYZTWRYYRRTSK"}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.PolicyID != args[1] {
+			t.Errorf("Expected policy id to be %s, received %s", args[1], cfg.PolicyID)
+		}
+	})
+
+	t.Run("with invalid arguments", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := [][]string{
+			{},
+			{"-id", "00p1emaKYZTWRYYRRTSK", "-name", "Default Policy"},
+			{"-name", "Default Policy", "-type", "IDP_DISCOVERY"},
+		}
+		for _, tc := range testCases {
+			c := createTestShowPolicyCommand("")
+			if _, err := c.ParseArgs(tc); err == nil {
+				t.Errorf("Expected args %v to be invalid", tc)
+			}
+		}
+	})
+}
+
+func TestShowPolicyCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.policies = []*oktaapi.Policy{
+		fakePolicy("00p1", "Staff", policyTypePassword, "Employees"),
+		fakePolicy("00p2", "Staff", policyTypeSignOn, ""),
+	}
+	client.policyRules["00p1"] = []*oktaapi.PolicyRule{fakePolicyRule("0pr1", "Default rule", 1)}
+	run := func(args ...string) (int, string) {
+		c, out := createTestCommandWithClient("test_show_policy_cmd", client)
+		return (&ShowPolicyCommand{Command: c}).Run(args), out.String()
+	}
+
+	for _, args := range [][]string{{"-id", "00p1"}, {"-name", "Staff", "-type", policyTypePassword}} {
+		code, out := run(args...)
+		if code != ExitOK {
+			t.Fatalf("Expected exit code %d for %q, received %d: %s", ExitOK, args, code, out)
+		}
+		for _, expected := range []string{"ID:          00p1", "Description: Employees", "Default rule"} {
+			if !strings.Contains(out, expected) {
+				t.Errorf("Expected output for %q to contain %q, received %q", args, expected, out)
+			}
+		}
+	}
+
+	code, out := run("-id", "00p1", "-format", "json")
+	var res struct {
+		Policy map[string]interface{}   `json:"policy"`
+		Rules  []map[string]interface{} `json:"rules"`
+	}
+	if code != ExitOK {
+		t.Fatalf("Expected exit code %d, received %d: %s", ExitOK, code, out)
+	}
+	if err := json.Unmarshal([]byte(out), &res); err != nil || res.Policy["id"] != "00p1" || len(res.Rules) != 1 {
+		t.Errorf("Expected the raw policy and its rules, received %s (error %v)", out, err)
+	}
+
+	// Policies of different types share the name
+	if code, out := run("-name", "Staff"); code != ExitFailure || !strings.Contains(out, "specify the policy ID or type") {
+		t.Errorf("Expected the ambiguous name to be reported, received %d: %s", code, out)
+	}
+	if code, out := run("-name", "Contractors"); code != ExitNotFound {
+		t.Errorf("Expected exit code %d for a missing policy, received %d: %s", ExitNotFound, code, out)
+	}
+}
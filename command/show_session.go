@@ -0,0 +1,73 @@
+package command
+
+type ShowSessionCommand struct {
+	*Command
+}
+
+type ShowSessionCommandConfig struct {
+	SessionID string
+}
+
+func (c *ShowSessionCommand) Synopsis() string {
+	return "Show details of a session"
+}
+
+func (c *ShowSessionCommand) Help() string {
+	helpText := `
+Usage: okta-admin show-session [options]
+
+  Displays a session, including the user it belongs to, when it
+  expires and how the user authenticated.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -id ID of the session
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *ShowSessionCommand) ParseArgs(args []string) (*ShowSessionCommandConfig, error) {
+	var cfg ShowSessionCommandConfig
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.SessionID, "id", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+	err := c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "id", Required: true, Value: cfg.SessionID},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+	)
+	return &cfg, err
+}
+
+func (c *ShowSessionCommand) Run(args []string) int {
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+
+	session, _, err := client.GetSession(cfg.SessionID)
+	if err != nil {
+		c.logError("Failed to fetch session", err)
+		return exitCode(err)
+	}
+
+	c.Logger.Println(getSessionDetailsPretty(session))
+	return 0
+}
@@ -0,0 +1,59 @@
+package command
+
+import (
+	"testing"
+)
+
+func createTestShowSessionCommand(globalOptsHelpText string) *ShowSessionCommand {
+	return &ShowSessionCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_show_session_cmd"),
+	}
+}
+
+func TestShowSessionCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestShowSessionCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestShowSessionCommand_ParseArgs(t *testing.T) {
+	t.Run("with id", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestShowSessionCommand("")
+		args := []string{"-id", "102SmBFaL6hQ1aFSI0xmo1Ouw"}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.SessionID != args[1] {
+			t.Errorf("Expected session id to be %s, received %s", args[1], cfg.SessionID)
+		}
+	})
+
+	t.Run("without id", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestShowSessionCommand("")
+		if _, err := c.ParseArgs([]string{}); err == nil {
+			t.Errorf("Expected error when session id is not specified")
+		}
+	})
+}
+
+func TestShowSessionCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	c, out := createTestCommandWithClient("test_show_session_cmd", client)
+	cmd := &ShowSessionCommand{Command: c}
+
+	if code := cmd.Run([]string{"-id", "102abc"}); code != 1 {
+		t.Errorf("Expected exit code 1 for a session that doesn't exist, received %d", code)
+	}
+	if out.String() != "Failed to fetch session: failed to fetch session (404 Not Found)\n" {
+		t.Errorf("Unexpected output %q", out)
+	}
+}
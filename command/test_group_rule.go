@@ -0,0 +1,278 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"net/http"
+	"sort"
+)
+
+type TestGroupRuleCommand struct {
+	*Command
+}
+
+type TestGroupRuleCommandConfig struct {
+	Expression       string
+	GroupNames       []string
+	RuleID, RuleName string
+}
+
+func (c *TestGroupRuleCommand) Synopsis() string {
+	return "Preview the users a group rule would match"
+}
+
+func (c *TestGroupRuleCommand) Help() string {
+	helpText := `
+Usage: okta-admin test-group-rule [options]
+
+  Evaluates a group rule expression locally against all users in
+  the organization, without making any changes. It prints the
+  users the expression matches and, for each target group, the
+  users the rule would add to it and the existing members who
+  don't match the expression. Okta removes such members from the
+  group if the rule assigned them, while those assigned manually
+  stay, which this command can't tell apart.
+
+  The expression can either be supplied via -expression, or be
+  read from an existing rule identified by -id or -name, in which
+  case the users the rule excludes aren't added either.
+
+  Only a subset of the Okta Expression Language is supported:
+  comparisons of user attributes (==, !=), String.startsWith,
+  String.stringContains, isMemberOfAnyGroup, boolean operators
+  (&&, ||, AND, OR, !) and parentheses. AND and OR can be written
+  in any case, eg- and.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -expression Okta Expression Language expression to evaluate
+  -groups     Comma-separated list of names or IDs of groups the
+              rule would assign matching users to
+  -id         ID of an existing group rule to evaluate
+  -name       Name of an existing group rule to evaluate
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *TestGroupRuleCommand) ParseArgs(args []string) (*TestGroupRuleCommandConfig, error) {
+	var cfg TestGroupRuleCommandConfig
+	var groupNames string
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.Expression, "expression", "", "")
+	flags.StringVar(&groupNames, "groups", "", "")
+	flags.StringVar(&cfg.RuleID, "id", "", "")
+	flags.StringVar(&cfg.RuleName, "name", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+	cfg.GroupNames = c.parseListOfValues(groupNames, ParamListSep)
+
+	if cfg.Expression != "" {
+		if cfg.RuleID != "" || cfg.RuleName != "" {
+			return &cfg, errors.New("expression cannot be combined with id or name")
+		}
+		if _, err := parseGroupRuleExpression(cfg.Expression); err != nil {
+			return &cfg, errors.New("invalid expression: " + err.Error())
+		}
+	} else {
+		if len(cfg.GroupNames) > 0 {
+			return &cfg, errors.New("groups can only be combined with expression")
+		}
+		if err := validateGroupRuleIdentifier(cfg.RuleID, cfg.RuleName); err != nil {
+			return &cfg, errors.New("either expression, or id or name of the rule is required")
+		}
+	}
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+	)
+	return &cfg, err
+}
+
+func (c *TestGroupRuleCommand) Run(args []string) int {
+	var (
+		expr      string
+		targetIDs []string
+		matched   = map[string]bool{}
+		excluded  = map[string]bool{}
+		logins    = map[string]string{}
+		names     = map[string]string{}
+	)
+
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+
+	if cfg.Expression != "" {
+		expr = cfg.Expression
+		for _, n := range cfg.GroupNames {
+			g, err := resolveGroup(client, n)
+			var nfErr *notFoundError
+			switch {
+			case errors.As(err, &nfErr):
+				c.Logger.Println(err)
+				return ExitNotFound
+			case err != nil:
+				c.logError(fmt.Sprintf("Failed to find group %s", n), err)
+				return exitCode(err)
+			case names[g.Id] == "":
+				// A group may be specified both by name and ID
+				names[g.Id] = g.Profile.Name
+				targetIDs = append(targetIDs, g.Id)
+			}
+		}
+	} else {
+		rule, err := findGroupRule(client, cfg.RuleID, cfg.RuleName)
+		if err != nil {
+			c.logError("Failed to fetch group rule", err)
+			return exitCode(err)
+		}
+		if rule.Conditions != nil && rule.Conditions.Expression != nil {
+			expr = rule.Conditions.Expression.Value
+		}
+		if rule.Actions != nil && rule.Actions.AssignUserToGroups != nil {
+			targetIDs = rule.Actions.AssignUserToGroups.GroupIds
+		}
+		for _, gid := range targetIDs {
+			// A group deleted since the rule was created is shown by ID
+			g, resp, err := client.GetGroup(gid, nil)
+			switch {
+			case resp != nil && resp.StatusCode == http.StatusNotFound:
+				names[gid] = gid
+			case err != nil:
+				c.logError(fmt.Sprintf("Failed to find group %s", gid), err)
+				return exitCode(err)
+			default:
+				names[gid] = g.Profile.Name
+			}
+		}
+		if rule.Conditions != nil && rule.Conditions.People != nil && rule.Conditions.People.Users != nil {
+			for _, uid := range rule.Conditions.People.Users.Exclude {
+				excluded[uid] = true
+			}
+		}
+	}
+
+	parsed, err := parseGroupRuleExpression(expr)
+	if err != nil {
+		c.logError("Failed to parse expression", err)
+		return exitCode(err)
+	}
+
+	// Fetch members of every group whose membership is either
+	// checked by the expression or compared against the matches.
+	members := map[string]map[string]bool{}
+	gids := append(append([]string{}, parsed.GroupIDs()...), targetIDs...)
+	for _, gid := range gids {
+		if _, ok := members[gid]; ok {
+			continue
+		}
+		users, err := listAllGroupUsers(client, gid)
+		if err != nil {
+			c.logError(fmt.Sprintf("Failed to fetch members of group %s", gid), err)
+			return exitCode(err)
+		}
+		members[gid] = map[string]bool{}
+		for _, u := range users {
+			members[gid][u.Id] = true
+			logins[u.Id] = userLogin(u)
+		}
+	}
+
+	users, err := listAllUsers(client, nil)
+	if err != nil {
+		c.logError("Failed to fetch list of users", err)
+		return exitCode(err)
+	}
+
+	for _, u := range users {
+		logins[u.Id] = userLogin(u)
+
+		memberOf := map[string]bool{}
+		for _, gid := range parsed.GroupIDs() {
+			memberOf[gid] = members[gid][u.Id]
+		}
+		ok, err := parsed.Matches(oktaapi.ProfileOf(u), memberOf)
+		if err != nil {
+			c.Logger.Printf("Failed to evaluate expression for %s: %v\n", logins[u.Id], err)
+			return ExitFailure
+		}
+		if ok {
+			matched[u.Id] = true
+		}
+	}
+
+	// Users excluded by the rule aren't assigned even if they match
+	skipped := map[string]bool{}
+	for uid := range excluded {
+		if matched[uid] {
+			skipped[uid] = true
+			delete(matched, uid)
+		}
+	}
+
+	c.Logger.Printf("Matched %d of %d users\n", len(matched), len(users))
+	for _, l := range sortedLogins(matched, logins) {
+		c.Logger.Printf("  %s\n", l)
+	}
+	if len(skipped) > 0 {
+		c.Logger.Printf("%d matching user(s) are excluded by the rule\n", len(skipped))
+		for _, l := range sortedLogins(skipped, logins) {
+			c.Logger.Printf("  - %s\n", l)
+		}
+	}
+
+	for _, gid := range targetIDs {
+		added, unmatched := map[string]bool{}, map[string]bool{}
+		for uid := range matched {
+			if !members[gid][uid] {
+				added[uid] = true
+			}
+		}
+		for uid := range members[gid] {
+			if !matched[uid] {
+				unmatched[uid] = true
+			}
+		}
+
+		c.Logger.Printf("\n%s: %d user(s) would be added\n", names[gid], len(added))
+		for _, l := range sortedLogins(added, logins) {
+			c.Logger.Printf("  + %s\n", l)
+		}
+		c.Logger.Printf("%s: %d existing member(s) don't match the rule; Okta removes them if the rule assigned them, only manually assigned members stay\n", names[gid], len(unmatched))
+		for _, l := range sortedLogins(unmatched, logins) {
+			c.Logger.Printf("  ? %s\n", l)
+		}
+	}
+
+	return ExitOK
+}
+
+// sortedLogins returns the logins of the specified users in
+// alphabetical order.
+func sortedLogins(uids map[string]bool, logins map[string]string) []string {
+	res := make([]string, 0, len(uids))
+	for uid := range uids {
+		res = append(res, logins[uid])
+	}
+	sort.Strings(res)
+	return res
+}
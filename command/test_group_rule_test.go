@@ -0,0 +1,158 @@
+package command
+
+import (
+	"github.com/okta/okta-sdk-golang/okta"
+	"testing"
+)
+
+func createTestTestGroupRuleCommand(globalOptsHelpText string) *TestGroupRuleCommand {
+	return &TestGroupRuleCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_test_group_rule_cmd"),
+	}
+}
+
+func TestTestGroupRuleCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestTestGroupRuleCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestTestGroupRuleCommand_ParseArgs(t *testing.T) {
+	t.Run("with expression", func(t *testing.T) {
+		t.Parallel()
+
+		var groups = []string{"Platform", "engineering"}
+		c := createTestTestGroupRuleCommand("")
+		args := []string{
+			"-expression", `user.team=="Platform" && String.startsWith(user.login, "a")`,
+			"-groups", "Platform,engineering",
+		}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.Expression != args[1] {
+			t.Errorf("Expected expression to be %s, received %s", args[1], cfg.Expression)
+		}
+		if !testEq(cfg.GroupNames, groups) {
+			t.Errorf("Expected group names to be %v, received %v", groups, cfg.GroupNames)
+		}
+	})
+
+	t.Run("with rule", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestTestGroupRuleCommand("")
+		args := []string{"-name", "Platform engineers"}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+		if cfg.RuleName != args[1] {
+			t.Errorf("Expected rule name to be %s, received %s", args[1], cfg.RuleName)
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := [][]string{
+			{},
+			{"-expression", `user.team=="Platform"`, "-name", "Platform engineers"},
+			{"-name", "Platform engineers", "-groups", "Platform"},
+			{"-expression", `user.team=`},
+		}
+		for _, tc := range testCases {
+			c := createTestTestGroupRuleCommand("")
+			if _, err := c.ParseArgs(tc); err == nil {
+				t.Errorf("Expected args %v to be invalid", tc)
+			}
+		}
+	})
+}
+
+func TestTestGroupRuleCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	harry := fakeUser("00u1", "harry.potter@hogwarts.co.uk", "ACTIVE")
+	ron := fakeUser("00u2", "ron.weasley@hogwarts.co.uk", "ACTIVE")
+	hermione := fakeUser("00u3", "hermione.granger@hogwarts.co.uk", "ACTIVE")
+	(*harry.Profile)["house"] = "Gryffindor"
+	(*ron.Profile)["house"] = "Hufflepuff"
+	(*hermione.Profile)["house"] = "Gryffindor"
+
+	client := newFakeOktaClient()
+	client.users = []*okta.User{harry, ron, hermione}
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor")}
+	client.members["00g1"] = []string{"00u2", "00u3"}
+	client.rules = []*okta.GroupRule{
+		fakeGroupRule("0pr1", "Gryffindors", groupRuleStatusActive, `user.house == "Gryffindor" or user.house == "Ravenclaw"`, "00g1"),
+	}
+	expected := `Matched 2 of 3 users
+  harry.potter@hogwarts.co.uk
+  hermione.granger@hogwarts.co.uk
+
+Gryffindor: 1 user(s) would be added
+  + harry.potter@hogwarts.co.uk
+Gryffindor: 1 existing member(s) don't match the rule; Okta removes them if the rule assigned them, only manually assigned members stay
+  ? ron.weasley@hogwarts.co.uk
+`
+
+	for _, args := range [][]string{
+		{"-expression", `user.house == "Gryffindor" OR user.house == "Ravenclaw"`, "-groups", "Gryffindor"},
+		{"-name", "Gryffindors"},
+	} {
+		c, out := createTestCommandWithClient("test_test_group_rule_cmd", client)
+		if code := (&TestGroupRuleCommand{Command: c}).Run(args); code != ExitOK {
+			t.Fatalf("Expected exit code %d for %q, received %d: %s", ExitOK, args, code, out)
+		}
+		if out.String() != expected {
+			t.Errorf("Expected output %q for %q, received %q", expected, args, out)
+		}
+	}
+
+	c, out := createTestCommandWithClient("test_test_group_rule_cmd", client)
+	code := (&TestGroupRuleCommand{Command: c}).Run([]string{"-expression", `user.house == "Slytherin"`, "-groups", "Slytherin"})
+	if code != ExitNotFound {
+		t.Errorf("Expected exit code %d for a missing group, received %d: %s", ExitNotFound, code, out)
+	}
+}
+
+func TestTestGroupRuleCommand_RunExcluded(t *testing.T) {
+	t.Parallel()
+
+	harry := fakeUser("00u1", "harry.potter@hogwarts.co.uk", "ACTIVE")
+	hermione := fakeUser("00u3", "hermione.granger@hogwarts.co.uk", "ACTIVE")
+	(*harry.Profile)["house"] = "Gryffindor"
+	(*hermione.Profile)["house"] = "Gryffindor"
+
+	rule := fakeGroupRule("0pr1", "Gryffindors", groupRuleStatusActive, `user.house == "Gryffindor"`, "00g1")
+	rule.Conditions.People = &okta.GroupRulePeopleCondition{
+		Users: &okta.GroupRuleUserCondition{Exclude: []string{"00u1"}},
+	}
+
+	client := newFakeOktaClient()
+	client.users = []*okta.User{harry, hermione}
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor")}
+	client.rules = []*okta.GroupRule{rule}
+	expected := `Matched 1 of 2 users
+  hermione.granger@hogwarts.co.uk
+1 matching user(s) are excluded by the rule
+  - harry.potter@hogwarts.co.uk
+
+Gryffindor: 1 user(s) would be added
+  + hermione.granger@hogwarts.co.uk
+Gryffindor: 0 existing member(s) don't match the rule; Okta removes them if the rule assigned them, only manually assigned members stay
+`
+
+	c, out := createTestCommandWithClient("test_test_group_rule_cmd", client)
+	if code := (&TestGroupRuleCommand{Command: c}).Run([]string{"-id", "0pr1"}); code != ExitOK {
+		t.Fatalf("Expected exit code %d, received %d: %s", ExitOK, code, out)
+	}
+	if out.String() != expected {
+		t.Errorf("Expected output %q, received %q", expected, out)
+	}
+}
@@ -0,0 +1,202 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/duaraghav8/okta-admin/tokenstore"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TokenFileEnv is the environment variable which overrides the path
+// of the file API tokens are encrypted in.
+const TokenFileEnv = "OKTA_ADMIN_TOKEN_FILE"
+
+// PassphraseEnv is the environment variable which contains the
+// passphrase of the token file, so that it isn't prompted for.
+const PassphraseEnv = "OKTA_ADMIN_PASSPHRASE"
+
+// tokenFileName is the name of the token file in the okta-admin
+// directory of the user's configuration directory.
+const tokenFileName = "tokens.enc"
+
+// maxApiTokenLength is the maximum length of the input API tokens
+// are read from.
+const maxApiTokenLength = 4096
+
+// apiTokenInput is the standard input -api-token-stdin reads the
+// API token from.
+var apiTokenInput io.Reader = os.Stdin
+
+// keyringStore is a token store which may not be available, like
+// the system keyring.
+type keyringStore interface {
+	tokenstore.Store
+	Available() bool
+}
+
+// systemKeyring is the keyring API tokens are stored in, which
+// tests replace.
+var systemKeyring keyringStore = &tokenstore.Keyring{}
+
+// tokenFileIterations is the number of PBKDF2 iterations deriving
+// the key of the token file when it's written.
+var tokenFileIterations = tokenstore.DefaultIterations
+
+// newFileStore returns the store of the token file, which is
+// specified by the OKTA_ADMIN_TOKEN_FILE environment variable, or is
+// tokens.enc in the okta-admin directory of the user's configuration
+// directory.
+func newFileStore() (*tokenstore.File, error) {
+	path := os.Getenv(TokenFileEnv)
+	if path == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(dir, "okta-admin", tokenFileName)
+	}
+	return &tokenstore.File{
+		Path:       path,
+		Iterations: tokenFileIterations,
+		Passphrase: func(confirm bool) (string, error) {
+			return tokenPassphrase(path, confirm)
+		},
+	}, nil
+}
+
+// tokenPassphrase returns the passphrase of the token file at path,
+// from the OKTA_ADMIN_PASSPHRASE environment variable or prompted
+// for, twice if confirm is set.
+func tokenPassphrase(path string, confirm bool) (string, error) {
+	if p := os.Getenv(PassphraseEnv); p != "" {
+		return p, nil
+	}
+	p, err := readSecret(fmt.Sprintf("Passphrase of %s: ", path))
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("failed to read the passphrase, which %s can contain instead: %v", PassphraseEnv, err))
+	}
+	if p == "" {
+		return "", errors.New("the passphrase cannot be empty")
+	}
+	if confirm {
+		again, err := readSecret("Repeat the passphrase: ")
+		if err != nil {
+			return "", errors.New(fmt.Sprintf("failed to read the passphrase: %v", err))
+		}
+		if again != p {
+			return "", errors.New("the passphrases don't match")
+		}
+	}
+	return p, nil
+}
+
+// StoredApiToken returns the API token stored for the organization
+// by the login command, or an empty string if there's none. It is
+// looked up in the system keyring first, if it's available, then
+// in the encrypted file, whose passphrase may be prompted for.
+func StoredApiToken(orgUrl string) (string, error) {
+	var keyringErr error
+	if systemKeyring.Available() {
+		token, err := systemKeyring.Get(orgUrl)
+		if err == nil {
+			return token, nil
+		}
+		if err != tokenstore.ErrNotStored {
+			keyringErr = err
+		}
+	}
+
+	f, err := newFileStore()
+	if err != nil {
+		return "", err
+	}
+	token, err := f.Get(orgUrl)
+	switch {
+	case err == tokenstore.ErrNotStored && keyringErr != nil:
+		return "", keyringErr
+	case err == tokenstore.ErrNotStored:
+		return "", nil
+	}
+	return token, err
+}
+
+// useStoredApiToken sets the API token to the one stored for the
+// organization, if the metadata can look it up.
+func (c *Command) useStoredApiToken() error {
+	opts := c.Meta.GlobalOptions
+	if opts.ApiTokenLookup == nil || opts.OrgUrl == "" || opts.ApiToken != "" {
+		return nil
+	}
+	token, err := opts.ApiTokenLookup(opts.OrgUrl)
+	if err != nil {
+		return errors.New(fmt.Sprintf("failed to read the API token stored for %s: %v", opts.OrgUrl, err))
+	}
+	opts.ApiToken = token
+	return nil
+}
+
+// readApiTokenStdin sets the API token to the one on the first line
+// of standard input, if -api-token-stdin was specified. The rest of
+// the input isn't read.
+func (c *Command) readApiTokenStdin() error {
+	opts := c.Meta.GlobalOptions
+	if !opts.ApiTokenStdin {
+		return nil
+	}
+	token, err := readApiTokenLine(apiTokenInput)
+	if err != nil {
+		return errors.New(fmt.Sprintf("failed to read the API token from standard input: %v", err))
+	}
+	opts.ApiToken, opts.ApiTokenStdin = token, false
+	return nil
+}
+
+// readApiTokenLine reads an API token from the first line of r. It
+// reads a byte at a time, so that nothing after the line is
+// consumed, eg- answers to confirmations.
+func readApiTokenLine(r io.Reader) (string, error) {
+	var line []byte
+	b := make([]byte, 1)
+	for len(line) <= maxApiTokenLength {
+		n, err := r.Read(b)
+		if n == 1 && b[0] == '\n' {
+			break
+		}
+		if n == 1 {
+			line = append(line, b[0])
+			continue
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return ReadApiToken(bytes.NewReader(line))
+}
+
+// ReadApiToken reads an API token from r, eg- a file. Whitespace
+// around it, like the trailing newline, is ignored.
+func ReadApiToken(r io.Reader) (string, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxApiTokenLength+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxApiTokenLength {
+		return "", errors.New("the API token is too long")
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", errors.New("no API token was found")
+	}
+	if strings.ContainsAny(token, " \t\r\n") {
+		return "", errors.New("the API token cannot contain whitespace")
+	}
+	return token, nil
+}
@@ -0,0 +1,110 @@
+package command
+
+import (
+	"errors"
+	"github.com/duaraghav8/okta-admin/tokenstore"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeKeyring is an in-memory keyring, which is only available if
+// available is set.
+type fakeKeyring struct {
+	mu        sync.Mutex
+	available bool
+	tokens    map[string]string
+}
+
+func (k *fakeKeyring) String() string {
+	return "the fake keyring"
+}
+
+func (k *fakeKeyring) Available() bool {
+	return k.available
+}
+
+func (k *fakeKeyring) Get(orgUrl string) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if !k.available {
+		return "", errors.New("the keyring isn't available")
+	}
+	token, ok := k.tokens[tokenstore.NormalizeOrgUrl(orgUrl)]
+	if !ok {
+		return "", tokenstore.ErrNotStored
+	}
+	return token, nil
+}
+
+func (k *fakeKeyring) Set(orgUrl, token string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if !k.available {
+		return errors.New("the keyring isn't available")
+	}
+	if k.tokens == nil {
+		k.tokens = map[string]string{}
+	}
+	k.tokens[tokenstore.NormalizeOrgUrl(orgUrl)] = token
+	return nil
+}
+
+func (k *fakeKeyring) Delete(orgUrl string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if !k.available {
+		return errors.New("the keyring isn't available")
+	}
+	if _, ok := k.tokens[tokenstore.NormalizeOrgUrl(orgUrl)]; !ok {
+		return tokenstore.ErrNotStored
+	}
+	delete(k.tokens, tokenstore.NormalizeOrgUrl(orgUrl))
+	return nil
+}
+
+func TestCommand_readApiTokenStdin(t *testing.T) {
+	defer func(r io.Reader) { apiTokenInput = r }(apiTokenInput)
+	input := strings.NewReader("00abc-XYZ\nyes\n")
+	apiTokenInput = input
+
+	c := createTestCommand("", "test_api_token_stdin")
+	opts := c.Meta.GlobalOptions
+	opts.ApiToken, opts.ApiTokenStdin = "from-the-environment", true
+	p := &parameter{Name: "api-token", Required: true, Value: opts.ApiToken}
+	if err := c.validateParameters(p); err != nil {
+		t.Fatalf("Expected the token to be read, received %v", err)
+	}
+	if p.Value != "00abc-XYZ" || opts.ApiToken != "00abc-XYZ" {
+		t.Errorf("Expected the token from standard input to be used, received %q", opts.ApiToken)
+	}
+
+	// Only the first line is read, and only once
+	if err := c.validateParameters(&parameter{Name: "api-token", Required: true, Value: opts.ApiToken}); err != nil {
+		t.Errorf("Expected the token to be read once, received %v", err)
+	}
+	if rest, _ := ioutil.ReadAll(input); string(rest) != "yes\n" {
+		t.Errorf("Expected the rest of the input to be left unread, %q is left", rest)
+	}
+
+	apiTokenInput = strings.NewReader("")
+	opts.ApiToken, opts.ApiTokenStdin = "", true
+	if err := c.validateParameters(&parameter{Name: "api-token", Required: true}); err == nil || !strings.Contains(err.Error(), "standard input") {
+		t.Errorf("Expected empty standard input to be reported, received %v", err)
+	}
+}
+
+func TestReadApiToken(t *testing.T) {
+	t.Parallel()
+
+	if token, err := ReadApiToken(strings.NewReader("  00abc-XYZ\n")); err != nil || token != "00abc-XYZ" {
+		t.Errorf("Expected the token to be read, received %q (error %v)", token, err)
+	}
+	for _, input := range []string{"", "\n", "00abc XYZ", strings.Repeat("a", maxApiTokenLength+1)} {
+		if _, err := ReadApiToken(strings.NewReader(input)); err == nil {
+			t.Errorf("Expected reading a token from %q to fail", input)
+		}
+	}
+}
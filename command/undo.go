@@ -0,0 +1,377 @@
+package command
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// undoInput is the standard input the confirmation is read from.
+var undoInput io.Reader = os.Stdin
+
+type UndoCommand struct {
+	*Command
+}
+
+type UndoCommandConfig struct {
+	Last bool
+	ID   string
+	List bool
+	Yes  bool
+}
+
+func (c *UndoCommand) Synopsis() string {
+	return "Undo the changes made by a command"
+}
+
+func (c *UndoCommand) Help() string {
+	helpText := `
+Usage: okta-admin undo -last | -id ID | -list [options]
+
+  Undoes the changes a command made to the organization, as
+  recorded in the journal. Commands record the changes which can
+  be undone along with the operations undoing them: users added
+  to groups are removed from them, deactivated users are activated
+  again, created users are deactivated and activated or
+  deactivated group rules are changed back. Other changes, like
+  resetting passwords or factors, ending sessions, deleting group
+  rules or restoring policies, can't be undone and aren't recorded.
+
+  The operations are listed and only performed once confirmed.
+  Each is checked first and skipped if the change was reverted
+  since, eg- if the user was already removed from the group. If
+  some of the operations fail, the changes can be undone again
+  with -id or -last, which retries the operations still needed.
+  Activated users don't receive an activation email and are left
+  provisioned, and the app assignments and sessions removed when
+  they were deactivated aren't restored. Undoing changes is
+  recorded in the journal too, so it can be undone with -id.
+
+  The journal is journal.jsonl in the okta-admin directory of the
+  user's configuration directory, eg- ~/.config/okta-admin on
+  Linux, unless the OKTA_ADMIN_JOURNAL environment variable
+  specifies another file.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -last  Undo the most recent changes made to the organization
+         which weren't undone yet
+  -id    ID of the journal entry whose changes are undone, as
+         listed by -list
+  -list  List the changes recorded for the organization, most
+         recent last
+  -yes   Don't ask for confirmation
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *UndoCommand) ParseArgs(args []string) (*UndoCommandConfig, error) {
+	var cfg UndoCommandConfig
+
+	flags := c.Meta.FlagSet
+	flags.BoolVar(&cfg.Last, "last", false, "")
+	flags.StringVar(&cfg.ID, "id", "", "")
+	flags.BoolVar(&cfg.List, "list", false, "")
+	flags.BoolVar(&cfg.Yes, "yes", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+	if rest := flags.Args(); len(rest) > 0 {
+		return &cfg, errors.New(fmt.Sprintf("unexpected arguments: %s", strings.Join(rest, " ")))
+	}
+
+	n := 0
+	for _, set := range []bool{cfg.Last, cfg.ID != "", cfg.List} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return &cfg, errors.New("exactly one of -last, -id or -list must be specified")
+	}
+
+	params := []*parameter{
+		{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+	}
+	if !cfg.List {
+		params = append(params, &parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken})
+	}
+	return &cfg, c.Command.validateParameters(params...)
+}
+
+func (c *UndoCommand) Run(args []string) int {
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	entries, invalid, err := readJournal()
+	if err != nil {
+		c.logError("Failed to read the journal", err)
+		return ExitFailure
+	}
+	if invalid > 0 {
+		c.Logger.Printf("Skipped %d line(s) of the journal which aren't valid entries\n", invalid)
+	}
+
+	// undoneBy maps the IDs of entries to the entries undoing them.
+	// Entries whose changes were only partly undone can be undone
+	// again.
+	undoneBy := map[string]string{}
+	for _, e := range entries {
+		if e.Undoes != "" && !e.Incomplete {
+			undoneBy[e.Undoes] = e.ID
+		}
+	}
+
+	orgUrl := c.Meta.GlobalOptions.OrgUrl
+	if cfg.List {
+		c.list(entries, undoneBy)
+		return ExitOK
+	}
+
+	var entry *journalEntry
+	if cfg.Last {
+		for i := len(entries) - 1; i >= 0 && entry == nil; i-- {
+			e := entries[i]
+			if sameOrgUrl(e.OrgUrl, orgUrl) && e.Undoes == "" && undoneBy[e.ID] == "" {
+				entry = e
+			}
+		}
+		if entry == nil {
+			c.Logger.Printf("No changes to undo were found for %s\n", orgUrl)
+			return ExitNotFound
+		}
+	} else {
+		for _, e := range entries {
+			if e.ID == cfg.ID {
+				entry = e
+			}
+		}
+		switch {
+		case entry == nil:
+			c.logError("Failed to find changes", newNotFoundError("no journal entry with ID %s", cfg.ID))
+			return ExitNotFound
+		case !sameOrgUrl(entry.OrgUrl, orgUrl):
+			c.Logger.Printf("The changes of %s were made to %s, not %s\n", entry.ID, entry.OrgUrl, orgUrl)
+			return ExitUsage
+		case undoneBy[entry.ID] != "":
+			c.Logger.Printf("The changes of %s were already undone by %s\n", entry.ID, undoneBy[entry.ID])
+			return ExitConflict
+		}
+	}
+
+	// Changes are undone in the opposite order they were made
+	var ops []*journalOp
+	c.Logger.Printf("Changes made by %s (%s) on %s:\n", describeJournalEntry(entry), entry.ID, entry.Time.Local().Format(time.RFC1123))
+	for i := len(entry.Changes) - 1; i >= 0; i-- {
+		ch := entry.Changes[i]
+		if ch.Inverse == nil {
+			c.Logger.Printf("  %s, which can't be undone\n", ch.Change)
+			continue
+		}
+		ops = append(ops, ch.Inverse)
+		c.Logger.Printf("  %s, undone by: %s\n", ch.Change, ch.Inverse)
+	}
+	if len(ops) == 0 {
+		c.Logger.Println("None of the changes can be undone")
+		return ExitFailure
+	}
+
+	if !cfg.Yes && !c.confirm(fmt.Sprintf("Undo %d change(s)?", len(ops))) {
+		c.Logger.Println("Nothing was undone")
+		return ExitFailure
+	}
+
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+
+	var failures []error
+	for _, op := range ops {
+		msg, err := undoOp(client, op)
+		if err != nil {
+			c.logError(fmt.Sprintf("Failed to %s", strings.ToLower(op.String()[:1])+op.String()[1:]), err)
+			failures = append(failures, err)
+			continue
+		}
+		c.Logger.Println(msg)
+	}
+	c.journal.setUndoes(entry.ID, len(failures) > 0)
+	if len(failures) > 0 {
+		c.Logger.Printf("Failed to undo %d change(s), run undo -id %s to retry\n", len(failures), entry.ID)
+	}
+	return fanOutExitCode(len(ops), failures)
+}
+
+// list prints the journal entries of the organization.
+func (c *UndoCommand) list(entries []*journalEntry, undoneBy map[string]string) {
+	w := tabwriter.NewWriter(c.Logger.Writer(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTIME\tCHANGES\tSTATUS\tCOMMAND")
+	n := 0
+	for _, e := range entries {
+		if !sameOrgUrl(e.OrgUrl, c.Meta.GlobalOptions.OrgUrl) {
+			continue
+		}
+		status := "-"
+		switch {
+		case undoneBy[e.ID] != "":
+			status = "undone by " + undoneBy[e.ID]
+		case e.Undoes != "" && e.Incomplete:
+			status = "partly undoes " + e.Undoes
+		case e.Undoes != "":
+			status = "undoes " + e.Undoes
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", e.ID, e.Time.Local().Format(time.RFC3339), len(e.Changes), status, describeJournalEntry(e))
+		n++
+	}
+	if n == 0 {
+		c.Logger.Printf("No changes were recorded for %s\n", c.Meta.GlobalOptions.OrgUrl)
+		return
+	}
+	w.Flush()
+}
+
+// confirm asks the question and returns whether it was answered
+// with yes.
+func (c *UndoCommand) confirm(question string) bool {
+	c.Logger.Printf("%s [y/N] ", question)
+	answer, err := bufio.NewReader(undoInput).ReadString('\n')
+	if err != nil && answer == "" {
+		c.Logger.Println()
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// describeJournalEntry returns the command line of the entry.
+func describeJournalEntry(e *journalEntry) string {
+	return strings.TrimSpace(e.Command + " " + strings.Join(e.Args, " "))
+}
+
+// undoOp performs the operation, unless the change it undoes was
+// reverted already, and returns a message describing the outcome.
+func undoOp(client oktaapi.Client, op *journalOp) (string, error) {
+	var resp *http.Response
+	var err error
+	expected := http.StatusNoContent
+
+	switch op.Op {
+	case opRemoveGroupMember, opAddGroupMember:
+		var member bool
+		member, err = hasGroupMember(client, op.UserID, op.GroupID)
+		if err != nil {
+			return "", err
+		}
+		if op.Op == opRemoveGroupMember {
+			if !member {
+				return fmt.Sprintf("Skipped: user %s isn't a member of group %s anymore", op.UserID, op.GroupID), nil
+			}
+			r, e := client.RemoveUserFromGroup(op.GroupID, op.UserID)
+			resp, err = httpResponse(r), e
+		} else {
+			if member {
+				return fmt.Sprintf("Skipped: user %s is a member of group %s already", op.UserID, op.GroupID), nil
+			}
+			r, e := client.AddUserToGroup(op.GroupID, op.UserID)
+			resp, err = httpResponse(r), e
+		}
+
+	case opActivateUser, opDeactivateUser:
+		var user *oktaapi.User
+		user, _, err = client.GetUser(op.UserID)
+		if err != nil {
+			return "", err
+		}
+		deprovisioned := user.Status == userStatusDeprovisioned
+		expected = http.StatusOK
+		if op.Op == opActivateUser {
+			if !deprovisioned {
+				return fmt.Sprintf("Skipped: user %s isn't deactivated anymore (%s)", op.UserID, user.Status), nil
+			}
+			_, r, e := client.ActivateUser(op.UserID, query.NewQueryParams(query.WithSendEmail(false)))
+			resp, err = httpResponse(r), e
+		} else {
+			if deprovisioned {
+				return fmt.Sprintf("Skipped: user %s is deactivated already", op.UserID), nil
+			}
+			r, e := client.DeactivateUser(op.UserID, nil)
+			resp, err = httpResponse(r), e
+		}
+
+	case opActivateGroupRule, opDeactivateGroupRule:
+		var rule *okta.GroupRule
+		rule, _, err = client.GetGroupRule(op.RuleID, nil)
+		if err != nil {
+			return "", err
+		}
+		if op.Op == opActivateGroupRule {
+			if rule.Status == groupRuleStatusActive {
+				return fmt.Sprintf("Skipped: group rule %s is active already", op.RuleID), nil
+			}
+			r, e := client.ActivateGroupRule(op.RuleID)
+			resp, err = httpResponse(r), e
+		} else {
+			if rule.Status == groupRuleStatusInactive {
+				return fmt.Sprintf("Skipped: group rule %s is inactive already", op.RuleID), nil
+			}
+			r, e := client.DeactivateGroupRule(op.RuleID)
+			resp, err = httpResponse(r), e
+		}
+
+	default:
+		return "", errors.New(fmt.Sprintf("unknown operation %s", op.Op))
+	}
+
+	if err != nil {
+		return "", err
+	}
+	if resp == nil || resp.StatusCode != expected {
+		status := "no response"
+		if resp != nil {
+			status = resp.Status
+		}
+		return "", errors.New(fmt.Sprintf("unexpected response (%s)", status))
+	}
+	return fmt.Sprintf("Done: %s", op), nil
+}
+
+// hasGroupMember returns whether the user is a member of the group.
+func hasGroupMember(client oktaapi.Client, userID, groupID string) (bool, error) {
+	groups, resp, err := client.ListUserGroups(userID, nil)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return false, newNotFoundError("user %s doesn't exist", userID)
+	}
+	for _, g := range groups {
+		if g.Id == groupID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
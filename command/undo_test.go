@@ -0,0 +1,251 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func createTestUndoCommand(globalOptsHelpText string) *UndoCommand {
+	return &UndoCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_undo_cmd"),
+	}
+}
+
+func TestUndoCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestUndoCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestUndoCommand_ParseArgs(t *testing.T) {
+	t.Parallel()
+
+	c := createTestUndoCommand("")
+	cfg, err := c.ParseArgs([]string{"-id", "0123456789ab", "-yes"})
+	if err != nil {
+		t.Fatalf("Failed to parse arguments: %v", err)
+	}
+	if cfg.ID != "0123456789ab" || !cfg.Yes || cfg.Last || cfg.List {
+		t.Errorf("Unexpected config %+v", cfg)
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"-yes"},
+		{"-last", "-list"},
+		{"-last", "-id", "0123456789ab"},
+		{"-last", "assign-groups"},
+	} {
+		c := createTestUndoCommand("")
+		if _, err := c.ParseArgs(args); err == nil {
+			t.Errorf("Expected parsing of %q to fail", args)
+		}
+	}
+
+	// Listing doesn't require an API token
+	c = createTestUndoCommand("")
+	c.Meta.GlobalOptions.ApiToken = ""
+	if _, err := c.ParseArgs([]string{"-list"}); err != nil {
+		t.Errorf("Expected -list to be parsed without an API token, received %v", err)
+	}
+}
+
+func TestUndoCommand_Run(t *testing.T) {
+	const orgUrl = "https://undo.okta.com/"
+
+	client := newFakeOktaClient()
+	client.users = []*okta.User{fakeUser("00u1", "harry.potter@hogwarts.co.uk", "ACTIVE")}
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor"), fakeGroup("00g2", "Quidditch")}
+	client.members["00g2"] = []string{"00u1"}
+
+	// run runs the command with the arguments and records its
+	// changes in the journal, like the CLI does
+	run := func(name string, cmd func(c *Command) int, args ...string) (int, string) {
+		t.Helper()
+		c, out := createTestCommandWithClient("test_undo_cmd", client)
+		c.Meta.GlobalOptions.OrgUrl = orgUrl
+		code := cmd(c)
+		c.RecordJournal(name, args)
+		return code, out.String()
+	}
+	assignGroups := func(args ...string) {
+		t.Helper()
+		code, out := run("assign-groups", func(c *Command) int { return (&AssignUserGroupsCommand{Command: c}).Run(args) }, args...)
+		if code != ExitOK {
+			t.Fatalf("Expected assign-groups to succeed, received %d: %s", code, out)
+		}
+	}
+	undo := func(input string, args ...string) (int, string) {
+		t.Helper()
+		undoInput = strings.NewReader(input)
+		return run("undo", func(c *Command) int { return (&UndoCommand{Command: c}).Run(args) }, args...)
+	}
+
+	// Memberships the user already had aren't undone
+	assignGroups("-user", "harry.potter@hogwarts.co.uk", "-groups", "Gryffindor,Quidditch")
+	if code, out := undo("n\n", "-last"); code != ExitFailure || !strings.Contains(out, "Remove user 00u1 from group 00g1") || strings.Contains(out, "00g2") {
+		t.Errorf("Expected declined undo to list the removal from Gryffindor only, received %d: %s", code, out)
+	}
+	if !testEq(client.members["00g1"], []string{"00u1"}) {
+		t.Errorf("Expected nothing to be undone when declined, members are %v", client.members)
+	}
+
+	code, out := undo("y\n", "-last")
+	if code != ExitOK || len(client.members["00g1"]) != 0 || !testEq(client.members["00g2"], []string{"00u1"}) {
+		t.Errorf("Expected user to be removed from Gryffindor only, received %d: %s (members %v)", code, out, client.members)
+	}
+
+	// Undone changes and undos aren't undone by -last
+	if code, out := undo("", "-last", "-yes"); code != ExitNotFound {
+		t.Errorf("Expected nothing to be left to undo, received %d: %s", code, out)
+	}
+
+	entries, _, err := readJournal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, e := range entries {
+		if sameOrgUrl(e.OrgUrl, orgUrl) {
+			ids = append(ids, e.ID)
+		}
+	}
+	if len(ids) != 2 || entries[len(entries)-1].Undoes != ids[0] {
+		t.Fatalf("Expected the assignment and its undo to be journaled, received %v", ids)
+	}
+	if code, _ := undo("", "-id", ids[0], "-yes"); code != ExitConflict {
+		t.Errorf("Expected undoing changes twice to fail with %d, received %d", ExitConflict, code)
+	}
+
+	// Undos can be undone, which skips operations no longer needed
+	client.members["00g1"] = []string{"00u1"}
+	code, out = undo("", "-id", ids[1], "-yes")
+	if code != ExitOK || !strings.Contains(out, "Skipped: user 00u1 is a member of group 00g1 already") {
+		t.Errorf("Expected undo to be skipped, received %d: %s", code, out)
+	}
+
+	code, out = undo("", "-list")
+	if code != ExitOK || !strings.Contains(out, "undone by") || !strings.Contains(out, "undoes "+ids[0]) {
+		t.Errorf("Expected entries to be listed, received %d: %s", code, out)
+	}
+}
+
+func TestUndoCommand_RunFailure(t *testing.T) {
+	const orgUrl = "https://undo-failure.okta.com/"
+
+	client := newFakeOktaClient()
+	client.users = []*okta.User{fakeUser("00u1", "ron.weasley@hogwarts.co.uk", "ACTIVE")}
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor")}
+
+	run := func(name string, cmd func(c *Command) int, args ...string) (int, string) {
+		t.Helper()
+		c, out := createTestCommandWithClient("test_undo_cmd", client)
+		c.Meta.GlobalOptions.OrgUrl = orgUrl
+		code := cmd(c)
+		c.RecordJournal(name, args)
+		return code, out.String()
+	}
+	undo := func() (int, string) {
+		t.Helper()
+		return run("undo", func(c *Command) int { return (&UndoCommand{Command: c}).Run([]string{"-last", "-yes"}) }, "-last", "-yes")
+	}
+
+	// Errors of the operations undoing changes are reported along
+	// with their exit codes
+	args := []string{"-user", "ron.weasley@hogwarts.co.uk", "-groups", "Gryffindor"}
+	if code, out := run("assign-groups", func(c *Command) int { return (&AssignUserGroupsCommand{Command: c}).Run(args) }, args...); code != ExitOK {
+		t.Fatalf("Expected assign-groups to succeed, received %d: %s", code, out)
+	}
+	client.errs["RemoveUserFromGroup"] = &oktaapi.ApiError{StatusCode: http.StatusForbidden, Code: oktaapi.ErrorCodeForbidden, Summary: "You do not have permission to perform the requested action"}
+	code, out := undo()
+	if code != ExitAuth || !strings.Contains(out, "Failed to remove user 00u1 from group 00g1: You do not have permission") {
+		t.Errorf("Expected the API error to be reported with exit code %d, received %d: %s", ExitAuth, code, out)
+	}
+	if !testEq(client.members["00g1"], []string{"00u1"}) {
+		t.Errorf("Expected the membership to be kept, members are %v", client.members)
+	}
+
+	args = []string{"-user", "ron.weasley@hogwarts.co.uk"}
+	if code, out := run("deactivate-user", func(c *Command) int { return (&DeactivateUserCommand{Command: c}).Run(args) }, args...); code != ExitOK {
+		t.Fatalf("Expected deactivate-user to succeed, received %d: %s", code, out)
+	}
+	client.errs["ActivateUser"] = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	code, out = undo()
+	if code != ExitNetwork || !strings.Contains(out, "connection refused") {
+		t.Errorf("Expected the network error to be reported with exit code %d, received %d: %s", ExitNetwork, code, out)
+	}
+	if status := client.findUser("00u1").Status; status != "DEPROVISIONED" {
+		t.Errorf("Expected the user to stay deactivated, status is %s", status)
+	}
+}
+
+func TestUndoCommand_RunRetry(t *testing.T) {
+	const orgUrl = "https://undo-retry.okta.com/"
+
+	client := newFakeOktaClient()
+	client.users = []*okta.User{fakeUser("00u1", "ron.weasley@hogwarts.co.uk", "DEPROVISIONED")}
+	client.groups = []*okta.Group{fakeGroup("00g1", "Gryffindor")}
+	client.members["00g1"] = []string{"00u1"}
+
+	line, _ := json.Marshal(&journalEntry{
+		ID:      "retry1",
+		OrgUrl:  orgUrl,
+		Command: "offboard",
+		Changes: []*journalChange{
+			{Change: "Added user 00u1 to group 00g1", Inverse: &journalOp{Op: opRemoveGroupMember, UserID: "00u1", GroupID: "00g1"}},
+			{Change: "Deactivated user 00u1", Inverse: &journalOp{Op: opActivateUser, UserID: "00u1"}},
+		},
+	})
+	path, err := journalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := appendLine(path, line); err != nil {
+		t.Fatalf("Failed to write journal entry: %v", err)
+	}
+
+	run := func(args ...string) (int, string) {
+		t.Helper()
+		c, out := createTestCommandWithClient("test_undo_cmd", client)
+		c.Meta.GlobalOptions.OrgUrl = orgUrl
+		code := (&UndoCommand{Command: c}).Run(args)
+		c.RecordJournal("undo", args)
+		return code, out.String()
+	}
+
+	// The entry isn't marked undone while some of its changes aren't
+	client.errs["ActivateUser"] = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	code, out := run("-id", "retry1", "-yes")
+	if code != ExitPartialFailure || !strings.Contains(out, "run undo -id retry1 to retry") {
+		t.Fatalf("Expected the activation to fail, received %d: %s", code, out)
+	}
+	if _, out := run("-list"); !strings.Contains(out, "partly undoes retry1") {
+		t.Errorf("Expected the undo to be listed as partial, received %s", out)
+	}
+
+	// Retrying skips the changes undone already
+	delete(client.errs, "ActivateUser")
+	client.calls = nil
+	code, out = run("-last", "-yes")
+	if code != ExitOK || !strings.Contains(out, "Skipped: user 00u1 isn't a member of group 00g1 anymore") {
+		t.Fatalf("Expected the remaining change to be undone, received %d: %s", code, out)
+	}
+	if status := client.findUser("00u1").Status; status == "DEPROVISIONED" {
+		t.Errorf("Expected the user to be activated, status is %s", status)
+	}
+	for _, call := range client.calls {
+		if strings.HasPrefix(call, "RemoveUserFromGroup") {
+			t.Errorf("Expected the membership not to be removed again, calls made: %v", client.calls)
+		}
+	}
+
+	if code, out := run("-id", "retry1", "-yes"); code != ExitConflict {
+		t.Errorf("Expected exit code %d once every change is undone, received %d: %s", ExitConflict, code, out)
+	}
+}
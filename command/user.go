@@ -1,25 +1,103 @@
 package command
 
 import (
+	"errors"
+	"fmt"
 	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta"
+	"github.com/okta/okta-sdk-golang/okta/query"
 	"net/http"
+	"strings"
 )
 
-// getUserResult contains the result of an async HTTP request
-// made to Okta API to fetch a single User.
-type getUserResult struct {
-	User oktaapi.ApiResponse
-	Resp *http.Response
-	Err  error
+// listAllUsers fetches every page of users matching the query
+// params supplied to it. Okta excludes deprovisioned users from
+// a listing unless they're explicitly requested via a filter.
+func listAllUsers(client oktaapi.Client, qp *query.Params) ([]*okta.User, error) {
+	var res []*okta.User
+	if qp == nil {
+		qp = query.NewQueryParams()
+	}
+
+	for {
+		users, resp, err := client.ListUsers(qp)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.New(resp.Status)
+		}
+		res = append(res, users...)
+
+		cursor := oktaapi.NextPageCursor(resp.Response)
+		if cursor == "" {
+			return res, nil
+		}
+		qp.After = cursor
+	}
+}
+
+// userLogin returns the login of the user, falling back to the
+// user's ID if the profile doesn't contain a login.
+func userLogin(u *okta.User) string {
+	if login := oktaapi.ProfileOf(u).Login(); login != "" {
+		return login
+	}
+	return u.Id
 }
 
-// getUser fetches a single User from Okta API asynchronously
-func getUser(email, orgUrl, apiToken string, ch chan<- *getUserResult) {
-	user, resp, err := oktaapi.GetUserByEmail(
-		&oktaapi.Credentials{
-			OrgUrl: orgUrl, ApiToken: apiToken,
-		},
-		email,
-	)
-	ch <- &getUserResult{User: user, Resp: resp, Err: err}
+// resolveUser returns the ID of the organization member identified
+// by user, which may be their Okta ID, login or email ID. Okta looks
+// users up by ID or login directly. If neither matches and user
+// looks like an email ID, members whose profile contains it are
+// searched for instead, which fails if more than one does. If the
+// client caches the IDs users are resolved to, the cached ID is
+// returned if there is one.
+func resolveUser(client oktaapi.Client, user string) (string, error) {
+	cache, ok := client.(userIDCache)
+	if !ok {
+		return lookupUser(client, user)
+	}
+	if id, ok := cache.cachedUserID(user); ok {
+		return id, nil
+	}
+	id, err := lookupUser(client, user)
+	if err == nil {
+		cache.cacheUserID(user, id)
+	}
+	return id, err
+}
+
+// lookupUser resolves the user via the API, as resolveUser does.
+func lookupUser(client oktaapi.Client, user string) (string, error) {
+	u, resp, err := client.GetUser(user)
+	if err == nil {
+		return u.Id, nil
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotFound {
+		return "", err
+	}
+	if !strings.Contains(user, "@") {
+		return "", newNotFoundError("no user found with ID or login %s", user)
+	}
+
+	matches, err := listAllUsers(client, query.NewQueryParams(
+		query.WithSearch("profile.email eq "+QuoteFilterValue(user))))
+	if err != nil {
+		return "", err
+	}
+	switch len(matches) {
+	case 0:
+		return "", newNotFoundError("no user found with ID, login or email ID %s", user)
+	case 1:
+		return matches[0].Id, nil
+	}
+
+	logins := make([]string, len(matches), len(matches))
+	for i, m := range matches {
+		logins[i] = userLogin(m)
+	}
+	return "", errors.New(fmt.Sprintf(
+		"%d users have the email ID %s (%s), specify the ID or login of one of them instead",
+		len(matches), user, strings.Join(logins, ", ")))
 }
@@ -0,0 +1,181 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	logOutcomeFailure = "FAILURE"
+
+	highlightFailedLogin = "!! FAILED LOGIN"
+	highlightFailedMfa   = "!! FAILED MFA"
+	highlightMfa         = "!! MFA"
+)
+
+type UserActivityCommand struct {
+	*Command
+}
+
+type UserActivityCommandConfig struct {
+	User         string
+	Since, Until time.Time
+}
+
+func (c *UserActivityCommand) Synopsis() string {
+	return "Show a timeline of an organization member's activity"
+}
+
+func (c *UserActivityCommand) Help() string {
+	helpText := `
+Usage: okta-admin user-activity [options]
+
+  Shows a chronological timeline of System Log events in which
+  an organization member is either the actor or a target. Each
+  event is listed with the IP address, location and client it
+  originated from and its outcome.
+
+  Failed logins and MFA challenges are highlighted to help
+  investigate compromised accounts.
+{{.GlobalOptionsHelpText}}
+Options:
+
+  -user  ID, login or email ID of the organization member.
+         -email is accepted as an alias.
+  -since Only show events published after this point in time.
+         Either an RFC3339 timestamp or a duration in the past,
+         like 12h or 7d (Default: 7d)
+  -until Only show events published before this point in time,
+         specified the same way as -since (Default: now)
+`
+
+	return c.Command.prepareHelpMessage(
+		helpText,
+		map[string]interface{}{
+			"GlobalOptionsHelpText": c.Meta.GlobalOptionsHelpText,
+		},
+	)
+}
+
+func (c *UserActivityCommand) ParseArgs(args []string) (*UserActivityCommandConfig, error) {
+	var cfg UserActivityCommandConfig
+	var since, until string
+
+	flags := c.Meta.FlagSet
+	flags.StringVar(&cfg.User, "user", "", "")
+	flags.StringVar(&cfg.User, "email", "", "")
+	flags.StringVar(&since, "since", "7d", "")
+	flags.StringVar(&until, "until", "0s", "")
+
+	if err := flags.Parse(args); err != nil {
+		return &cfg, err
+	}
+
+	err := c.Command.validateParameters(
+		&parameter{Name: "api-token", Required: true, Value: c.Meta.GlobalOptions.ApiToken},
+		&parameter{Name: "org-url", Required: true, Value: c.Meta.GlobalOptions.OrgUrl, ValidationFunc: ValidateUrl},
+		&parameter{Name: "user", Required: true, Value: cfg.User, ValidationFunc: ValidateUserIdentifier},
+	)
+	if err != nil {
+		return &cfg, err
+	}
+
+	now := time.Now()
+	if cfg.Since, err = ParseRelativeTime(since, now); err != nil {
+		return &cfg, errors.New(fmt.Sprintf("since: %v", err))
+	}
+	if cfg.Until, err = ParseRelativeTime(until, now); err != nil {
+		return &cfg, errors.New(fmt.Sprintf("until: %v", err))
+	}
+	return &cfg, nil
+}
+
+func (c *UserActivityCommand) Run(args []string) int {
+	cfg, err := c.ParseArgs(args)
+	if err != nil {
+		c.logError("Failed to parse arguments", err)
+		return ExitUsage
+	}
+
+	client, err := c.OktaClient()
+	if err != nil {
+		c.logError("Failed to initialize Okta client", err)
+		return exitCode(err)
+	}
+
+	// Fetch user ID
+	uid, err := resolveUser(client, cfg.User)
+	if err != nil {
+		c.logError("Failed to resolve user ID", err)
+		return exitCode(err)
+	}
+
+	qp := query.NewQueryParams(
+		query.WithSince(cfg.Since.UTC().Format(logsTimeLayout)),
+		query.WithUntil(cfg.Until.UTC().Format(logsTimeLayout)),
+		query.WithFilter(fmt.Sprintf("actor.id eq \"%s\" or target.id eq \"%s\"", uid, uid)),
+		query.WithSortOrder("ASCENDING"),
+		query.WithLimit(logsPageSize),
+	)
+	events, err := listAllLogs(client, qp)
+	if err != nil {
+		c.logError("Failed to fetch logs", err)
+		return exitCode(err)
+	}
+	if len(events) == 0 {
+		c.Logger.Printf("No activity found for %s\n", cfg.User)
+		return 0
+	}
+
+	w := tabwriter.NewWriter(c.Logger.Writer(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tEVENT\tOUTCOME\tROLE\tIP\tLOCATION\tCLIENT\tNOTE")
+	for _, e := range events {
+		role := "target"
+		if e.Actor != nil && e.Actor.Id == uid {
+			role = "actor"
+		}
+
+		line := strings.Join([]string{
+			e.Published.UTC().Format(time.RFC3339),
+			e.EventType,
+			Coalesce(logEventOutcome(e), "-"),
+			role,
+			Coalesce(logEventIP(e), "-"),
+			Coalesce(logEventLocation(e), "-"),
+			Coalesce(logEventClient(e), "-"),
+			logEventHighlight(e),
+		}, "\t")
+		fmt.Fprintln(w, line)
+	}
+	if err := w.Flush(); err != nil {
+		c.logError("Failed to render timeline", err)
+		return exitCode(err)
+	}
+
+	return 0
+}
+
+// logEventHighlight returns a note drawing attention to the event
+// if it's a failed login or an MFA challenge. For other events, an
+// empty string is returned.
+func logEventHighlight(e *oktaapi.LogEvent) string {
+	failed := logEventOutcome(e) == logOutcomeFailure
+	mfa := strings.HasPrefix(e.EventType, "user.authentication.auth_via_mfa") ||
+		strings.HasPrefix(e.EventType, "user.mfa.") ||
+		strings.Contains(e.EventType, "factor_verify")
+
+	switch {
+	case mfa && failed:
+		return highlightFailedMfa
+	case mfa:
+		return highlightMfa
+	case failed && (e.EventType == "user.session.start" || strings.HasPrefix(e.EventType, "user.authentication.")):
+		return highlightFailedLogin
+	}
+	return ""
+}
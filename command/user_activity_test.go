@@ -0,0 +1,110 @@
+package command
+
+import (
+	oktaapi "github.com/duaraghav8/okta-admin/okta"
+	"github.com/okta/okta-sdk-golang/okta"
+	"testing"
+	"time"
+)
+
+func createTestUserActivityCommand(globalOptsHelpText string) *UserActivityCommand {
+	return &UserActivityCommand{
+		Command: createTestCommand(globalOptsHelpText, "test_user_activity_cmd"),
+	}
+}
+
+func TestUserActivityCommand_Help(t *testing.T) {
+	t.Parallel()
+	c := createTestUserActivityCommand(testHelpMessage)
+	testCommandHelp(t, c.Help())
+}
+
+func TestUserActivityCommand_ParseArgs(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestUserActivityCommand("")
+		args := []string{"-email", "harry.potter@hogwarts.co.uk"}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.User != args[1] {
+			t.Errorf("Expected user to be %s, received %s", args[1], cfg.User)
+		}
+		if d := time.Since(cfg.Since); d < 7*24*time.Hour || d > 7*24*time.Hour+time.Minute {
+			t.Errorf("Expected since to be 7 days ago, received %s", cfg.Since)
+		}
+		if d := time.Since(cfg.Until); d < 0 || d > time.Minute {
+			t.Errorf("Expected until to be now, received %s", cfg.Until)
+		}
+	})
+
+	t.Run("with options", func(t *testing.T) {
+		t.Parallel()
+
+		c := createTestUserActivityCommand("")
+		args := []string{
+			"-email", "harry.potter@hogwarts.co.uk",
+			"-since", "2019-10-01T00:00:00Z",
+			"-until", "2019-10-02T00:00:00Z",
+		}
+
+		cfg, err := c.ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Failed to parse arguments: %v", err)
+		}
+
+		if cfg.Since.Format(time.RFC3339) != args[3] {
+			t.Errorf("Expected since to be %s, received %s", args[3], cfg.Since)
+		}
+		if cfg.Until.Format(time.RFC3339) != args[5] {
+			t.Errorf("Expected until to be %s, received %s", args[5], cfg.Until)
+		}
+	})
+}
+
+func TestLogEventHighlight(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		eventType, result, expected string
+	}{
+		{"user.session.start", "SUCCESS", ""},
+		{"user.session.start", "FAILURE", highlightFailedLogin},
+		{"user.authentication.sso", "FAILURE", highlightFailedLogin},
+		{"user.authentication.auth_via_mfa", "SUCCESS", highlightMfa},
+		{"user.authentication.auth_via_mfa", "FAILURE", highlightFailedMfa},
+		{"system.push.send_factor_verify_push", "SUCCESS", highlightMfa},
+		{"user.mfa.okta_verify.deny_push", "FAILURE", highlightFailedMfa},
+		{"group.user_membership.add", "FAILURE", ""},
+	}
+
+	for _, tc := range testCases {
+		e := &oktaapi.LogEvent{EventType: tc.eventType, Outcome: &okta.LogOutcome{Result: tc.result}}
+		if res := logEventHighlight(e); res != tc.expected {
+			t.Errorf("Expected highlight %q for %s (%s), received %q", tc.expected, tc.eventType, tc.result, res)
+		}
+	}
+}
+
+func TestUserActivityCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.users = []*okta.User{fakeUser("00u1", "harry.potter@hogwarts.co.uk", "ACTIVE")}
+	c, out := createTestCommandWithClient("test_user_activity_cmd", client)
+	cmd := &UserActivityCommand{Command: c}
+
+	if code := cmd.Run([]string{"-email", "harry.potter@hogwarts.co.uk"}); code != 0 {
+		t.Fatalf("Expected exit code 0, received %d: %s", code, out)
+	}
+	if out.String() != "No activity found for harry.potter@hogwarts.co.uk\n" {
+		t.Errorf("Unexpected output %q", out)
+	}
+	if call := client.calls[len(client.calls)-1]; call != `GetLogs actor.id eq "00u1" or target.id eq "00u1"` {
+		t.Errorf("Expected logs to be filtered by the user's ID, received call %q", call)
+	}
+}
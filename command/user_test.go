@@ -0,0 +1,43 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveUser(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOktaClient()
+	client.users = append(client.users,
+		fakeUserWithLogin("00u1", "harry.potter", "harry.potter@hogwarts.co.uk", "ACTIVE"),
+		fakeUserWithLogin("00u2", "fred", "weasleys@hogwarts.co.uk", "ACTIVE"),
+		fakeUserWithLogin("00u3", "george", "weasleys@hogwarts.co.uk", "SUSPENDED"),
+		fakeUser("00u4", "ron+quidditch@hogwarts.co.uk", "ACTIVE"),
+	)
+
+	testCases := []struct{ user, expected string }{
+		{"00u1", "00u1"},
+		{"harry.potter", "00u1"},
+		{"harry.potter@hogwarts.co.uk", "00u1"},
+		{"ron+quidditch@hogwarts.co.uk", "00u4"},
+	}
+	for _, tc := range testCases {
+		uid, err := resolveUser(client, tc.user)
+		if err != nil {
+			t.Errorf("Failed to resolve %s: %v", tc.user, err)
+		}
+		if uid != tc.expected {
+			t.Errorf("Expected %s to resolve to %s, received %s", tc.user, tc.expected, uid)
+		}
+	}
+
+	if _, err := resolveUser(client, "weasleys@hogwarts.co.uk"); err == nil || !strings.Contains(err.Error(), "fred, george") {
+		t.Errorf("Expected ambiguity error listing both logins, received %v", err)
+	}
+	for _, user := range []string{"draco", "draco@hogwarts.co.uk"} {
+		if _, err := resolveUser(client, user); err == nil || !strings.Contains(err.Error(), "no user found") {
+			t.Errorf("Expected %s not to be found, received %v", user, err)
+		}
+	}
+}
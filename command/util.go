@@ -2,10 +2,14 @@ package command
 
 import (
 	"errors"
+	"fmt"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
+	"unicode"
 )
 
 // FillTemplateMessage interpolates data into a complex string
@@ -32,6 +36,13 @@ func Coalesce(args ...string) string {
 	return ""
 }
 
+// QuoteFilterValue returns the value as a string literal of an Okta
+// filter or search expression, escaping backslashes and quotes so
+// that the value can't end the literal.
+func QuoteFilterValue(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
 // ValidateUrl returns an error if the parameter supplied to it
 // is not a valid URL. Because this function is only a wrapper
 // around a standard library function, it doesn't need to be
@@ -50,3 +61,56 @@ func ValidateEmailID(email string) error {
 	}
 	return nil
 }
+
+// ValidateUserIdentifier returns an error if the parameter supplied
+// to it cannot be the Okta ID, login or email ID of a user. Okta
+// logins are at most 100 characters long and, like IDs and email
+// IDs, never contain whitespace.
+func ValidateUserIdentifier(user string) error {
+	if len(user) > 100 || strings.IndexFunc(user, unicode.IsSpace) != -1 {
+		return errors.New("invalid user ID, login or email ID")
+	}
+	return nil
+}
+
+// ValidateOneOf returns a validation function which returns an
+// error if the value supplied to it is not one of the allowed
+// values.
+func ValidateOneOf(allowed ...string) func(value string) error {
+	return func(value string) error {
+		for _, a := range allowed {
+			if value == a {
+				return nil
+			}
+		}
+		return errors.New(fmt.Sprintf("%s is invalid, must be one of: %s", value, strings.Join(allowed, ", ")))
+	}
+}
+
+// ParseRelativeTime returns the point in time described by value.
+// The value can either be an RFC3339 timestamp or a duration in
+// the past relative to now, like "90m", "12h" or "7d".
+func ParseRelativeTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	var d time.Duration
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return time.Time{}, errors.New(fmt.Sprintf("invalid duration %s", value))
+		}
+		d = time.Duration(days) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(value); err != nil {
+			return time.Time{}, errors.New(fmt.Sprintf("%s is neither a valid timestamp nor a duration", value))
+		}
+	}
+
+	if d < 0 {
+		return time.Time{}, errors.New(fmt.Sprintf("duration %s cannot be negative", value))
+	}
+	return now.Add(-d), nil
+}
@@ -2,7 +2,9 @@ package command
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestFillTemplateMessage(t *testing.T) {
@@ -75,6 +77,24 @@ func TestCoalesce(t *testing.T) {
 	}
 }
 
+func TestQuoteFilterValue(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		value, expected string
+	}{
+		{"harry.potter@hogwarts.co.uk", `"harry.potter@hogwarts.co.uk"`},
+		{`harry" or actor.id pr or "`, `"harry\" or actor.id pr or \""`},
+		{`harry\`, `"harry\\"`},
+		{"", `""`},
+	}
+	for _, tc := range testCases {
+		if res := QuoteFilterValue(tc.value); res != tc.expected {
+			t.Errorf("Expected %q to be quoted as %s, received %s", tc.value, tc.expected, res)
+		}
+	}
+}
+
 func TestValidateEmailID(t *testing.T) {
 	t.Run("valid", func(t *testing.T) {
 		t.Parallel()
@@ -103,3 +123,71 @@ func TestValidateEmailID(t *testing.T) {
 		}
 	})
 }
+
+func TestValidateUserIdentifier(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []string{"00ub0oNGTSWTBKOLGLNR", "harry.potter", "harry+quidditch@hogwarts.co.uk", "hp/seeker"} {
+		if err := ValidateUserIdentifier(tc); err != nil {
+			t.Errorf("Expected %s to be valid", tc)
+		}
+	}
+	for _, tc := range []string{"Harry Potter", "harry\t", strings.Repeat("a", 101)} {
+		if err := ValidateUserIdentifier(tc); err == nil {
+			t.Errorf("Expected %q to be invalid", tc)
+		}
+	}
+}
+
+func TestValidateOneOf(t *testing.T) {
+	t.Parallel()
+	validate := ValidateOneOf("csv", "json")
+
+	for _, v := range []string{"csv", "json"} {
+		if err := validate(v); err != nil {
+			t.Errorf("Expected %s to be valid", v)
+		}
+	}
+	for _, v := range []string{"", "CSV", "yaml", "json "} {
+		if err := validate(v); err == nil {
+			t.Errorf("Expected %s to be invalid", v)
+		}
+	}
+}
+
+func TestParseRelativeTime(t *testing.T) {
+	now := time.Date(2019, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+		testCases := []struct {
+			value    string
+			expected time.Time
+		}{
+			{"1h", now.Add(-time.Hour)},
+			{"90m", now.Add(-90 * time.Minute)},
+			{"7d", now.AddDate(0, 0, -7)},
+			{"0d", now},
+			{"2019-10-01T08:30:00Z", time.Date(2019, 10, 1, 8, 30, 0, 0, time.UTC)},
+		}
+		for _, tc := range testCases {
+			res, err := ParseRelativeTime(tc.value, now)
+			if err != nil {
+				t.Errorf("Expected %s to be valid, received error: %v", tc.value, err)
+				continue
+			}
+			if !res.Equal(tc.expected) {
+				t.Errorf("Expected %s for %s, received %s", tc.expected, tc.value, res)
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		testCases := []string{"", "foobar", "7days", "d", "-1h", "-2d", "2019-10-01"}
+		for _, tc := range testCases {
+			if _, err := ParseRelativeTime(tc, now); err == nil {
+				t.Errorf("Expected %s to be invalid", tc)
+			}
+		}
+	})
+}
@@ -0,0 +1,461 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	cmd "github.com/duaraghav8/okta-admin/command"
+	"github.com/duaraghav8/okta-admin/okta/oktatest"
+	"github.com/zalando/go-keyring"
+)
+
+func TestMain(m *testing.M) {
+	// The fake Okta server is served over plain HTTP
+	os.Setenv("OKTA_TESTING_DISABLE_HTTPS_CHECK", "true")
+	// Data is cached and changes are recorded in a temporary
+	// directory, not the user's
+	dir, err := ioutil.TempDir("", "okta-admin-cache")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(cmd.CacheDirEnv, dir)
+	os.Setenv(cmd.AuditLogEnv, filepath.Join(dir, "audit.jsonl"))
+	os.Setenv(cmd.JournalEnv, filepath.Join(dir, "journal.jsonl"))
+	// API tokens are stored in a temporary file or an in-memory
+	// keyring, not the user's
+	os.Setenv(cmd.TokenFileEnv, filepath.Join(dir, "tokens.enc"))
+	os.Setenv(cmd.PassphraseEnv, "mischief managed")
+	os.Setenv("DBUS_SESSION_BUS_ADDRESS", "disabled:")
+	keyring.MockInit()
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// runAgainst runs okta-admin with the args against the server and
+// returns its exit status and output.
+func runAgainst(s *oktatest.Server, args ...string) (int, string) {
+	out := &bytes.Buffer{}
+	args = append(args, "-org-url", s.URL, "-api-token", s.ApiToken)
+	return runCLI(args, out), out.String()
+}
+
+func TestEndToEnd(t *testing.T) {
+	t.Run("assign-groups searches groups across pages and reports failures", func(t *testing.T) {
+		s := oktatest.NewServer()
+		defer s.Close()
+		s.PageSize = 2
+
+		u := s.AddUser("jane@example.com", "Jane", "Doe", "ACTIVE")
+		for _, n := range []string{"a", "b-team", "b-alumni", "b", "c"} {
+			s.AddGroup(n)
+		}
+		d := s.AddGroup("d")
+		denied := s.AddGroup("e")
+		s.Fail(http.MethodPut, "/api/v1/groups/"+denied.Id+"/users/"+u.Id, 1, http.StatusForbidden, "You do not have permission to perform the requested action")
+
+		code, out := runAgainst(s, "assign-groups", "-email", "jane@example.com", "-groups", "b,"+d.Id+",e,f")
+		if code != cmd.ExitPartialFailure {
+			t.Errorf("Expected exit status %d, received %d", cmd.ExitPartialFailure, code)
+		}
+		for _, expected := range []string{"Added to b", "Added to d", "Failed to add user to e", "f does not exist"} {
+			if !strings.Contains(out, expected) {
+				t.Errorf("Expected output to contain %q, received:\n%s", expected, out)
+			}
+		}
+		if m := s.GroupMembers(denied.Id); len(m) != 0 {
+			t.Errorf("Expected user not to be added to e, members: %v", m)
+		}
+		// Groups are searched for by name instead of being listed
+		for _, r := range s.Requests() {
+			if strings.HasPrefix(r, "GET /api/v1/groups") && !strings.Contains(r, "q=") && !strings.HasPrefix(r, "GET /api/v1/groups/"+d.Id) {
+				t.Errorf("Expected groups to be searched for by name or fetched by ID, requests made: %v", s.Requests())
+				break
+			}
+		}
+	})
+
+	t.Run("deactivate-user processes every member of a batch", func(t *testing.T) {
+		s := oktatest.NewServer()
+		defer s.Close()
+
+		a := s.AddUser("a@example.com", "A", "A", "ACTIVE")
+		b := s.AddUser("b@example.com", "B", "B", "ACTIVE")
+		s.AddSession(a.Id)
+
+		code, out := runAgainst(s, "deactivate-user", "-emails", "a@example.com,missing@example.com,b@example.com")
+		if code != cmd.ExitPartialFailure {
+			t.Errorf("Expected exit status %d, received %d", cmd.ExitPartialFailure, code)
+		}
+		if !strings.Contains(out, "2 succeeded, 1 failed") {
+			t.Errorf("Expected summary of batch, received:\n%s", out)
+		}
+		for _, id := range []string{a.Id, b.Id} {
+			if status := s.User(id).Status; status != "DEPROVISIONED" {
+				t.Errorf("Expected %s to be deprovisioned, received %s", id, status)
+			}
+		}
+		if sessions := s.Sessions(a.Id); len(sessions) != 0 {
+			t.Errorf("Expected sessions to be ended, received %v", sessions)
+		}
+	})
+
+	t.Run("resolves users by ID, login or email ID", func(t *testing.T) {
+		s := oktatest.NewServer()
+		defer s.Close()
+
+		seeker := s.AddUserWithLogin("hp/seeker", "harry+quidditch@example.com", "Harry", "Potter", "ACTIVE")
+		keeper := s.AddUserWithLogin("ron", "ron@example.com", "Ron", "Weasley", "ACTIVE")
+		chaser := s.AddUser("ginny@example.com", "Ginny", "Weasley", "ACTIVE")
+		s.AddUserWithLogin("fred", "twins@example.com", "Fred", "Weasley", "ACTIVE")
+		s.AddUserWithLogin("george", "twins@example.com", "George", "Weasley", "ACTIVE")
+
+		code, out := runAgainst(s, "deactivate-user", "-users", "hp/seeker,ron@example.com,"+chaser.Id+",twins@example.com")
+		if code != cmd.ExitPartialFailure {
+			t.Errorf("Expected exit status %d, received %d", cmd.ExitPartialFailure, code)
+		}
+		for _, id := range []string{seeker.Id, keeper.Id, chaser.Id} {
+			if status := s.User(id).Status; status != "DEPROVISIONED" {
+				t.Errorf("Expected %s to be deprovisioned, received %s", id, status)
+			}
+		}
+		if !strings.Contains(out, "2 users have the email ID twins@example.com (fred, george)") {
+			t.Errorf("Expected ambiguity to be reported, received:\n%s", out)
+		}
+	})
+
+	t.Run("end-user-sessions ends all sessions of the user", func(t *testing.T) {
+		s := oktatest.NewServer()
+		defer s.Close()
+
+		u := s.AddUser("jane@example.com", "Jane", "Doe", "ACTIVE")
+		s.AddSession(u.Id)
+		s.AddSession(u.Id)
+
+		if code, out := runAgainst(s, "end-user-sessions", "-email", "jane@example.com"); code != 0 {
+			t.Fatalf("Expected exit status 0, received %d:\n%s", code, out)
+		}
+		if sessions := s.Sessions(u.Id); len(sessions) != 0 {
+			t.Errorf("Expected sessions to be ended, received %v", sessions)
+		}
+	})
+
+	t.Run("reset-user-mfa removes all factors", func(t *testing.T) {
+		s := oktatest.NewServer()
+		defer s.Close()
+
+		u := s.AddUser("jane@example.com", "Jane", "Doe", "ACTIVE")
+		s.AddFactor(u.Id, "push", "ACTIVE")
+		s.AddFactor(u.Id, "sms", "ACTIVE")
+
+		if code, out := runAgainst(s, "reset-user-mfa", "-email", "jane@example.com"); code != 0 {
+			t.Fatalf("Expected exit status 0, received %d:\n%s", code, out)
+		}
+		if n := s.Factors(u.Id); n != 0 {
+			t.Errorf("Expected no factors, received %d", n)
+		}
+	})
+
+	t.Run("audit-admins reports roles of active users", func(t *testing.T) {
+		s := oktatest.NewServer()
+		defer s.Close()
+		s.PageSize = 1
+
+		admin := s.AddUser("admin@example.com", "Ad", "Min", "ACTIVE")
+		s.AddUser("jane@example.com", "Jane", "Doe", "ACTIVE")
+		s.AddRole(admin.Id, "SUPER_ADMIN", "Super Administrator")
+
+		code, out := runAgainst(s, "audit-admins")
+		if code != 0 {
+			t.Fatalf("Expected exit status 0, received %d:\n%s", code, out)
+		}
+		if !strings.Contains(out, "admin@example.com") || !strings.Contains(out, "SUPER_ADMIN") {
+			t.Errorf("Expected report to contain the administrator, received:\n%s", out)
+		}
+		if strings.Contains(out, "jane@example.com") {
+			t.Errorf("Expected report to only contain administrators, received:\n%s", out)
+		}
+	})
+
+	t.Run("user-activity lists events of the user", func(t *testing.T) {
+		s := oktatest.NewServer()
+		defer s.Close()
+
+		u := s.AddUser("jane@example.com", "Jane", "Doe", "ACTIVE")
+		published := time.Now().UTC().Add(-time.Hour).Format(time.RFC3339)
+		s.AddLogEvent(map[string]interface{}{
+			"published":             published,
+			"eventType":             "user.session.start",
+			"displayMessage":        "User login to Okta",
+			"actor":                 map[string]interface{}{"id": u.Id, "alternateId": "jane@example.com"},
+			"outcome":               map[string]interface{}{"result": "SUCCESS"},
+			"client":                map[string]interface{}{"ipAddress": "10.0.0.1"},
+			"authenticationContext": map[string]interface{}{},
+		})
+		s.AddLogEvent(map[string]interface{}{
+			"published":      published,
+			"eventType":      "user.session.start",
+			"displayMessage": "Someone else",
+			"actor":          map[string]interface{}{"id": "00uother", "alternateId": "other@example.com"},
+		})
+
+		code, out := runAgainst(s, "user-activity", "-email", "jane@example.com")
+		if code != 0 {
+			t.Fatalf("Expected exit status 0, received %d:\n%s", code, out)
+		}
+		if !strings.Contains(out, "user.session.start") || strings.Contains(out, "other@example.com") {
+			t.Errorf("Expected only events of the user, received:\n%s", out)
+		}
+	})
+
+	t.Run("reports the causes of errors and how to resolve them", func(t *testing.T) {
+		s := oktatest.NewServer()
+		defer s.Close()
+		s.AddUser("jane@example.com", "Jane", "Doe", "ACTIVE")
+
+		code, out := runAgainst(s, "create-user", "-email", "jane@example.com", "-fname", "Jane", "-lname", "Doe", "-team", "Ops")
+		if code != cmd.ExitConflict {
+			t.Errorf("Expected exit status %d, received %d", cmd.ExitConflict, code)
+		}
+		for _, expected := range []string{"An object with this field already exists", "E0000001, errorId ", "Hint: "} {
+			if !strings.Contains(out, expected) {
+				t.Errorf("Expected output to contain %q, received:\n%s", expected, out)
+			}
+		}
+
+		buf := &bytes.Buffer{}
+		runCLI([]string{"list-groups", "-org-url", s.URL, "-api-token", "revoked"}, buf)
+		if !strings.Contains(buf.String(), "Invalid token provided") || !strings.Contains(buf.String(), "OKTA_API_TOKEN") {
+			t.Errorf("Expected invalid token to be reported with a hint, received:\n%s", buf)
+		}
+	})
+
+	t.Run("reports errors returned by Okta", func(t *testing.T) {
+		s := oktatest.NewServer()
+		defer s.Close()
+		s.AddUser("jane@example.com", "Jane", "Doe", "ACTIVE")
+		s.Fail(http.MethodGet, "/api/v1/groups", -1, http.StatusInternalServerError, "Groups are unavailable")
+
+		code, out := runAgainst(s, "list-groups")
+		if code != 1 {
+			t.Errorf("Expected exit status 1, received %d", code)
+		}
+		if !strings.Contains(out, "Groups are unavailable") {
+			t.Errorf("Expected output to mention the failure, received:\n%s", out)
+		}
+	})
+	t.Run("exit status describes the failure", func(t *testing.T) {
+		s := oktatest.NewServer()
+		defer s.Close()
+		s.AddUser("jane@example.com", "Jane", "Doe", "ACTIVE")
+		s.Fail(http.MethodGet, "/api/v1/logs", -1, http.StatusTooManyRequests, "API call exceeded rate limit due to too many requests.")
+
+		unreachable := oktatest.NewServer()
+		unreachable.Close()
+
+		testCases := []struct {
+			name     string
+			args     []string
+			expected int
+		}{
+			{"usage", []string{"end-user-sessions", "-org-url", s.URL, "-api-token", s.ApiToken}, cmd.ExitUsage},
+			{"auth", []string{"list-groups", "-org-url", s.URL, "-api-token", "revoked"}, cmd.ExitAuth},
+			{"not found", []string{"end-user-sessions", "-user", "john@example.com", "-org-url", s.URL, "-api-token", s.ApiToken}, cmd.ExitNotFound},
+			{"conflict", []string{"create-user", "-email", "jane@example.com", "-fname", "Jane", "-lname", "Doe", "-team", "Ops", "-org-url", s.URL, "-api-token", s.ApiToken}, cmd.ExitConflict},
+			{"rate limited", []string{"user-activity", "-user", "jane@example.com", "-org-url", s.URL, "-api-token", s.ApiToken}, cmd.ExitRateLimited},
+			{"network", []string{"list-groups", "-org-url", unreachable.URL, "-api-token", s.ApiToken}, cmd.ExitNetwork},
+		}
+		for _, tc := range testCases {
+			buf := &bytes.Buffer{}
+			if code := runCLI(tc.args, buf); code != tc.expected {
+				t.Errorf("%s: expected exit status %d, received %d:\n%s", tc.name, tc.expected, code, buf)
+			}
+		}
+	})
+	t.Run("completion offers group names and email IDs used earlier", func(t *testing.T) {
+		s := oktatest.NewServer()
+		defer s.Close()
+
+		s.AddUser("jane@example.com", "Jane", "Doe", "ACTIVE")
+		s.AddGroup("Quidditch Team")
+		s.AddGroup("Tech")
+
+		if code, out := runAgainst(s, "assign-groups", "-email", "jane@example.com", "-groups", "Tech"); code != cmd.ExitOK {
+			t.Fatalf("Expected exit status 0, received %d:\n%s", code, out)
+		}
+
+		line := "okta-admin assign-groups -org-url " + s.URL + " -api-token " + s.ApiToken
+		for _, tc := range []struct{ line, expected string }{
+			{line + " -groups q", "\"Quidditch Team\"\n"},
+			{line + " -user j", "jane@example.com\n"},
+		} {
+			buf := &bytes.Buffer{}
+			if code := runCLI([]string{cmd.CompleteCommandName, "--", tc.line}, buf); code != cmd.ExitOK || buf.String() != tc.expected {
+				t.Errorf("Expected completions of %q to be %q, received %q (exit status %d)", tc.line, tc.expected, buf, code)
+			}
+		}
+	})
+	t.Run("-cache saves fetching groups and users until cleared", func(t *testing.T) {
+		s := oktatest.NewServer()
+		defer s.Close()
+
+		u := s.AddUser("jane@example.com", "Jane", "Doe", "ACTIVE")
+		g := s.AddGroup("Tech")
+
+		// The groups of the user are fetched by every run, to journal
+		// the memberships it changes, so they aren't counted
+		fetches := func() (n int) {
+			for _, r := range s.Requests() {
+				if strings.HasPrefix(r, "GET /api/v1/groups") || strings.HasPrefix(r, "GET /api/v1/users") && !strings.HasSuffix(r, "/groups") {
+					n++
+				}
+			}
+			return n
+		}
+
+		for i := 0; i < 2; i++ {
+			if code, out := runAgainst(s, "assign-groups", "-cache", "-user", "jane@example.com", "-groups", "Tech"); code != cmd.ExitOK {
+				t.Fatalf("Expected exit status 0, received %d:\n%s", code, out)
+			}
+		}
+		if n := fetches(); n != 2 {
+			t.Errorf("Expected the user and groups to be fetched once, requests made: %v", s.Requests())
+		}
+		if m := s.GroupMembers(g.Id); len(m) != 1 || m[0] != u.Id {
+			t.Errorf("Expected user to be a member of Tech, members: %v", m)
+		}
+
+		if code, out := runAgainst(s, "cache", "clear"); code != cmd.ExitOK || !strings.Contains(out, "Cleared the data cached for "+s.URL) {
+			t.Fatalf("Expected cache to be cleared, received %d:\n%s", code, out)
+		}
+		if code, out := runAgainst(s, "assign-groups", "-cache", "-user", "jane@example.com", "-groups", "Tech"); code != cmd.ExitOK {
+			t.Fatalf("Expected exit status 0, received %d:\n%s", code, out)
+		}
+		if n := fetches(); n != 4 {
+			t.Errorf("Expected the user and groups to be fetched again once the cache was cleared, requests made: %v", s.Requests())
+		}
+	})
+
+	t.Run("changes are recorded in the audit log", func(t *testing.T) {
+		s := oktatest.NewServer()
+		defer s.Close()
+
+		dir, err := ioutil.TempDir("", "okta-admin-audit")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "audit.jsonl")
+
+		u := s.AddUser("jane@example.com", "Jane", "Doe", "ACTIVE")
+		if code, out := runAgainst(s, "deactivate-user", "-audit-log", path, "-user", "jane@example.com"); code != cmd.ExitOK {
+			t.Fatalf("Expected exit status 0, received %d:\n%s", code, out)
+		}
+		if code, out := runAgainst(s, "list-groups", "-audit-log", path); code != cmd.ExitOK {
+			t.Fatalf("Expected exit status 0, received %d:\n%s", code, out)
+		}
+
+		code, out := runAgainst(s, "audit-log", "show", "-audit-log", path, "-format", "json")
+		if code != cmd.ExitOK {
+			t.Fatalf("Expected exit status 0, received %d:\n%s", code, out)
+		}
+		var rec struct {
+			OrgUrl     string   `json:"orgUrl"`
+			Command    string   `json:"command"`
+			Args       []string `json:"args"`
+			Targets    []string `json:"targets"`
+			Result     string   `json:"result"`
+			RequestIDs []string `json:"requestIds"`
+		}
+		if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 1 || json.Unmarshal([]byte(lines[0]), &rec) != nil {
+			t.Fatalf("Expected only deactivate-user to be recorded, received:\n%s", out)
+		}
+		if rec.Command != "deactivate-user" || rec.OrgUrl != s.URL || rec.Result != "success" {
+			t.Errorf("Expected the run of deactivate-user to be recorded, received %+v", rec)
+		}
+		if len(rec.Targets) != 1 || rec.Targets[0] != u.Id || len(rec.RequestIDs) != 1 || !strings.HasPrefix(rec.RequestIDs[0], "req") {
+			t.Errorf("Expected the user and the ID of the request deactivating them to be recorded, received %+v", rec)
+		}
+		if strings.Contains(strings.Join(rec.Args, " "), s.ApiToken) {
+			t.Errorf("Expected the API token to be masked, received %q", rec.Args)
+		}
+	})
+
+	t.Run("undo reverts the most recent changes first", func(t *testing.T) {
+		s := oktatest.NewServer()
+		defer s.Close()
+
+		jane := s.AddUser("jane@example.com", "Jane", "Doe", "ACTIVE")
+		john := s.AddUser("john@example.com", "John", "Doe", "ACTIVE")
+		g := s.AddGroup("Engineering")
+		for _, args := range [][]string{
+			{"assign-groups", "-user", "jane@example.com", "-groups", "Engineering"},
+			{"deactivate-user", "-user", "john@example.com"},
+		} {
+			if code, out := runAgainst(s, args...); code != cmd.ExitOK {
+				t.Fatalf("Expected exit status 0 for %q, received %d:\n%s", args, code, out)
+			}
+		}
+
+		if code, out := runAgainst(s, "undo", "-last", "-yes"); code != cmd.ExitOK {
+			t.Fatalf("Expected exit status 0, received %d:\n%s", code, out)
+		}
+		if status := s.User(john.Id).Status; status != "PROVISIONED" {
+			t.Errorf("Expected deactivated user to be activated again, status is %s", status)
+		}
+		if members := s.GroupMembers(g.Id); len(members) != 1 || members[0] != jane.Id {
+			t.Errorf("Expected the group membership to be left alone, members are %v", members)
+		}
+
+		if code, out := runAgainst(s, "undo", "-last", "-yes"); code != cmd.ExitOK {
+			t.Fatalf("Expected exit status 0, received %d:\n%s", code, out)
+		}
+		if members := s.GroupMembers(g.Id); len(members) != 0 {
+			t.Errorf("Expected user to be removed from the group, members are %v", members)
+		}
+		if code, out := runAgainst(s, "undo", "-last", "-yes"); code != cmd.ExitNotFound {
+			t.Errorf("Expected exit status %d once everything is undone, received %d:\n%s", cmd.ExitNotFound, code, out)
+		}
+	})
+
+	t.Run("login stores the token used by later commands", func(t *testing.T) {
+		s := oktatest.NewServer()
+		defer s.Close()
+		s.AddGroup("Engineering")
+
+		dir, err := ioutil.TempDir("", "okta-admin-login")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		tokenFile := filepath.Join(dir, "token")
+		if err := ioutil.WriteFile(tokenFile, []byte(s.ApiToken+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		run := func(args ...string) (int, string) {
+			out := &bytes.Buffer{}
+			return runCLI(append(args, "-org-url", s.URL), out), out.String()
+		}
+		if code, out := run("login", "-api-token-file", tokenFile); code != cmd.ExitOK {
+			t.Fatalf("Expected exit status 0, received %d:\n%s", code, out)
+		}
+		if code, out := run("list-groups"); code != cmd.ExitOK || !strings.Contains(out, "Engineering") {
+			t.Errorf("Expected groups to be listed with the stored token, received %d:\n%s", code, out)
+		}
+
+		if code, out := run("logout"); code != cmd.ExitOK {
+			t.Fatalf("Expected exit status 0, received %d:\n%s", code, out)
+		}
+		if code, out := run("list-groups"); code != cmd.ExitUsage || !strings.Contains(out, "api-token is required") {
+			t.Errorf("Expected the token to be required once logged out, received %d:\n%s", code, out)
+		}
+	})
+}
@@ -3,6 +3,9 @@ module github.com/duaraghav8/okta-admin
 go 1.13
 
 require (
+	github.com/godbus/dbus/v5 v5.0.6
 	github.com/mitchellh/cli v1.0.0
 	github.com/okta/okta-sdk-golang v1.0.1
+	github.com/zalando/go-keyring v0.2.1
+	golang.org/x/crypto v0.0.0-20210421170649-83a5a9bb288b
 )
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"io"
 	"log"
 	"os"
 
@@ -10,11 +11,17 @@ import (
 )
 
 func main() {
-	logger := log.New(os.Stdout, "", 0)
+	os.Exit(runCLI(os.Args[1:], os.Stdout))
+}
+
+// runCLI runs the command specified by args, writing its output to
+// out, and returns the exit status.
+func runCLI(args []string, out io.Writer) int {
+	logger := log.New(out, "", 0)
 	meta, err := createMeta()
 	if err != nil {
 		logger.Printf("Failed to create metadata for actions: %v\n", err)
-		os.Exit(1)
+		return 1
 	}
 
 	globalCommand := &cmd.Command{
@@ -22,37 +29,135 @@ func main() {
 		Logger: logger,
 	}
 
+	commands := map[string]cli.CommandFactory{
+		"create-user": func() (command cli.Command, err error) {
+			return &cmd.CreateUserCommand{Command: globalCommand}, nil
+		},
+		"deactivate-user": func() (command cli.Command, err error) {
+			return &cmd.DeactivateUserCommand{Command: globalCommand}, nil
+		},
+		"reset-user-password": func() (command cli.Command, err error) {
+			return &cmd.ResetUserPasswordCommand{Command: globalCommand}, nil
+		},
+		"reset-user-mfa": func() (command cli.Command, err error) {
+			return &cmd.ResetUserMultifactorsCommand{Command: globalCommand}, nil
+		},
+		"list-groups": func() (command cli.Command, err error) {
+			return &cmd.ListGroupsCommand{Command: globalCommand}, nil
+		},
+		"assign-groups": func() (command cli.Command, err error) {
+			return &cmd.AssignUserGroupsCommand{Command: globalCommand}, nil
+		},
+		"audit-admins": func() (command cli.Command, err error) {
+			return &cmd.AuditAdminsCommand{Command: globalCommand}, nil
+		},
+		"logs": func() (command cli.Command, err error) {
+			return &cmd.LogsCommand{Command: globalCommand}, nil
+		},
+		"export-logs": func() (command cli.Command, err error) {
+			return &cmd.ExportLogsCommand{Command: globalCommand}, nil
+		},
+		"user-activity": func() (command cli.Command, err error) {
+			return &cmd.UserActivityCommand{Command: globalCommand}, nil
+		},
+		"list-group-rules": func() (command cli.Command, err error) {
+			return &cmd.ListGroupRulesCommand{Command: globalCommand}, nil
+		},
+		"show-group-rule": func() (command cli.Command, err error) {
+			return &cmd.ShowGroupRuleCommand{Command: globalCommand}, nil
+		},
+		"create-group-rule": func() (command cli.Command, err error) {
+			return &cmd.CreateGroupRuleCommand{Command: globalCommand}, nil
+		},
+		"activate-group-rule": func() (command cli.Command, err error) {
+			return &cmd.ActivateGroupRuleCommand{Command: globalCommand}, nil
+		},
+		"deactivate-group-rule": func() (command cli.Command, err error) {
+			return &cmd.DeactivateGroupRuleCommand{Command: globalCommand}, nil
+		},
+		"delete-group-rule": func() (command cli.Command, err error) {
+			return &cmd.DeleteGroupRuleCommand{Command: globalCommand}, nil
+		},
+		"test-group-rule": func() (command cli.Command, err error) {
+			return &cmd.TestGroupRuleCommand{Command: globalCommand}, nil
+		},
+		"list-policies": func() (command cli.Command, err error) {
+			return &cmd.ListPoliciesCommand{Command: globalCommand}, nil
+		},
+		"show-policy": func() (command cli.Command, err error) {
+			return &cmd.ShowPolicyCommand{Command: globalCommand}, nil
+		},
+		"backup-policies": func() (command cli.Command, err error) {
+			return &cmd.BackupPoliciesCommand{Command: globalCommand}, nil
+		},
+		"restore-policies": func() (command cli.Command, err error) {
+			return &cmd.RestorePoliciesCommand{Command: globalCommand}, nil
+		},
+		"end-user-sessions": func() (command cli.Command, err error) {
+			return &cmd.EndUserSessionsCommand{Command: globalCommand}, nil
+		},
+		"show-session": func() (command cli.Command, err error) {
+			return &cmd.ShowSessionCommand{Command: globalCommand}, nil
+		},
+		"end-session": func() (command cli.Command, err error) {
+			return &cmd.EndSessionCommand{Command: globalCommand}, nil
+		},
+	}
+	commands["shell"] = func() (command cli.Command, err error) {
+		return &cmd.ShellCommand{Command: globalCommand, Commands: commands}, nil
+	}
+	commands["cache"] = func() (command cli.Command, err error) {
+		return &cmd.CacheCommand{Command: globalCommand}, nil
+	}
+	commands["cache clear"] = func() (command cli.Command, err error) {
+		return &cmd.CacheClearCommand{Command: globalCommand}, nil
+	}
+	commands["audit-log"] = func() (command cli.Command, err error) {
+		return &cmd.AuditLogCommand{Command: globalCommand}, nil
+	}
+	commands["audit-log show"] = func() (command cli.Command, err error) {
+		return &cmd.AuditLogShowCommand{Command: globalCommand}, nil
+	}
+	commands["login"] = func() (command cli.Command, err error) {
+		return &cmd.LoginCommand{Command: globalCommand}, nil
+	}
+	commands["logout"] = func() (command cli.Command, err error) {
+		return &cmd.LogoutCommand{Command: globalCommand}, nil
+	}
+	commands["undo"] = func() (command cli.Command, err error) {
+		return &cmd.UndoCommand{Command: globalCommand}, nil
+	}
+	commands["completion"] = func() (command cli.Command, err error) {
+		return &cmd.CompletionCommand{Command: globalCommand}, nil
+	}
+	commands[cmd.CompleteCommandName] = func() (command cli.Command, err error) {
+		return &cmd.CompleteCommand{Command: globalCommand, Commands: commands}, nil
+	}
+
 	c := cli.CLI{
-		Name:    version.AppName,
-		Version: version.FormattedVersion(),
-		Commands: map[string]cli.CommandFactory{
-			"create-user": func() (command cli.Command, err error) {
-				return &cmd.CreateUserCommand{Command: globalCommand}, nil
-			},
-			"deactivate-user": func() (command cli.Command, err error) {
-				return &cmd.DeactivateUserCommand{Command: globalCommand}, nil
-			},
-			"reset-user-password": func() (command cli.Command, err error) {
-				return &cmd.ResetUserPasswordCommand{Command: globalCommand}, nil
-			},
-			"reset-user-mfa": func() (command cli.Command, err error) {
-				return &cmd.ResetUserMultifactorsCommand{Command: globalCommand}, nil
-			},
-			"list-groups": func() (command cli.Command, err error) {
-				return &cmd.ListGroupsCommand{Command: globalCommand}, nil
-			},
-			"assign-groups": func() (command cli.Command, err error) {
-				return &cmd.AssignUserGroupsCommand{Command: globalCommand}, nil
-			},
-		},
-		Args:       os.Args[1:],
-		HelpWriter: os.Stdout,
+		Name:           version.AppName,
+		Version:        version.FormattedVersion(),
+		Commands:       commands,
+		HiddenCommands: []string{cmd.CompleteCommandName},
+		Args:           args,
+		HelpWriter:     out,
 	}
 
 	exitStatus, err := c.Run()
 	if err != nil {
 		logger.Println(err)
 	}
+	// Email IDs used successfully are offered as completions later
+	if exitStatus == cmd.ExitOK && !c.IsHelp() && c.Subcommand() != cmd.CompleteCommandName {
+		globalCommand.RecordRecentUsers(c.SubcommandArgs())
+	}
+	// Changes made to the organization are recorded in the audit log,
+	// and those which can be undone in the journal
+	if !c.IsHelp() {
+		globalCommand.RecordAudit(c.Subcommand(), c.SubcommandArgs(), exitStatus)
+		globalCommand.RecordJournal(c.Subcommand(), c.SubcommandArgs())
+	}
+	globalCommand.EndOperation()
 
-	os.Exit(exitStatus)
+	return exitStatus
 }
@@ -4,8 +4,34 @@ import (
 	"flag"
 	"github.com/duaraghav8/okta-admin/command"
 	"os"
+	"strconv"
 )
 
+// apiTokenFileValue is the value of -api-token-file, which reads
+// the API token from the file once it's specified.
+type apiTokenFileValue struct {
+	token *string
+	path  string
+}
+
+func (v *apiTokenFileValue) String() string {
+	return v.path
+}
+
+func (v *apiTokenFileValue) Set(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	token, err := command.ReadApiToken(f)
+	if err != nil {
+		return err
+	}
+	v.path, *v.token = path, token
+	return nil
+}
+
 // createMeta returns the Metadata object to be passed to
 // all actions. None of the global options are treated as
 // required. Checking for emptiness of an option and
@@ -19,6 +45,17 @@ func createMeta() (*command.Metadata, error) {
 
 	flags.StringVar(&globalOpts.OrgUrl, "org-url", os.Getenv("OKTA_ORG_URL"), "")
 	flags.StringVar(&globalOpts.ApiToken, "api-token", os.Getenv("OKTA_API_TOKEN"), "")
+	flags.Var(&apiTokenFileValue{token: &globalOpts.ApiToken}, "api-token-file", "")
+	flags.BoolVar(&globalOpts.ApiTokenStdin, "api-token-stdin", false, "")
+	// The token stored by login is used if none is specified
+	globalOpts.ApiTokenLookup = command.StoredApiToken
+	flags.DurationVar(&globalOpts.Timeout, "timeout", 0, "")
+	cache, _ := strconv.ParseBool(os.Getenv("OKTA_ADMIN_CACHE"))
+	flags.BoolVar(&globalOpts.Cache, "cache", cache, "")
+	flags.BoolVar(&globalOpts.NoCache, "no-cache", false, "")
+	flags.StringVar(&globalOpts.AuditLog, "audit-log", "", "")
+	flags.StringVar(&globalOpts.AuditSyslog, "audit-syslog", os.Getenv("OKTA_ADMIN_AUDIT_SYSLOG"), "")
+	flags.StringVar(&globalOpts.AuditWebhook, "audit-webhook", os.Getenv("OKTA_ADMIN_AUDIT_WEBHOOK"), "")
 
 	meta = command.Metadata{
 		FlagSet:       flags,
@@ -29,6 +66,29 @@ Global Options:
              This can also be specified via the OKTA_ORG_URL environment variable.
   -api-token Token to authenticate with Okta API
              This can also be specified via the OKTA_API_TOKEN environment variable.
+             If neither is specified, the token stored by login is used.
+  -api-token-file
+             Read the API token from this file instead.
+  -api-token-stdin
+             Read the API token from the first line of standard input instead.
+  -timeout   Maximum time the whole operation may take, eg- 30s or 5m.
+             The operation is aborted once it elapses. (Default: no limit)
+  -cache     Cache the organization's groups for 10 minutes and the IDs
+             users are resolved to for an hour, on disk, so that commands
+             run one after another don't fetch them each time.
+             This can also be enabled via the OKTA_ADMIN_CACHE environment variable.
+  -no-cache  Don't use the cache, even if OKTA_ADMIN_CACHE is set.
+  -audit-log Path of the log which commands changing the organization
+             append a record to. (Default: audit.jsonl in the okta-admin
+             directory of the user's configuration directory)
+             This can also be specified via the OKTA_ADMIN_AUDIT_LOG environment variable.
+  -audit-syslog
+             Forward audit records to syslog, either the local daemon with
+             "local" or a remote one with a URL like udp://host:514.
+             This can also be specified via the OKTA_ADMIN_AUDIT_SYSLOG environment variable.
+  -audit-webhook
+             URL audit records are POSTed to as JSON.
+             This can also be specified via the OKTA_ADMIN_AUDIT_WEBHOOK environment variable.
 `,
 	}
 
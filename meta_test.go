@@ -2,6 +2,7 @@ package main
 
 import (
 	"os"
+	"strconv"
 	"testing"
 )
 
@@ -24,6 +25,12 @@ func testMetaGlobalOptValues(t *testing.T, args []string, expected map[string]st
 	if meta.GlobalOptions.ApiToken != expected["api_token"] {
 		t.Errorf("API token: expected %s, received %s", expected["api_token"], meta.GlobalOptions.ApiToken)
 	}
+	if meta.GlobalOptions.Timeout.String() != expected["timeout"] {
+		t.Errorf("Timeout: expected %s, received %s", expected["timeout"], meta.GlobalOptions.Timeout)
+	}
+	if cache := strconv.FormatBool(meta.GlobalOptions.Cache && !meta.GlobalOptions.NoCache); cache != expected["cache"] {
+		t.Errorf("Cache: expected %s, received %s", expected["cache"], cache)
+	}
 }
 
 func TestCreateMeta(t *testing.T) {
@@ -35,6 +42,8 @@ func TestCreateMeta(t *testing.T) {
 	expected := map[string]string{
 		"org_url":   orgUrl,
 		"api_token": apiToken,
+		"timeout":   "0s",
+		"cache":     "false",
 	}
 
 	t.Run("parses global options supplied as args", func(t *testing.T) {
@@ -42,8 +51,15 @@ func TestCreateMeta(t *testing.T) {
 		args := []string{
 			"-org-url", orgUrl,
 			"-api-token", apiToken,
+			"-timeout", "1m30s",
+			"-cache",
 		}
-		testMetaGlobalOptValues(t, args, expected)
+		testMetaGlobalOptValues(t, args, map[string]string{
+			"org_url":   orgUrl,
+			"api_token": apiToken,
+			"timeout":   "1m30s",
+			"cache":     "true",
+		})
 	})
 
 	// This test shouldn't be run in parallel. Because it manipulates
@@ -64,7 +80,23 @@ func TestCreateMeta(t *testing.T) {
 
 		testMetaGlobalOptValues(t, []string{}, expected)
 
+		// The cache enabled via the environment can be disabled
+		originalEnvCache := os.Getenv("OKTA_ADMIN_CACHE")
+		if err := os.Setenv("OKTA_ADMIN_CACHE", "true"); err != nil {
+			t.Fatal("Unable to set cache env var")
+		}
+		testMetaGlobalOptValues(t, []string{}, map[string]string{
+			"org_url":   orgUrl,
+			"api_token": apiToken,
+			"timeout":   "0s",
+			"cache":     "true",
+		})
+		testMetaGlobalOptValues(t, []string{"-no-cache"}, expected)
+
 		// cleanup
+		if err := os.Setenv("OKTA_ADMIN_CACHE", originalEnvCache); err != nil {
+			t.Fatal("Unable to set cache env var back to original value")
+		}
 		if err := os.Setenv("OKTA_ORG_URL", originalEnvOrgUrl); err != nil {
 			t.Fatal("Unable to set org url env var back to original value")
 		}
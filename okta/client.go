@@ -0,0 +1,268 @@
+package okta
+
+import (
+	"context"
+	"github.com/okta/okta-sdk-golang/okta"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"net/http"
+)
+
+// Client contains the Okta API operations used by okta-admin.
+// Operations supported by the SDK mirror the signatures of their
+// SDK counterparts, while the rest are made directly against the
+// API. Commands depend on this interface rather than on the SDK,
+// so that they can be run against fakes in tests.
+type Client interface {
+	// Users
+	GetUser(idOrLogin string) (*User, *http.Response, error)
+	ListUsers(qp *query.Params) ([]*okta.User, *okta.Response, error)
+	CreateUser(user okta.User, qp *query.Params) (*okta.User, *okta.Response, error)
+	DeactivateUser(userID string, qp *query.Params) (*okta.Response, error)
+	ActivateUser(userID string, qp *query.Params) (*okta.UserActivationToken, *okta.Response, error)
+	ResetPassword(userID string, qp *query.Params) (*okta.ResetPasswordToken, *okta.Response, error)
+	ListAssignedRoles(userID string, qp *query.Params) ([]*okta.Role, *okta.Response, error)
+	ListGroupTargetsForRole(userID, roleID string, qp *query.Params) ([]*okta.Group, *okta.Response, error)
+	ListUserGroups(userID string, qp *query.Params) ([]*okta.Group, *okta.Response, error)
+
+	// Factors
+	ListUserFactors(userID string) ([]*Factor, *http.Response, error)
+	ResetAllFactors(userID string) (*okta.Response, error)
+
+	// Groups
+	ListGroups(qp *query.Params) ([]*okta.Group, *okta.Response, error)
+	GetGroup(groupID string, qp *query.Params) (*okta.Group, *okta.Response, error)
+	ListGroupUsers(groupID string, qp *query.Params) ([]*okta.User, *okta.Response, error)
+	AddUserToGroup(groupID, userID string) (*okta.Response, error)
+	RemoveUserFromGroup(groupID, userID string) (*okta.Response, error)
+
+	// Group rules
+	ListGroupRules(qp *query.Params) ([]*okta.GroupRule, *okta.Response, error)
+	GetGroupRule(ruleID string, qp *query.Params) (*okta.GroupRule, *okta.Response, error)
+	CreateGroupRule(rule okta.GroupRule) (*okta.GroupRule, *okta.Response, error)
+	ActivateGroupRule(ruleID string) (*okta.Response, error)
+	DeactivateGroupRule(ruleID string) (*okta.Response, error)
+	DeleteGroupRule(ruleID string, qp *query.Params) (*okta.Response, error)
+
+	// Logs
+	GetLogs(qp *query.Params) ([]*LogEvent, *http.Response, error)
+
+	// Policies
+	ListPolicies(qp *query.Params) ([]*Policy, *http.Response, error)
+	GetPolicy(policyID string) (*Policy, *http.Response, error)
+	CreatePolicy(body interface{}, activate bool) (*Policy, *http.Response, error)
+	UpdatePolicy(policyID string, body interface{}) (*http.Response, error)
+	ActivatePolicy(policyID string) (*okta.Response, error)
+	DeactivatePolicy(policyID string) (*okta.Response, error)
+	ListPolicyRules(policyID string) ([]*PolicyRule, *http.Response, error)
+	CreatePolicyRule(policyID string, body interface{}, activate bool) (*http.Response, error)
+	UpdatePolicyRule(policyID, ruleID string, body interface{}) (*http.Response, error)
+	ActivatePolicyRule(policyID, ruleID string) (*okta.Response, error)
+	DeactivatePolicyRule(policyID, ruleID string) (*okta.Response, error)
+
+	// Sessions
+	GetSession(sessionID string) (*Session, *http.Response, error)
+	EndSession(sessionID string) (*okta.Response, error)
+	EndAllUserSessions(userID string, qp *query.Params) (*okta.Response, error)
+}
+
+// sdkClient implements Client using the Okta SDK, falling back to
+// direct API requests for operations the SDK doesn't support.
+// Errors returned by the SDK for error responses of the API are
+// converted into ApiErrors, like those of direct requests.
+type sdkClient struct {
+	sdk   *okta.Client
+	creds *Credentials
+}
+
+// NewClient returns a Client which authenticates using the
+// credentials supplied to it. Requests made via the SDK use the
+// credentials' HTTP client too, if set.
+func NewClient(c *Credentials) (Client, error) {
+	// The SDK doesn't invalidate cached resources when they are
+	// changed via other endpoints, and a client may outlive many
+	// operations in the shell, so responses aren't cached.
+	opts := []okta.ConfigSetter{okta.WithOrgUrl(c.OrgUrl), okta.WithToken(c.ApiToken), okta.WithCache(false)}
+	if c.HttpClient != nil {
+		opts = append(opts, okta.WithHttpClient(*c.HttpClient))
+	}
+
+	sdk, err := okta.NewClient(context.Background(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &sdkClient{sdk: sdk, creds: c}, nil
+}
+
+func (s *sdkClient) GetUser(idOrLogin string) (*User, *http.Response, error) {
+	return GetUser(s.creds, idOrLogin)
+}
+
+func (s *sdkClient) ListUsers(qp *query.Params) ([]*okta.User, *okta.Response, error) {
+	res, resp, err := s.sdk.User.ListUsers(qp)
+	return res, resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) CreateUser(user okta.User, qp *query.Params) (*okta.User, *okta.Response, error) {
+	res, resp, err := s.sdk.User.CreateUser(user, qp)
+	return res, resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) DeactivateUser(userID string, qp *query.Params) (*okta.Response, error) {
+	resp, err := s.sdk.User.DeactivateUser(userID, qp)
+	return resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) ActivateUser(userID string, qp *query.Params) (*okta.UserActivationToken, *okta.Response, error) {
+	res, resp, err := s.sdk.User.ActivateUser(userID, qp)
+	return res, resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) ResetPassword(userID string, qp *query.Params) (*okta.ResetPasswordToken, *okta.Response, error) {
+	res, resp, err := s.sdk.User.ResetPassword(userID, qp)
+	return res, resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) ListAssignedRoles(userID string, qp *query.Params) ([]*okta.Role, *okta.Response, error) {
+	res, resp, err := s.sdk.User.ListAssignedRoles(userID, qp)
+	return res, resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) ListGroupTargetsForRole(userID, roleID string, qp *query.Params) ([]*okta.Group, *okta.Response, error) {
+	res, resp, err := s.sdk.User.ListGroupTargetsForRole(userID, roleID, qp)
+	return res, resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) ListUserGroups(userID string, qp *query.Params) ([]*okta.Group, *okta.Response, error) {
+	res, resp, err := s.sdk.User.ListUserGroups(userID, qp)
+	return res, resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) ListUserFactors(userID string) ([]*Factor, *http.Response, error) {
+	return ListUserFactors(s.creds, userID)
+}
+
+func (s *sdkClient) ResetAllFactors(userID string) (*okta.Response, error) {
+	resp, err := s.sdk.User.ResetAllFactors(userID)
+	return resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) ListGroups(qp *query.Params) ([]*okta.Group, *okta.Response, error) {
+	res, resp, err := s.sdk.Group.ListGroups(qp)
+	return res, resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) GetGroup(groupID string, qp *query.Params) (*okta.Group, *okta.Response, error) {
+	res, resp, err := s.sdk.Group.GetGroup(groupID, qp)
+	return res, resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) ListGroupUsers(groupID string, qp *query.Params) ([]*okta.User, *okta.Response, error) {
+	res, resp, err := s.sdk.Group.ListGroupUsers(groupID, qp)
+	return res, resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) AddUserToGroup(groupID, userID string) (*okta.Response, error) {
+	resp, err := s.sdk.Group.AddUserToGroup(groupID, userID)
+	return resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) RemoveUserFromGroup(groupID, userID string) (*okta.Response, error) {
+	resp, err := s.sdk.Group.RemoveGroupUser(groupID, userID)
+	return resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) ListGroupRules(qp *query.Params) ([]*okta.GroupRule, *okta.Response, error) {
+	res, resp, err := s.sdk.Group.ListRules(qp)
+	return res, resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) GetGroupRule(ruleID string, qp *query.Params) (*okta.GroupRule, *okta.Response, error) {
+	res, resp, err := s.sdk.Group.GetRule(ruleID, qp)
+	return res, resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) CreateGroupRule(rule okta.GroupRule) (*okta.GroupRule, *okta.Response, error) {
+	res, resp, err := s.sdk.Group.CreateRule(rule)
+	return res, resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) ActivateGroupRule(ruleID string) (*okta.Response, error) {
+	resp, err := s.sdk.Group.ActivateRule(ruleID)
+	return resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) DeactivateGroupRule(ruleID string) (*okta.Response, error) {
+	resp, err := s.sdk.Group.DeactivateRule(ruleID)
+	return resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) DeleteGroupRule(ruleID string, qp *query.Params) (*okta.Response, error) {
+	resp, err := s.sdk.Group.DeleteRule(ruleID, qp)
+	return resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) GetLogs(qp *query.Params) ([]*LogEvent, *http.Response, error) {
+	return GetLogs(s.creds, qp)
+}
+
+func (s *sdkClient) ListPolicies(qp *query.Params) ([]*Policy, *http.Response, error) {
+	return ListPolicies(s.creds, qp)
+}
+
+func (s *sdkClient) GetPolicy(policyID string) (*Policy, *http.Response, error) {
+	return GetPolicy(s.creds, policyID)
+}
+
+func (s *sdkClient) CreatePolicy(body interface{}, activate bool) (*Policy, *http.Response, error) {
+	return CreatePolicy(s.creds, body, activate)
+}
+
+func (s *sdkClient) UpdatePolicy(policyID string, body interface{}) (*http.Response, error) {
+	return UpdatePolicy(s.creds, policyID, body)
+}
+
+func (s *sdkClient) ActivatePolicy(policyID string) (*okta.Response, error) {
+	resp, err := s.sdk.Policy.ActivatePolicy(policyID)
+	return resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) DeactivatePolicy(policyID string) (*okta.Response, error) {
+	resp, err := s.sdk.Policy.DeactivatePolicy(policyID)
+	return resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) ListPolicyRules(policyID string) ([]*PolicyRule, *http.Response, error) {
+	return ListPolicyRules(s.creds, policyID)
+}
+
+func (s *sdkClient) CreatePolicyRule(policyID string, body interface{}, activate bool) (*http.Response, error) {
+	return CreatePolicyRule(s.creds, policyID, body, activate)
+}
+
+func (s *sdkClient) UpdatePolicyRule(policyID, ruleID string, body interface{}) (*http.Response, error) {
+	return UpdatePolicyRule(s.creds, policyID, ruleID, body)
+}
+
+func (s *sdkClient) ActivatePolicyRule(policyID, ruleID string) (*okta.Response, error) {
+	resp, err := s.sdk.Policy.ActivatePolicyRule(policyID, ruleID)
+	return resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) DeactivatePolicyRule(policyID, ruleID string) (*okta.Response, error) {
+	resp, err := s.sdk.Policy.DeactivatePolicyRule(policyID, ruleID)
+	return resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) GetSession(sessionID string) (*Session, *http.Response, error) {
+	return GetSession(s.creds, sessionID)
+}
+
+func (s *sdkClient) EndSession(sessionID string) (*okta.Response, error) {
+	resp, err := s.sdk.Session.EndSession(sessionID)
+	return resp, sdkError(resp, err)
+}
+
+func (s *sdkClient) EndAllUserSessions(userID string, qp *query.Params) (*okta.Response, error) {
+	resp, err := s.sdk.User.EndAllUserSessions(userID, qp)
+	return resp, sdkError(resp, err)
+}
@@ -0,0 +1,150 @@
+package okta
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/okta/okta-sdk-golang/okta"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error codes of common Okta API errors
+const (
+	ErrorCodeValidation     = "E0000001"
+	ErrorCodeForbidden      = "E0000006"
+	ErrorCodeNotFound       = "E0000007"
+	ErrorCodeInternal       = "E0000009"
+	ErrorCodeReadOnly       = "E0000010"
+	ErrorCodeInvalidToken   = "E0000011"
+	ErrorCodePasswordPolicy = "E0000080"
+	ErrorCodeAlreadyActive  = "E0000016"
+	ErrorCodeInvalidStatus  = "E0000038"
+	ErrorCodeRateLimited    = "E0000047"
+	ErrorCodeInvalidSearch  = "E0000031"
+)
+
+// errorHints contains advice on how to resolve common errors,
+// keyed by their error codes.
+var errorHints = map[string]string{
+	ErrorCodeValidation:     "Okta rejected the values in the request. Correct the fields named in the error and retry.",
+	ErrorCodeForbidden:      "The administrator who owns the API token lacks permission for this operation. Use a token of an administrator with a suitable role.",
+	ErrorCodeNotFound:       "Check that the ID, login or name is correct and belongs to this organization.",
+	ErrorCodeInternal:       "Okta failed to handle the request. Retry later and quote the errorId to Okta support if it keeps failing.",
+	ErrorCodeReadOnly:       "The organization is in read-only mode, usually during Okta maintenance. Retry later.",
+	ErrorCodeInvalidToken:   "Check the token supplied via -api-token or OKTA_API_TOKEN, and that -org-url is the organization it was created in. Tokens expire after 30 days without use.",
+	ErrorCodePasswordPolicy: "The password doesn't satisfy the organization's password policy.",
+	ErrorCodeAlreadyActive:  "The user is already active.",
+	ErrorCodeInvalidStatus:  "The operation isn't allowed in the user's current status, eg- deprovisioned users must be reactivated first.",
+	ErrorCodeRateLimited:    "The organization's API rate limit was exceeded. Retry once it resets, or lower -parallelism.",
+	ErrorCodeInvalidSearch:  "The filter or search expression is invalid.",
+}
+
+// statusHints contains advice for errors whose code is unknown,
+// keyed by their HTTP status code.
+var statusHints = map[int]string{
+	http.StatusUnauthorized:    errorHints[ErrorCodeInvalidToken],
+	http.StatusForbidden:       errorHints[ErrorCodeForbidden],
+	http.StatusNotFound:        errorHints[ErrorCodeNotFound],
+	http.StatusTooManyRequests: errorHints[ErrorCodeRateLimited],
+}
+
+// ApiError is an error response of the Okta API. Okta describes
+// errors with a code, a summary and, for validation errors, their
+// causes. The error ID identifies the failed request and should be
+// quoted in support tickets.
+type ApiError struct {
+	StatusCode int
+	Status     string
+	Code       string
+	Summary    string
+	Causes     []string
+	Id         string
+
+	// RateLimitReset is the time the rate limit resets at, if the
+	// request was rate limited.
+	RateLimitReset time.Time
+}
+
+// newApiError creates an ApiError from a response of the API and
+// the Okta error decoded from its body, which may be nil if the
+// body didn't contain one.
+func newApiError(resp *http.Response, e *okta.Error) *ApiError {
+	apiErr := &ApiError{StatusCode: resp.StatusCode, Status: resp.Status}
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-Rate-Limit-Reset"), 10, 64); err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		apiErr.RateLimitReset = time.Unix(reset, 0)
+	}
+	if e == nil {
+		return apiErr
+	}
+
+	apiErr.Code, apiErr.Summary, apiErr.Id = e.ErrorCode, e.ErrorSummary, e.ErrorId
+	for _, cause := range e.ErrorCauses {
+		if s, ok := cause["errorSummary"].(string); ok {
+			apiErr.Causes = append(apiErr.Causes, s)
+			continue
+		}
+		for k, v := range cause {
+			apiErr.Causes = append(apiErr.Causes, fmt.Sprintf("%s: %v", k, v))
+		}
+	}
+	return apiErr
+}
+
+// decodeApiError creates an ApiError from an error response of
+// the API, reading the Okta error from its body.
+func decodeApiError(resp *http.Response) *ApiError {
+	var e okta.Error
+	if json.NewDecoder(resp.Body).Decode(&e) != nil || (e.ErrorCode == "" && e.ErrorSummary == "") {
+		return newApiError(resp, nil)
+	}
+	return newApiError(resp, &e)
+}
+
+// sdkError converts errors returned by the SDK for error responses
+// of the API into ApiErrors. Other errors are returned as is.
+func sdkError(resp *okta.Response, err error) error {
+	if e, ok := err.(*okta.Error); ok && resp != nil && resp.Response != nil {
+		return newApiError(resp.Response, e)
+	}
+	return err
+}
+
+func (e *ApiError) Error() string {
+	msg := e.Summary
+	if msg == "" {
+		msg = e.Status
+	}
+	if len(e.Causes) > 0 {
+		msg = fmt.Sprintf("%s; %s", msg, strings.Join(e.Causes, "; "))
+	}
+
+	var details []string
+	if e.Code != "" {
+		details = append(details, e.Code)
+	}
+	if e.Id != "" {
+		details = append(details, "errorId "+e.Id)
+	}
+	if len(details) == 0 {
+		return msg
+	}
+	return fmt.Sprintf("%s (%s)", msg, strings.Join(details, ", "))
+}
+
+// Hint returns advice on how to resolve the error, or an empty
+// string if there's none.
+func (e *ApiError) Hint() string {
+	hint, ok := errorHints[e.Code]
+	if !ok {
+		hint = statusHints[e.StatusCode]
+		if hint == "" && e.StatusCode >= http.StatusInternalServerError {
+			hint = errorHints[ErrorCodeInternal]
+		}
+	}
+	if hint != "" && !e.RateLimitReset.IsZero() {
+		hint = fmt.Sprintf("%s The limit resets at %s.", hint, e.RateLimitReset.Format(time.RFC3339))
+	}
+	return hint
+}
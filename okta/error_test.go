@@ -0,0 +1,90 @@
+package okta
+
+import (
+	"errors"
+	"github.com/okta/okta-sdk-golang/okta"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func errorResponse(statusCode int, body string, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		StatusCode: statusCode,
+		Status:     http.StatusText(statusCode),
+		Header:     header,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestDecodeApiError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with causes", func(t *testing.T) {
+		e := decodeApiError(errorResponse(http.StatusBadRequest, `{
+			"errorCode": "E0000001",
+			"errorSummary": "Api validation failed: login",
+			"errorLink": "E0000001",
+			"errorId": "oaeZ5Y3tH3tRYGzNiFY1rVmkA",
+			"errorCauses": [{"errorSummary": "login: An object with this field already exists in the current organization"}]
+		}`, nil))
+
+		expected := "Api validation failed: login; login: An object with this field already exists in the current organization (E0000001, errorId oaeZ5Y3tH3tRYGzNiFY1rVmkA)"
+		if e.Error() != expected {
+			t.Errorf("Expected %q, received %q", expected, e.Error())
+		}
+		if e.Hint() != errorHints[ErrorCodeValidation] {
+			t.Errorf("Expected validation hint, received %q", e.Hint())
+		}
+	})
+
+	t.Run("without an Okta error", func(t *testing.T) {
+		e := decodeApiError(errorResponse(http.StatusBadGateway, "<html>Bad Gateway</html>", nil))
+		if e.Error() != "Bad Gateway" {
+			t.Errorf("Expected status as error, received %q", e.Error())
+		}
+		if e.Hint() != errorHints[ErrorCodeInternal] {
+			t.Errorf("Expected hint for server errors, received %q", e.Hint())
+		}
+	})
+
+	t.Run("when rate limited", func(t *testing.T) {
+		reset := time.Date(2019, 10, 1, 10, 0, 0, 0, time.UTC)
+		header := http.Header{}
+		header.Set("X-Rate-Limit-Reset", "1569924000")
+		e := decodeApiError(errorResponse(http.StatusTooManyRequests,
+			`{"errorCode": "E0000047", "errorSummary": "API call exceeded rate limit due to too many requests."}`, header))
+
+		if !e.RateLimitReset.Equal(reset) {
+			t.Errorf("Expected rate limit to reset at %s, received %s", reset, e.RateLimitReset)
+		}
+		if !strings.Contains(e.Hint(), reset.Local().Format(time.RFC3339)) {
+			t.Errorf("Expected hint to contain the reset time, received %q", e.Hint())
+		}
+	})
+}
+
+func TestSdkError(t *testing.T) {
+	t.Parallel()
+
+	resp := &okta.Response{Response: errorResponse(http.StatusUnauthorized, "", nil)}
+	err := sdkError(resp, &okta.Error{ErrorCode: ErrorCodeInvalidToken, ErrorSummary: "Invalid token provided", ErrorId: "oae1"})
+
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an ApiError, received %T", err)
+	}
+	if apiErr.StatusCode != http.StatusUnauthorized || apiErr.Id != "oae1" {
+		t.Errorf("Expected status code and error ID to be set, received %+v", apiErr)
+	}
+
+	other := errors.New("connection refused")
+	if err := sdkError(nil, other); err != other {
+		t.Errorf("Expected other errors to be returned as is, received %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package okta
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Factor represents a factor enrolled by a user. The SDK fails to
+// decode factors because it unmarshals them into an interface
+// type, so only the fields used by the CLI are decoded here.
+type Factor struct {
+	Id         string `json:"id"`
+	FactorType string `json:"factorType"`
+	Provider   string `json:"provider"`
+	Status     string `json:"status"`
+}
+
+// ListUserFactors returns the factors enrolled by the user
+// with the specified ID.
+func ListUserFactors(c *Credentials, userID string) ([]*Factor, *http.Response, error) {
+	var factors []*Factor
+	endpoint := fmt.Sprintf("/api/v1/users/%s/factors", userID)
+
+	resp, err := getResource(c, endpoint, nil, "factors", &factors)
+	if err != nil {
+		return nil, resp, err
+	}
+	return factors, resp, nil
+}
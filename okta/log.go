@@ -0,0 +1,52 @@
+package okta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/okta/okta-sdk-golang/okta"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"net/http"
+	"time"
+)
+
+// LogEvent represents a single event from the Okta System Log.
+// The SDK cannot decode some fields of real log events (eg-
+// credentialType is a string, not an object), so only the fields
+// used by the CLI are decoded. Raw contains the event exactly as
+// returned by the API.
+type LogEvent struct {
+	Uuid           string            `json:"uuid"`
+	EventType      string            `json:"eventType"`
+	DisplayMessage string            `json:"displayMessage"`
+	Severity       string            `json:"severity"`
+	Published      time.Time         `json:"published"`
+	Actor          *okta.LogActor    `json:"actor"`
+	Client         *okta.LogClient   `json:"client"`
+	Outcome        *okta.LogOutcome  `json:"outcome"`
+	Target         []*okta.LogTarget `json:"target"`
+	Raw            json.RawMessage   `json:"-"`
+}
+
+// GetLogs returns a single page of events from the System Log
+// matching the query params supplied to it. Use NextPageCursor
+// on the response to fetch subsequent pages.
+func GetLogs(c *Credentials, qp *query.Params) ([]*LogEvent, *http.Response, error) {
+	var rawEvents []json.RawMessage
+
+	resp, err := getResource(c, "/api/v1/logs", qp, "logs", &rawEvents)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	events := make([]*LogEvent, len(rawEvents), len(rawEvents))
+	for i, raw := range rawEvents {
+		var e LogEvent
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return nil, resp, errors.New(fmt.Sprintf("failed to read log event: %v", err))
+		}
+		e.Raw = raw
+		events[i] = &e
+	}
+	return events, resp, nil
+}
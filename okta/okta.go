@@ -3,39 +3,52 @@
 package okta
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/okta/okta-sdk-golang/okta"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"io"
+	"net/http"
 	"net/url"
-	"path"
+	"strings"
 )
 
-// GenericResult represents a struct that can hold results
-// returned by any okta sdk function that passes response
-// from the upstream API.
-type GenericResult struct {
-	Err  error
-	Resp *okta.Response
-}
-
 // Credentials contains all information required to authenticate
-// to and access an Okta domain.
+// to and access an Okta domain. HttpClient is used to make requests
+// if set, allowing callers to apply timeouts and cancellation.
 type Credentials struct {
 	OrgUrl, ApiToken string
+	HttpClient       *http.Client
 }
 
-// ApiResponse represents an arbitrary JSON response object
-// from a REST API.
-type ApiResponse map[string]interface{}
-
 // CreateRequestUrl creates the URL to call when you
 // want to make an Okta API request to a specific endpoint.
+// Segments of the endpoint may be escaped using url.PathEscape.
+// Segments which are . or .. are rejected, since they would make
+// the request refer to another resource.
 func CreateRequestUrl(orgUrl, endpoint string) (string, error) {
 	u, err := url.Parse(orgUrl)
 	if err != nil {
 		return "", errors.New(fmt.Sprintf("Failed to parse organization url: %v", err))
 	}
-	u.Path = path.Join(u.Path, endpoint)
+	ep, err := url.Parse(endpoint)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("Failed to parse endpoint: %v", err))
+	}
+	for _, seg := range strings.Split(ep.EscapedPath(), "/") {
+		if s, _ := url.PathUnescape(seg); s == "." || s == ".." {
+			return "", errors.New(fmt.Sprintf("Invalid endpoint %s", endpoint))
+		}
+	}
+
+	// The escaped paths are concatenated rather than joined, since
+	// cleaning the unescaped path would resolve dot segments within
+	// escaped segments, eg- of "a/../b"
+	u.RawPath = strings.TrimRight(u.EscapedPath(), "/") + "/" + strings.TrimLeft(ep.EscapedPath(), "/")
+	if u.Path, err = url.PathUnescape(u.RawPath); err != nil {
+		return "", errors.New(fmt.Sprintf("Failed to parse endpoint: %v", err))
+	}
 	return u.String(), nil
 }
 
@@ -48,3 +61,65 @@ func CreateRequestHeaders(apiToken string) map[string]string {
 		"Authorization": fmt.Sprintf("SSWS %s", apiToken),
 	}
 }
+
+// getResource makes a GET request to the specified endpoint of
+// the Okta API and decodes the JSON response into v. An error is
+// returned if the API doesn't respond with 200 OK.
+func getResource(c *Credentials, endpoint string, qp *query.Params, resourceName string, v interface{}) (*http.Response, error) {
+	return sendRequest(c, http.MethodGet, endpoint, qp, nil, "fetch "+resourceName, v)
+}
+
+// sendRequest makes a request to the specified endpoint of the
+// Okta API with the JSON-encoded body, if any, and decodes the
+// JSON response into v, unless v is nil. An error describing the
+// action and wrapping an ApiError is returned if the API doesn't
+// respond with a 2xx status.
+func sendRequest(c *Credentials, method, endpoint string, qp *query.Params, body interface{}, action string, v interface{}) (*http.Response, error) {
+	client := c.HttpClient
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	reqUrl, err := CreateRequestUrl(c.OrgUrl, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if qp != nil {
+		reqUrl += qp.String()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("unable to encode request body: %v", err))
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, reqUrl, reqBody)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to create request: %v", err))
+	}
+
+	for n, v := range CreateRequestHeaders(c.ApiToken) {
+		req.Header.Set(n, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, fmt.Errorf("failed to %s: %w", action, decodeApiError(resp))
+	}
+	if v == nil || resp.StatusCode == http.StatusNoContent {
+		return resp, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return resp, errors.New(fmt.Sprintf("failed to read API response: %v", err))
+	}
+	return resp, nil
+}
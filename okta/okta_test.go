@@ -0,0 +1,54 @@
+package okta
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+func TestCreateRequestUrl(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		orgUrl, endpoint, expected string
+	}{
+		{"https://foo.okta.com", "/api/v1/users", "https://foo.okta.com/api/v1/users"},
+		{"https://foo.okta.com/", "/api/v1/groups/00g1/users", "https://foo.okta.com/api/v1/groups/00g1/users"},
+		{
+			"https://foo.okta.com",
+			fmt.Sprintf("/api/v1/users/%s", url.PathEscape("hp/seeker")),
+			"https://foo.okta.com/api/v1/users/hp%2Fseeker",
+		},
+		{
+			"https://foo.okta.com",
+			fmt.Sprintf("/api/v1/users/%s", url.PathEscape("harry potter+q@hogwarts.co.uk")),
+			"https://foo.okta.com/api/v1/users/harry%20potter+q@hogwarts.co.uk",
+		},
+		{
+			"https://foo.okta.com",
+			fmt.Sprintf("/api/v1/users/%s", url.PathEscape("a/../00uADMIN")),
+			"https://foo.okta.com/api/v1/users/a%2F..%2F00uADMIN",
+		},
+		{"https://foo.okta.com/okta/", "/api/v1/users", "https://foo.okta.com/okta/api/v1/users"},
+	}
+	for _, tc := range testCases {
+		res, err := CreateRequestUrl(tc.orgUrl, tc.endpoint)
+		if err != nil {
+			t.Errorf("Failed to create URL for %s: %v", tc.endpoint, err)
+		}
+		if res != tc.expected {
+			t.Errorf("Expected %s, received %s", tc.expected, res)
+		}
+	}
+
+	// Dot segments would refer to other resources
+	for _, endpoint := range []string{
+		fmt.Sprintf("/api/v1/users/%s", url.PathEscape("..")),
+		fmt.Sprintf("/api/v1/users/%s/groups", url.PathEscape(".")),
+		"/api/v1/users/%2E%2E",
+	} {
+		if res, err := CreateRequestUrl("https://foo.okta.com", endpoint); err == nil {
+			t.Errorf("Expected creating URL for %s to fail, received %s", endpoint, res)
+		}
+	}
+}
@@ -0,0 +1,896 @@
+// Package oktatest provides an in-process stand-in for the Okta
+// API, for end-to-end tests of okta-admin.
+//
+// The server keeps users, groups, apps, System Log events and
+// sessions in memory and serves the endpoints used by okta-admin
+// from them, paginating lists with Link headers and reporting rate
+// limits via the X-Rate-Limit-* headers, like Okta. Failures of
+// specific requests can be injected using Fail. Requests to any
+// other endpoint fail with 404 Not Found.
+//
+// The Okta SDK refuses to talk to servers over plain HTTP unless
+// the OKTA_TESTING_DISABLE_HTTPS_CHECK environment variable is set
+// to true, so tests using the SDK must set it.
+package oktatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/okta/okta-sdk-golang/okta"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultApiToken is the API token the server accepts unless
+	// ApiToken is changed.
+	DefaultApiToken = "00oktatest"
+
+	// DefaultPageSize is the number of items in a page of a list
+	// when the request doesn't specify a limit.
+	DefaultPageSize = 200
+
+	// DefaultRateLimit is the number of requests the server allows
+	// per minute.
+	DefaultRateLimit = 600
+)
+
+// App is an application in the organization.
+type App struct {
+	Id     string `json:"id"`
+	Name   string `json:"name"`
+	Label  string `json:"label"`
+	Status string `json:"status"`
+}
+
+// Session is a session of a user.
+type Session struct {
+	Id        string    `json:"id"`
+	Login     string    `json:"login"`
+	UserId    string    `json:"userId"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Amr       []string  `json:"amr"`
+}
+
+// failure is an injected failure of the requests matching its
+// method and path.
+type failure struct {
+	method, path string
+	remaining    int
+	status       int
+	summary      string
+}
+
+// Server is a fake Okta organization served over HTTP. Its fields
+// may be changed before making requests to it. Methods adding or
+// inspecting state are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	ApiToken  string
+	PageSize  int
+	RateLimit int
+
+	mu          sync.Mutex
+	users       []*okta.User
+	groups      []*okta.Group
+	members     map[string][]string
+	apps        []*App
+	logs        []map[string]interface{}
+	sessions    map[string]*Session
+	factors     map[string][]map[string]interface{}
+	roles       map[string][]*okta.Role
+	roleTargets map[string][]string
+	failures    []*failure
+	requests    []string
+	nextId      int
+	window      time.Time
+	windowCount int
+}
+
+// NewServer starts and returns a new Server with an empty
+// organization. The caller should call Close when finished, to
+// shut it down.
+func NewServer() *Server {
+	s := &Server{
+		ApiToken:    DefaultApiToken,
+		PageSize:    DefaultPageSize,
+		RateLimit:   DefaultRateLimit,
+		members:     map[string][]string{},
+		sessions:    map[string]*Session{},
+		factors:     map[string][]map[string]interface{}{},
+		roles:       map[string][]*okta.Role{},
+		roleTargets: map[string][]string{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// newId returns a new ID with the prefix Okta uses for the type of
+// object, eg- 00u for users.
+func (s *Server) newId(prefix string) string {
+	s.nextId++
+	return fmt.Sprintf("%s%017d", prefix, s.nextId)
+}
+
+// AddUser adds a user with the email as their login to the
+// organization and returns it.
+func (s *Server) AddUser(email, firstName, lastName, status string) *okta.User {
+	return s.AddUserWithLogin(email, email, firstName, lastName, status)
+}
+
+// AddUserWithLogin adds a user whose login differs from their email
+// to the organization and returns it.
+func (s *Server) AddUserWithLogin(login, email, firstName, lastName, status string) *okta.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	u := &okta.User{
+		Id:      s.newId("00u"),
+		Status:  status,
+		Created: &now,
+		Profile: &okta.UserProfile{
+			"login":     login,
+			"email":     email,
+			"firstName": firstName,
+			"lastName":  lastName,
+		},
+	}
+	s.users = append(s.users, u)
+	return u
+}
+
+// User returns a copy of the user with the ID or login, or nil if
+// it doesn't exist.
+func (s *Server) User(idOrLogin string) *okta.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.findUser(idOrLogin)
+	if u == nil {
+		return nil
+	}
+	res := *u
+	return &res
+}
+
+// AddGroup adds a group with the name to the organization and
+// returns it.
+func (s *Server) AddGroup(name string) *okta.Group {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.newId("00g")
+	g := &okta.Group{
+		Id:      id,
+		Type:    "OKTA_GROUP",
+		Profile: &okta.GroupProfile{Name: name},
+		Links: map[string]interface{}{
+			"users": map[string]interface{}{"href": s.URL + "/api/v1/groups/" + id + "/users"},
+			"apps":  map[string]interface{}{"href": s.URL + "/api/v1/groups/" + id + "/apps"},
+		},
+	}
+	s.groups = append(s.groups, g)
+	return g
+}
+
+// AddGroupMember adds the user to the group.
+func (s *Server) AddGroupMember(groupID, userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addGroupMember(groupID, userID)
+}
+
+// GroupMembers returns the IDs of the members of the group, in the
+// order they were added.
+func (s *Server) GroupMembers(groupID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.members[groupID]...)
+}
+
+// AddApp adds an active app to the organization and returns it.
+func (s *Server) AddApp(name, label string) *App {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := &App{Id: s.newId("0oa"), Name: name, Label: label, Status: "ACTIVE"}
+	s.apps = append(s.apps, a)
+	return a
+}
+
+// AddLogEvent adds an event to the System Log. The event must
+// contain a published timestamp in RFC 3339 format.
+func (s *Server) AddLogEvent(event map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := event["uuid"]; !ok {
+		event["uuid"] = s.newId("log")
+	}
+	s.logs = append(s.logs, event)
+	sort.SliceStable(s.logs, func(i, j int) bool {
+		return logEventTime(s.logs[i]).Before(logEventTime(s.logs[j]))
+	})
+}
+
+// AddSession adds an active session of the user and returns it.
+func (s *Server) AddSession(userID string) *Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	sess := &Session{
+		Id:        s.newId("102"),
+		UserId:    userID,
+		Status:    "ACTIVE",
+		CreatedAt: now,
+		ExpiresAt: now.Add(2 * time.Hour),
+		Amr:       []string{"pwd"},
+	}
+	if u := s.findUser(userID); u != nil {
+		sess.Login, _ = (*u.Profile)["login"].(string)
+	}
+	s.sessions[sess.Id] = sess
+	return sess
+}
+
+// Sessions returns the IDs of the active sessions of the user.
+func (s *Server) Sessions(userID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []string
+	for _, sess := range s.sessions {
+		if sess.UserId == userID {
+			res = append(res, sess.Id)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// AddFactor enrolls a factor of the type for the user.
+func (s *Server) AddFactor(userID, factorType, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.factors[userID] = append(s.factors[userID], map[string]interface{}{
+		"id":         s.newId("mfa"),
+		"factorType": factorType,
+		"provider":   "OKTA",
+		"status":     status,
+	})
+}
+
+// Factors returns the number of factors enrolled by the user.
+func (s *Server) Factors(userID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.factors[userID])
+}
+
+// AddRole assigns an administrator role of the type to the user,
+// scoped to the groups if any are specified.
+func (s *Server) AddRole(userID, roleType, label string, groupIDs ...string) *okta.Role {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := &okta.Role{Id: s.newId("ra1"), Type: roleType, Label: label, Status: "ACTIVE", AssignmentType: "USER"}
+	s.roles[userID] = append(s.roles[userID], r)
+	s.roleTargets[userID+"/"+r.Id] = groupIDs
+	return r
+}
+
+// Fail makes the next n requests with the method and path (without
+// the query) fail with the status code and an Okta error with the
+// summary. If n is negative, all such requests fail.
+func (s *Server) Fail(method, path string, n, status int, summary string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &failure{method: method, path: path, remaining: n, status: status, summary: summary})
+}
+
+// Requests returns the requests made to the server so far, as the
+// method followed by the path and query.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Okta-Request-Id", fmt.Sprintf("req%d", len(s.requests)))
+
+	if !s.checkRateLimit(w) {
+		writeError(w, http.StatusTooManyRequests, "E0000047", "API call exceeded rate limit due to too many requests.")
+		return
+	}
+	if r.Header.Get("Authorization") != "SSWS "+s.ApiToken {
+		writeError(w, http.StatusUnauthorized, "E0000011", "Invalid token provided")
+		return
+	}
+	if f := s.matchFailure(r); f != nil {
+		writeError(w, f.status, errorCodes[f.status], f.summary)
+		return
+	}
+
+	// Segments are split before unescaping, so that they may
+	// contain escaped slashes, like logins can.
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.EscapedPath(), "/api/v1"), "/"), "/")
+	for i, seg := range segments {
+		segments[i], _ = url.PathUnescape(seg)
+	}
+	switch segments[0] {
+	case "users":
+		s.serveUsers(w, r, segments[1:])
+	case "groups":
+		s.serveGroups(w, r, segments[1:])
+	case "apps":
+		s.serveApps(w, r, segments[1:])
+	case "logs":
+		s.serveLogs(w, r, segments[1:])
+	case "sessions":
+		s.serveSessions(w, r, segments[1:])
+	default:
+		writeNotFound(w, r.URL.Path)
+	}
+}
+
+// checkRateLimit sets the rate limit headers on the response and
+// returns false if the rate limit has been exceeded.
+func (s *Server) checkRateLimit(w http.ResponseWriter) bool {
+	now := time.Now()
+	if now.Sub(s.window) >= time.Minute {
+		s.window, s.windowCount = now, 0
+	}
+	s.windowCount++
+
+	remaining := s.RateLimit - s.windowCount
+	if remaining < 0 {
+		remaining = 0
+	}
+	w.Header().Set("X-Rate-Limit-Limit", strconv.Itoa(s.RateLimit))
+	w.Header().Set("X-Rate-Limit-Remaining", strconv.Itoa(remaining))
+	w.Header().Set("X-Rate-Limit-Reset", strconv.FormatInt(s.window.Add(time.Minute).Unix(), 10))
+	return s.windowCount <= s.RateLimit
+}
+
+func (s *Server) matchFailure(r *http.Request) *failure {
+	for _, f := range s.failures {
+		if f.method == r.Method && f.path == r.URL.Path && f.remaining != 0 {
+			f.remaining--
+			return f
+		}
+	}
+	return nil
+}
+
+func (s *Server) serveUsers(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) == 0 || segments[0] == "" {
+		switch r.Method {
+		case http.MethodGet:
+			s.listUsers(w, r)
+		case http.MethodPost:
+			s.createUser(w, r)
+		default:
+			writeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	u := s.findUser(segments[0])
+	if u == nil {
+		writeNotFound(w, fmt.Sprintf("%s (User)", segments[0]))
+		return
+	}
+
+	switch route := strings.Join(append([]string{r.Method}, segments[1:]...), " "); {
+	case route == "GET":
+		writeJSON(w, http.StatusOK, u)
+	case route == "POST lifecycle deactivate":
+		u.Status = "DEPROVISIONED"
+		s.endSessions(u.Id)
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	case route == "POST lifecycle activate":
+		if u.Status != "STAGED" && u.Status != "DEPROVISIONED" {
+			writeError(w, http.StatusForbidden, "E0000016", "Activation failed because the user is already active")
+			return
+		}
+		u.Status = "PROVISIONED"
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	case route == "GET groups":
+		groups := []*okta.Group{}
+		for _, g := range s.groups {
+			for _, uid := range s.members[g.Id] {
+				if uid == u.Id {
+					groups = append(groups, g)
+					break
+				}
+			}
+		}
+		writeJSON(w, http.StatusOK, groups)
+	case route == "POST lifecycle reset_password":
+		if u.Status == "DEPROVISIONED" {
+			writeError(w, http.StatusForbidden, "E0000038", "This operation is not allowed in the user's current status.")
+			return
+		}
+		u.Status = "RECOVERY"
+		if r.URL.Query().Get("sendEmail") == "false" {
+			writeJSON(w, http.StatusOK, map[string]string{"resetPasswordUrl": s.URL + "/reset_password/" + u.Id})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	case route == "POST lifecycle reset_factors":
+		delete(s.factors, u.Id)
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	case route == "DELETE sessions":
+		s.endSessions(u.Id)
+		w.WriteHeader(http.StatusNoContent)
+	case route == "GET factors":
+		writeJSON(w, http.StatusOK, nonNil(s.factors[u.Id]))
+	case route == "GET roles":
+		roles := s.roles[u.Id]
+		if roles == nil {
+			roles = []*okta.Role{}
+		}
+		writeJSON(w, http.StatusOK, roles)
+	case len(segments) == 5 && r.Method == http.MethodGet && segments[1] == "roles" && segments[3] == "targets" && segments[4] == "groups":
+		gids, ok := s.roleTargets[u.Id+"/"+segments[2]]
+		if !ok {
+			writeNotFound(w, fmt.Sprintf("%s (Role)", segments[2]))
+			return
+		}
+		groups := []*okta.Group{}
+		for _, gid := range gids {
+			if g := s.findGroup(gid); g != nil {
+				groups = append(groups, g)
+			}
+		}
+		writeJSON(w, http.StatusOK, groups)
+	default:
+		writeNotFound(w, r.URL.Path)
+	}
+}
+
+// rxStatusFilter matches the only user filter supported by the
+// server, on the status of users.
+var rxStatusFilter = regexp.MustCompile(`^status eq "([A-Z_]+)"$`)
+
+// rxEmailSearch matches the only user search supported by the
+// server, on the email IDs of users.
+var rxEmailSearch = regexp.MustCompile(`^profile\.email eq "(.*)"$`)
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	// Like Okta, searches return users of all statuses
+	if search := r.URL.Query().Get("search"); search != "" {
+		m := rxEmailSearch.FindStringSubmatch(search)
+		if m == nil {
+			writeError(w, http.StatusBadRequest, "E0000031", "Invalid search criteria.")
+			return
+		}
+		var users []interface{}
+		for _, u := range s.users {
+			if email, _ := (*u.Profile)["email"].(string); strings.EqualFold(email, m[1]) {
+				users = append(users, u)
+			}
+		}
+		s.writePage(w, r, users)
+		return
+	}
+
+	var status string
+	if filter := r.URL.Query().Get("filter"); filter != "" {
+		m := rxStatusFilter.FindStringSubmatch(filter)
+		if m == nil {
+			writeError(w, http.StatusBadRequest, "E0000031", "Invalid search criteria.")
+			return
+		}
+		status = m[1]
+	}
+
+	// Like Okta, deprovisioned users are only listed if requested
+	var users []interface{}
+	for _, u := range s.users {
+		if (status == "" && u.Status != "DEPROVISIONED") || u.Status == status {
+			users = append(users, u)
+		}
+	}
+	s.writePage(w, r, users)
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request) {
+	var u okta.User
+	if err := json.NewDecoder(r.Body).Decode(&u); err != nil || u.Profile == nil {
+		writeError(w, http.StatusBadRequest, "E0000003", "The request body was not well-formed.")
+		return
+	}
+	login, _ := (*u.Profile)["login"].(string)
+	if login == "" {
+		writeError(w, http.StatusBadRequest, "E0000001", "Api validation failed: login")
+		return
+	}
+	if s.findUser(login) != nil {
+		writeError(w, http.StatusBadRequest, "E0000001", "Api validation failed: login",
+			"login: An object with this field already exists in the current organization")
+		return
+	}
+
+	now := time.Now().UTC()
+	u.Id, u.Created, u.Status = s.newId("00u"), &now, "STAGED"
+	if r.URL.Query().Get("activate") != "false" {
+		u.Status, u.Activated = "PROVISIONED", &now
+	}
+	s.users = append(s.users, &u)
+	writeJSON(w, http.StatusOK, &u)
+}
+
+func (s *Server) serveGroups(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) == 0 || segments[0] == "" {
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w)
+			return
+		}
+		// q matches groups whose name starts with it
+		q := strings.ToLower(r.URL.Query().Get("q"))
+		var groups []interface{}
+		for _, g := range s.groups {
+			if strings.HasPrefix(strings.ToLower(g.Profile.Name), q) {
+				groups = append(groups, g)
+			}
+		}
+		s.writePage(w, r, groups)
+		return
+	}
+
+	g := s.findGroup(segments[0])
+	if g == nil {
+		writeNotFound(w, fmt.Sprintf("%s (UserGroup)", segments[0]))
+		return
+	}
+
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, g)
+	case len(segments) == 2 && segments[1] == "users" && r.Method == http.MethodGet:
+		var users []interface{}
+		for _, uid := range s.members[g.Id] {
+			users = append(users, s.findUser(uid))
+		}
+		s.writePage(w, r, users)
+	case len(segments) == 3 && segments[1] == "users":
+		u := s.findUser(segments[2])
+		if u == nil {
+			writeNotFound(w, fmt.Sprintf("%s (User)", segments[2]))
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			s.addGroupMember(g.Id, u.Id)
+		case http.MethodDelete:
+			s.removeGroupMember(g.Id, u.Id)
+		default:
+			writeMethodNotAllowed(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeNotFound(w, r.URL.Path)
+	}
+}
+
+func (s *Server) serveApps(w http.ResponseWriter, r *http.Request, segments []string) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w)
+		return
+	}
+	if len(segments) == 0 || segments[0] == "" {
+		q := strings.ToLower(r.URL.Query().Get("q"))
+		var apps []interface{}
+		for _, a := range s.apps {
+			if strings.HasPrefix(strings.ToLower(a.Label), q) || strings.HasPrefix(strings.ToLower(a.Name), q) {
+				apps = append(apps, a)
+			}
+		}
+		s.writePage(w, r, apps)
+		return
+	}
+	for _, a := range s.apps {
+		if a.Id == segments[0] && len(segments) == 1 {
+			writeJSON(w, http.StatusOK, a)
+			return
+		}
+	}
+	writeNotFound(w, fmt.Sprintf("%s (AppInstance)", segments[0]))
+}
+
+// rxLogFilterClause matches a clause of the System Log filters
+// supported by the server. Clauses may only be joined with "or".
+var rxLogFilterClause = regexp.MustCompile(`^(actor\.id|target\.id|eventType) eq "([^"]*)"$`)
+
+func (s *Server) serveLogs(w http.ResponseWriter, r *http.Request, segments []string) {
+	if r.Method != http.MethodGet || (len(segments) > 0 && segments[0] != "") {
+		writeNotFound(w, r.URL.Path)
+		return
+	}
+
+	qs := r.URL.Query()
+	var since, until time.Time
+	for _, p := range []struct {
+		name string
+		t    *time.Time
+	}{{"since", &since}, {"until", &until}} {
+		if v := qs.Get(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "E0000001", fmt.Sprintf("Api validation failed: %s", p.name))
+				return
+			}
+			*p.t = t
+		}
+	}
+
+	var clauses [][]string
+	if filter := qs.Get("filter"); filter != "" {
+		for _, c := range strings.Split(filter, " or ") {
+			m := rxLogFilterClause.FindStringSubmatch(strings.Trim(strings.TrimSpace(c), "()"))
+			if m == nil {
+				writeError(w, http.StatusBadRequest, "E0000031", "Invalid search criteria.")
+				return
+			}
+			clauses = append(clauses, m[1:])
+		}
+	}
+
+	var events []interface{}
+	for _, e := range s.logs {
+		t := logEventTime(e)
+		if (!since.IsZero() && t.Before(since)) || (!until.IsZero() && !t.Before(until)) {
+			continue
+		}
+		if len(clauses) > 0 && !matchLogEvent(e, clauses) {
+			continue
+		}
+		events = append(events, e)
+	}
+	if qs.Get("sortOrder") == "DESCENDING" {
+		for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+			events[i], events[j] = events[j], events[i]
+		}
+	}
+	s.writePage(w, r, events)
+}
+
+func (s *Server) serveSessions(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) != 1 || segments[0] == "" {
+		writeNotFound(w, r.URL.Path)
+		return
+	}
+	sess, ok := s.sessions[segments[0]]
+	if !ok {
+		writeNotFound(w, fmt.Sprintf("%s (Session)", segments[0]))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, sess)
+	case http.MethodDelete:
+		delete(s.sessions, sess.Id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+// writePage writes the page of items requested via the limit and
+// after query params, along with a Link header pointing to the
+// next page, if there is one. The after cursor is the ID of the
+// last item of the previous page.
+func (s *Server) writePage(w http.ResponseWriter, r *http.Request, items []interface{}) {
+	qs := r.URL.Query()
+	limit := s.PageSize
+	if v := qs.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "E0000001", "Api validation failed: limit")
+			return
+		}
+		limit = n
+	}
+
+	start := 0
+	if after := qs.Get("after"); after != "" {
+		for i, item := range items {
+			if itemId(item) == after {
+				start = i + 1
+				break
+			}
+		}
+	}
+	end := start + limit
+	if end > len(items) {
+		end = len(items)
+	}
+
+	self := *r.URL
+	links := []string{fmt.Sprintf(`<%s%s>; rel="self"`, s.URL, self.RequestURI())}
+	if end < len(items) {
+		qs.Set("after", itemId(items[end-1]))
+		qs.Set("limit", strconv.Itoa(limit))
+		next := url.URL{Path: r.URL.Path, RawQuery: qs.Encode()}
+		links = append(links, fmt.Sprintf(`<%s%s>; rel="next"`, s.URL, next.RequestURI()))
+	}
+	for _, l := range links {
+		w.Header().Add("Link", l)
+	}
+
+	page := items[start:end]
+	if page == nil {
+		page = []interface{}{}
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) findUser(idOrLogin string) *okta.User {
+	for _, u := range s.users {
+		if login, _ := (*u.Profile)["login"].(string); u.Id == idOrLogin || strings.EqualFold(login, idOrLogin) {
+			return u
+		}
+	}
+	return nil
+}
+
+func (s *Server) findGroup(id string) *okta.Group {
+	for _, g := range s.groups {
+		if g.Id == id {
+			return g
+		}
+	}
+	return nil
+}
+
+func (s *Server) addGroupMember(groupID, userID string) {
+	for _, uid := range s.members[groupID] {
+		if uid == userID {
+			return
+		}
+	}
+	s.members[groupID] = append(s.members[groupID], userID)
+}
+
+func (s *Server) removeGroupMember(groupID, userID string) {
+	members := s.members[groupID][:0]
+	for _, uid := range s.members[groupID] {
+		if uid != userID {
+			members = append(members, uid)
+		}
+	}
+	s.members[groupID] = members
+}
+
+func (s *Server) endSessions(userID string) {
+	for id, sess := range s.sessions {
+		if sess.UserId == userID {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+func itemId(item interface{}) string {
+	switch v := item.(type) {
+	case *okta.User:
+		return v.Id
+	case *okta.Group:
+		return v.Id
+	case *App:
+		return v.Id
+	case map[string]interface{}:
+		id, _ := v["uuid"].(string)
+		return id
+	}
+	return ""
+}
+
+func logEventTime(e map[string]interface{}) time.Time {
+	published, _ := e["published"].(string)
+	t, _ := time.Parse(time.RFC3339, published)
+	return t
+}
+
+// matchLogEvent returns true if the event matches any of the
+// filter clauses, each a field and value.
+func matchLogEvent(e map[string]interface{}, clauses [][]string) bool {
+	for _, c := range clauses {
+		switch c[0] {
+		case "eventType":
+			if e["eventType"] == c[1] {
+				return true
+			}
+		case "actor.id":
+			if actor, ok := e["actor"].(map[string]interface{}); ok && actor["id"] == c[1] {
+				return true
+			}
+		case "target.id":
+			targets, _ := e["target"].([]interface{})
+			for _, t := range targets {
+				if target, ok := t.(map[string]interface{}); ok && target["id"] == c[1] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func nonNil(items []map[string]interface{}) []map[string]interface{} {
+	if items == nil {
+		return []map[string]interface{}{}
+	}
+	return items
+}
+
+// errorCodes contains the Okta error codes returned along with
+// HTTP status codes of injected failures.
+var errorCodes = map[int]string{
+	http.StatusBadRequest:          "E0000001",
+	http.StatusUnauthorized:        "E0000011",
+	http.StatusForbidden:           "E0000006",
+	http.StatusNotFound:            "E0000007",
+	http.StatusTooManyRequests:     "E0000047",
+	http.StatusInternalServerError: "E0000009",
+	http.StatusServiceUnavailable:  "E0000010",
+}
+
+var errorIds struct {
+	sync.Mutex
+	n int
+}
+
+// writeError writes an error in the format of the Okta API.
+func writeError(w http.ResponseWriter, status int, code, summary string, causes ...string) {
+	errorIds.Lock()
+	errorIds.n++
+	id := fmt.Sprintf("oaetest%010d", errorIds.n)
+	errorIds.Unlock()
+
+	errorCauses := []interface{}{}
+	for _, c := range causes {
+		errorCauses = append(errorCauses, map[string]string{"errorSummary": c})
+	}
+	writeJSON(w, status, map[string]interface{}{
+		"errorCode":    code,
+		"errorSummary": summary,
+		"errorLink":    code,
+		"errorId":      id,
+		"errorCauses":  errorCauses,
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, resource string) {
+	writeError(w, http.StatusNotFound, "E0000007", fmt.Sprintf("Not found: Resource not found: %s", resource))
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "E0000022", "The endpoint does not support the provided HTTP method")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,131 @@
+package oktatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func get(t *testing.T, s *Server, path string) (*http.Response, []map[string]interface{}) {
+	t.Helper()
+
+	req, _ := http.NewRequest(http.MethodGet, s.URL+path, nil)
+	req.Header.Set("Authorization", "SSWS "+s.ApiToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body []map[string]interface{}
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+	}
+	return resp, body
+}
+
+func TestServer(t *testing.T) {
+	t.Run("paginates lists with Link headers", func(t *testing.T) {
+		s := NewServer()
+		defer s.Close()
+		for i := 0; i < 5; i++ {
+			s.AddGroup(fmt.Sprintf("group-%d", i))
+		}
+
+		var names []string
+		path := "/api/v1/groups?limit=2"
+		for pages := 0; path != ""; pages++ {
+			if pages > 3 {
+				t.Fatalf("Expected 3 pages, received more")
+			}
+			resp, groups := get(t, s, path)
+			for _, g := range groups {
+				names = append(names, g["profile"].(map[string]interface{})["name"].(string))
+			}
+
+			path = ""
+			for _, l := range resp.Header["Link"] {
+				if strings.HasSuffix(l, `rel="next"`) {
+					path = strings.TrimPrefix(l[1:strings.Index(l, ">")], s.URL)
+				}
+			}
+		}
+
+		if got := strings.Join(names, ","); got != "group-0,group-1,group-2,group-3,group-4" {
+			t.Errorf("Expected all groups in order, received %s", got)
+		}
+	})
+
+	t.Run("filters groups by name prefix", func(t *testing.T) {
+		s := NewServer()
+		defer s.Close()
+		s.AddGroup("Engineering")
+		s.AddGroup("Sales")
+
+		_, groups := get(t, s, "/api/v1/groups?q=eng")
+		if len(groups) != 1 {
+			t.Errorf("Expected 1 group, received %d", len(groups))
+		}
+	})
+
+	t.Run("hides deprovisioned users unless filtered", func(t *testing.T) {
+		s := NewServer()
+		defer s.Close()
+		s.AddUser("a@example.com", "A", "A", "ACTIVE")
+		s.AddUser("b@example.com", "B", "B", "DEPROVISIONED")
+
+		if _, users := get(t, s, "/api/v1/users"); len(users) != 1 {
+			t.Errorf("Expected 1 user, received %d", len(users))
+		}
+		if _, users := get(t, s, `/api/v1/users?filter=status+eq+"DEPROVISIONED"`); len(users) != 1 || users[0]["status"] != "DEPROVISIONED" {
+			t.Errorf("Expected the deprovisioned user, received %v", users)
+		}
+	})
+
+	t.Run("injects failures", func(t *testing.T) {
+		s := NewServer()
+		defer s.Close()
+		s.Fail(http.MethodGet, "/api/v1/apps", 1, http.StatusInternalServerError, "boom")
+
+		if resp, _ := get(t, s, "/api/v1/apps"); resp.StatusCode != http.StatusInternalServerError {
+			t.Errorf("Expected first request to fail, received %s", resp.Status)
+		}
+		if resp, _ := get(t, s, "/api/v1/apps"); resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected second request to succeed, received %s", resp.Status)
+		}
+	})
+
+	t.Run("enforces rate limits", func(t *testing.T) {
+		s := NewServer()
+		defer s.Close()
+		s.RateLimit = 2
+
+		resp, _ := get(t, s, "/api/v1/apps")
+		if v := resp.Header.Get("X-Rate-Limit-Remaining"); v != "1" {
+			t.Errorf("Expected 1 remaining request, received %s", v)
+		}
+		get(t, s, "/api/v1/apps")
+		if resp, _ := get(t, s, "/api/v1/apps"); resp.StatusCode != http.StatusTooManyRequests {
+			t.Errorf("Expected %d, received %s", http.StatusTooManyRequests, resp.Status)
+		}
+	})
+
+	t.Run("rejects invalid tokens", func(t *testing.T) {
+		s := NewServer()
+		defer s.Close()
+		s.ApiToken = "secret"
+
+		resp, err := http.Get(s.URL + "/api/v1/users")
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected %d, received %s", http.StatusUnauthorized, resp.Status)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package okta
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// NextPageCursor returns the cursor to pass as the "after"
+// query parameter to fetch the next page of a paginated
+// Okta API listing. Okta advertises the next page via a Link
+// header with relation "next". If resp is the last page,
+// an empty string is returned.
+func NextPageCursor(resp *http.Response) string {
+	if resp == nil {
+		return ""
+	}
+
+	for _, header := range resp.Header["Link"] {
+		// A single header may contain multiple comma-separated links
+		for _, link := range strings.Split(header, ",") {
+			parts := strings.Split(link, ";")
+			if len(parts) < 2 || !isNextRelation(parts[1:]) {
+				continue
+			}
+
+			rawUrl := strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			u, err := url.Parse(rawUrl)
+			if err != nil {
+				continue
+			}
+			return u.Query().Get("after")
+		}
+	}
+	return ""
+}
+
+// isNextRelation returns true if any of the link parameters
+// supplied to it is rel="next".
+func isNextRelation(params []string) bool {
+	for _, p := range params {
+		p = strings.ReplaceAll(strings.TrimSpace(p), " ", "")
+		if p == `rel="next"` || p == "rel=next" {
+			return true
+		}
+	}
+	return false
+}
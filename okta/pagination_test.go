@@ -0,0 +1,42 @@
+package okta
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNextPageCursor(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		links    []string
+		expected string
+	}{
+		{nil, ""},
+		{[]string{`<https://foo.okta.com/api/v1/users?limit=200>; rel="self"`}, ""},
+		{
+			[]string{
+				`<https://foo.okta.com/api/v1/users?limit=200>; rel="self"`,
+				`<https://foo.okta.com/api/v1/users?after=00ubfjQEMYBLRUWIEDKK&limit=200>; rel="next"`,
+			},
+			"00ubfjQEMYBLRUWIEDKK",
+		},
+		{
+			[]string{
+				`<https://foo.okta.com/api/v1/groups?limit=2>; rel="self", <https://foo.okta.com/api/v1/groups?after=00g1emaKYZTWRYYRRTSK&limit=2>; rel="next"`,
+			},
+			"00g1emaKYZTWRYYRRTSK",
+		},
+		{[]string{`<https://foo.okta.com/api/v1/logs?after=1571&limit=2>;rel=next`}, "1571"},
+	}
+
+	for _, tc := range testCases {
+		resp := &http.Response{Header: http.Header{}}
+		for _, l := range tc.links {
+			resp.Header.Add("Link", l)
+		}
+		if res := NextPageCursor(resp); res != tc.expected {
+			t.Errorf("Expected cursor %q, received %q for links %v", tc.expected, res, tc.links)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package okta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/okta/okta-sdk-golang/okta"
+	"github.com/okta/okta-sdk-golang/okta/query"
+	"net/http"
+)
+
+// Policy represents a Sign-On, Password or MFA Enrollment policy.
+// The SDK decodes all policies into a type without conditions or
+// settings, so the fields of every supported policy type are
+// decoded here instead. Raw contains the policy exactly as
+// returned by the API.
+type Policy struct {
+	Id          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Type        string            `json:"type"`
+	Status      string            `json:"status"`
+	Priority    int64             `json:"priority"`
+	System      bool              `json:"system"`
+	Conditions  *PolicyConditions `json:"conditions"`
+	Settings    *PolicySettings   `json:"settings"`
+	Raw         json.RawMessage   `json:"-"`
+}
+
+// PolicyRule represents a rule of a policy. Like policies, rules
+// returned by the SDK lack names, conditions and actions.
+type PolicyRule struct {
+	Id         string             `json:"id"`
+	Name       string             `json:"name"`
+	Type       string             `json:"type"`
+	Status     string             `json:"status"`
+	Priority   int64              `json:"priority"`
+	System     bool               `json:"system"`
+	Conditions *PolicyConditions  `json:"conditions"`
+	Actions    *PolicyRuleActions `json:"actions"`
+	Raw        json.RawMessage    `json:"-"`
+}
+
+// PolicyConditions contains the conditions of a policy or a rule
+// which determine whom and which requests it applies to.
+type PolicyConditions struct {
+	People       *okta.PolicyPeopleCondition                         `json:"people"`
+	Network      *okta.PolicyNetworkCondition                        `json:"network"`
+	AuthContext  *okta.PolicyRuleAuthContextCondition                `json:"authContext"`
+	AuthProvider *okta.PasswordPolicyAuthenticationProviderCondition `json:"authProvider"`
+}
+
+// PolicySettings contains the settings of Password policies and
+// the factors of MFA Enrollment policies.
+type PolicySettings struct {
+	Password *okta.PasswordPolicyPasswordSettings `json:"password"`
+	Factors  map[string]*PolicyFactorSettings     `json:"factors"`
+}
+
+// PolicyFactorSettings describes whether a factor can be enrolled
+// under an MFA Enrollment policy.
+type PolicyFactorSettings struct {
+	Enroll *struct {
+		Self string `json:"self"`
+	} `json:"enroll"`
+}
+
+// PolicyRuleActions contains the actions of Sign-On, Password and
+// MFA Enrollment policy rules.
+type PolicyRuleActions struct {
+	Signon                   *okta.OktaSignOnPolicyRuleSignonActions `json:"signon"`
+	PasswordChange           *okta.PasswordPolicyRuleAction          `json:"passwordChange"`
+	SelfServicePasswordReset *okta.PasswordPolicyRuleAction          `json:"selfServicePasswordReset"`
+	SelfServiceUnlock        *okta.PasswordPolicyRuleAction          `json:"selfServiceUnlock"`
+	Enroll                   *struct {
+		Self string `json:"self"`
+	} `json:"enroll"`
+}
+
+// ListPolicies returns the policies matching the query params
+// supplied to it. The API requires the type of the policies to
+// be specified.
+func ListPolicies(c *Credentials, qp *query.Params) ([]*Policy, *http.Response, error) {
+	var rawPolicies []json.RawMessage
+
+	resp, err := getResource(c, "/api/v1/policies", qp, "policies", &rawPolicies)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	policies := make([]*Policy, len(rawPolicies), len(rawPolicies))
+	for i, raw := range rawPolicies {
+		if policies[i], err = decodePolicy(raw); err != nil {
+			return nil, resp, err
+		}
+	}
+	return policies, resp, nil
+}
+
+// GetPolicy returns the policy with the specified ID.
+func GetPolicy(c *Credentials, policyID string) (*Policy, *http.Response, error) {
+	var raw json.RawMessage
+	endpoint := fmt.Sprintf("/api/v1/policies/%s", policyID)
+
+	resp, err := getResource(c, endpoint, nil, "policy", &raw)
+	if err != nil {
+		return nil, resp, err
+	}
+	policy, err := decodePolicy(raw)
+	return policy, resp, err
+}
+
+// ListPolicyRules returns the rules of the policy with the
+// specified ID.
+func ListPolicyRules(c *Credentials, policyID string) ([]*PolicyRule, *http.Response, error) {
+	var rawRules []json.RawMessage
+	endpoint := fmt.Sprintf("/api/v1/policies/%s/rules", policyID)
+
+	resp, err := getResource(c, endpoint, nil, "policy rules", &rawRules)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	rules := make([]*PolicyRule, len(rawRules), len(rawRules))
+	for i, raw := range rawRules {
+		var r PolicyRule
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return nil, resp, errors.New(fmt.Sprintf("failed to read policy rule: %v", err))
+		}
+		r.Raw = raw
+		rules[i] = &r
+	}
+	return rules, resp, nil
+}
+
+// CreatePolicy creates a policy from the body, which must be a
+// JSON-encodable policy object, and activates it if specified.
+func CreatePolicy(c *Credentials, body interface{}, activate bool) (*Policy, *http.Response, error) {
+	var raw json.RawMessage
+	qp := query.NewQueryParams(query.WithActivate(activate))
+
+	resp, err := sendRequest(c, http.MethodPost, "/api/v1/policies", qp, body, "create policy", &raw)
+	if err != nil {
+		return nil, resp, err
+	}
+	policy, err := decodePolicy(raw)
+	return policy, resp, err
+}
+
+// UpdatePolicy replaces the policy with the specified ID with the
+// body, which must be a JSON-encodable policy object.
+func UpdatePolicy(c *Credentials, policyID string, body interface{}) (*http.Response, error) {
+	endpoint := fmt.Sprintf("/api/v1/policies/%s", policyID)
+	return sendRequest(c, http.MethodPut, endpoint, nil, body, "update policy", nil)
+}
+
+// CreatePolicyRule adds a rule created from the body to the policy
+// with the specified ID, and activates it if specified.
+func CreatePolicyRule(c *Credentials, policyID string, body interface{}, activate bool) (*http.Response, error) {
+	endpoint := fmt.Sprintf("/api/v1/policies/%s/rules", policyID)
+	qp := query.NewQueryParams(query.WithActivate(activate))
+	return sendRequest(c, http.MethodPost, endpoint, qp, body, "create policy rule", nil)
+}
+
+// UpdatePolicyRule replaces the rule with the specified ID of the
+// policy with the body.
+func UpdatePolicyRule(c *Credentials, policyID, ruleID string, body interface{}) (*http.Response, error) {
+	endpoint := fmt.Sprintf("/api/v1/policies/%s/rules/%s", policyID, ruleID)
+	return sendRequest(c, http.MethodPut, endpoint, nil, body, "update policy rule", nil)
+}
+
+func decodePolicy(raw json.RawMessage) (*Policy, error) {
+	var p Policy
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, errors.New(fmt.Sprintf("failed to read policy: %v", err))
+	}
+	p.Raw = raw
+	return &p, nil
+}
@@ -0,0 +1,36 @@
+package okta
+
+import (
+	"fmt"
+	"github.com/okta/okta-sdk-golang/okta"
+	"net/http"
+	"time"
+)
+
+// Session represents an Okta session. The SDK cannot decode real
+// sessions because it expects authentication methods (amr) to be
+// objects, while the API returns them as strings.
+type Session struct {
+	Id                       string                        `json:"id"`
+	Login                    string                        `json:"login"`
+	UserId                   string                        `json:"userId"`
+	Status                   string                        `json:"status"`
+	CreatedAt                *time.Time                    `json:"createdAt"`
+	ExpiresAt                *time.Time                    `json:"expiresAt"`
+	LastPasswordVerification *time.Time                    `json:"lastPasswordVerification"`
+	LastFactorVerification   *time.Time                    `json:"lastFactorVerification"`
+	Amr                      []string                      `json:"amr"`
+	Idp                      *okta.SessionIdentityProvider `json:"idp"`
+}
+
+// GetSession returns the session with the specified ID.
+func GetSession(c *Credentials, sessionID string) (*Session, *http.Response, error) {
+	var session Session
+	endpoint := fmt.Sprintf("/api/v1/sessions/%s", sessionID)
+
+	resp, err := getResource(c, endpoint, nil, "session", &session)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &session, resp, nil
+}
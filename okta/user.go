@@ -1,44 +1,82 @@
 package okta
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/okta/okta-sdk-golang/okta"
 	"net/http"
+	"net/url"
+	"time"
 )
 
-// GetUserByEmail returns information about the user associated
-// with the specified email ID.
-func GetUserByEmail(c *Credentials, email string) (ApiResponse, *http.Response, error) {
-	var user ApiResponse
-	client := &http.Client{}
-	endpoint := fmt.Sprintf("/api/v1/users/%s", email)
+// User represents an Okta user. Unlike the SDK's user, its profile
+// can be read via accessors which never panic, whatever the
+// profile's schema.
+type User struct {
+	Id              string           `json:"id"`
+	Status          string           `json:"status"`
+	Created         *time.Time       `json:"created"`
+	Activated       *time.Time       `json:"activated"`
+	StatusChanged   *time.Time       `json:"statusChanged"`
+	LastLogin       *time.Time       `json:"lastLogin"`
+	LastUpdated     *time.Time       `json:"lastUpdated"`
+	PasswordChanged *time.Time       `json:"passwordChanged"`
+	Profile         UserProfile      `json:"profile"`
+	Credentials     *UserCredentials `json:"credentials"`
+}
 
-	reqUrl, err := CreateRequestUrl(c.OrgUrl, endpoint)
-	if err != nil {
-		return nil, nil, err
-	}
+// UserCredentials contains the credentials of a user which are
+// exposed by the API. Secrets such as passwords never are.
+type UserCredentials struct {
+	Provider *okta.AuthenticationProvider `json:"provider"`
+}
 
-	req, err := http.NewRequest(http.MethodGet, reqUrl, nil)
-	if err != nil {
-		return nil, nil, errors.New(fmt.Sprintf("unable to create request: %v", err))
-	}
+// UserProfile contains the profile attributes of a user. The
+// attributes depend on the organization's user schema, so they're
+// kept as decoded from JSON.
+type UserProfile map[string]interface{}
+
+// String returns the value of the string attribute with the
+// specified name, or an empty string if the attribute is missing
+// or isn't a string.
+func (p UserProfile) String(name string) string {
+	s, _ := p[name].(string)
+	return s
+}
 
-	for n, v := range CreateRequestHeaders(c.ApiToken) {
-		req.Header.Set(n, v)
+// Login returns the user's login.
+func (p UserProfile) Login() string {
+	return p.String("login")
+}
+
+// Email returns the user's primary email ID.
+func (p UserProfile) Email() string {
+	return p.String("email")
+}
+
+// ProfileOf returns the profile of a user returned by the SDK.
+// The profile is empty if the user is nil or has no profile.
+func ProfileOf(u *okta.User) UserProfile {
+	if u == nil || u.Profile == nil {
+		return UserProfile{}
 	}
+	return UserProfile(*u.Profile)
+}
+
+// GetUser returns the user with the specified ID or login. The
+// identifier is escaped, so logins containing characters such as
+// / or + can be looked up too. An error is returned if the API's
+// response doesn't describe a user.
+func GetUser(c *Credentials, idOrLogin string) (*User, *http.Response, error) {
+	var user User
+	endpoint := fmt.Sprintf("/api/v1/users/%s", url.PathEscape(idOrLogin))
 
-	resp, err := client.Do(req)
+	resp, err := getResource(c, endpoint, nil, "user", &user)
 	if err != nil {
-		return nil, nil, err
+		return nil, resp, err
 	}
-	if resp.StatusCode != http.StatusOK {
-		return ApiResponse{}, resp, errors.New(fmt.Sprintf("failed to fetch user (%s)", resp.Status))
-	}
-	defer resp.Body.Close()
-
-	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
-		return nil, resp, errors.New(fmt.Sprintf("failed to read API response: %v", err))
+	if user.Id == "" {
+		return nil, resp, errors.New("failed to read API response: user has no ID")
 	}
-	return user, resp, nil
+	return &user, resp, nil
 }
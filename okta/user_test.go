@@ -0,0 +1,59 @@
+package okta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetUser(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v1/users/hp%2Fseeker":
+			w.Write([]byte(`{"id": "00u1", "status": "ACTIVE", "created": "2019-10-01T10:00:00.000Z",
+				"profile": {"login": "hp/seeker", "email": "harry@hogwarts.co.uk", "nickname": 7},
+				"credentials": {"provider": {"type": "OKTA", "name": "OKTA"}}}`))
+		case "/api/v1/users/no-id":
+			w.Write([]byte(`{"status": "ACTIVE", "profile": {}}`))
+		case "/api/v1/users/bad-id":
+			w.Write([]byte(`{"id": 42}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	creds := &Credentials{OrgUrl: srv.URL, ApiToken: "token"}
+
+	u, _, err := GetUser(creds, "hp/seeker")
+	if err != nil {
+		t.Fatalf("Failed to fetch user: %v", err)
+	}
+	if u.Id != "00u1" || u.Status != "ACTIVE" || u.Created == nil {
+		t.Errorf("Expected user to be decoded, received %+v", u)
+	}
+	if u.Profile.Login() != "hp/seeker" || u.Profile.Email() != "harry@hogwarts.co.uk" {
+		t.Errorf("Expected login and email to be decoded, received %v", u.Profile)
+	}
+	if v := u.Profile.String("nickname"); v != "" {
+		t.Errorf("Expected non-string attribute to be read as empty, received %s", v)
+	}
+	if u.Credentials == nil || u.Credentials.Provider.Type != "OKTA" {
+		t.Errorf("Expected credentials to be decoded, received %+v", u.Credentials)
+	}
+
+	for _, id := range []string{"no-id", "bad-id", "missing"} {
+		if u, _, err := GetUser(creds, id); err == nil {
+			t.Errorf("Expected an error for %s, received %+v", id, u)
+		}
+	}
+}
+
+func TestProfileOf(t *testing.T) {
+	t.Parallel()
+
+	if login := ProfileOf(nil).Login(); login != "" {
+		t.Errorf("Expected empty login for nil user, received %s", login)
+	}
+}
@@ -0,0 +1,223 @@
+package tokenstore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/pbkdf2"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// fileKDF is the function deriving the key of the file from its
+	// passphrase.
+	fileKDF = "pbkdf2-sha256"
+
+	// fileAD is authenticated along with the tokens, so that files
+	// of other formats can't be mistaken for token files.
+	fileAD = "okta-admin tokens"
+)
+
+// DefaultIterations is the number of PBKDF2 iterations deriving the
+// key of new files, as recommended by OWASP for PBKDF2-HMAC-SHA256.
+const DefaultIterations = 600000
+
+// encryptedTokens is the content of a token file. Data is the JSON
+// object mapping organization URLs to their tokens, encrypted with
+// AES-256-GCM.
+type encryptedTokens struct {
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+// File stores API tokens in a file, encrypted with a key derived
+// from a passphrase. It is used where the system keyring isn't
+// available.
+type File struct {
+	Path string
+	// Iterations is the number of PBKDF2 iterations deriving the
+	// key when the file is written, DefaultIterations if it's 0.
+	Iterations int
+	// Passphrase returns the passphrase of the file. confirm is set
+	// when the file is created, so that a mistyped passphrase can
+	// be caught.
+	Passphrase func(confirm bool) (string, error)
+}
+
+var _ Store = &File{}
+
+func (f *File) String() string {
+	return f.Path
+}
+
+// Exists returns whether the file exists.
+func (f *File) Exists() bool {
+	_, err := os.Stat(f.Path)
+	return err == nil
+}
+
+// load returns the tokens of the file and its passphrase. If the
+// file doesn't exist, there are no tokens and the passphrase is
+// empty.
+func (f *File) load() (map[string]string, string, error) {
+	data, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return map[string]string{}, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	var enc encryptedTokens
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return nil, "", errors.New(fmt.Sprintf("%s is not a token file: %v", f.Path, err))
+	}
+	if enc.KDF != fileKDF || enc.Iterations < 1 {
+		return nil, "", errors.New(fmt.Sprintf("%s uses an unsupported key derivation function %s", f.Path, enc.KDF))
+	}
+
+	passphrase, err := f.Passphrase(false)
+	if err != nil {
+		return nil, "", err
+	}
+	aead, err := fileCipher(passphrase, enc.Salt, enc.Iterations)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(enc.Nonce) != aead.NonceSize() {
+		return nil, "", errors.New(fmt.Sprintf("%s is corrupted", f.Path))
+	}
+	plain, err := aead.Open(nil, enc.Nonce, enc.Data, []byte(fileAD))
+	if err != nil {
+		return nil, "", errors.New(fmt.Sprintf("the passphrase is wrong, or %s is corrupted", f.Path))
+	}
+	tokens := map[string]string{}
+	if err := json.Unmarshal(plain, &tokens); err != nil {
+		return nil, "", errors.New(fmt.Sprintf("%s is corrupted: %v", f.Path, err))
+	}
+	return tokens, passphrase, nil
+}
+
+// save encrypts the tokens with the passphrase, which is asked for
+// if it's empty, and writes them to the file. The file is deleted
+// if there are no tokens left.
+func (f *File) save(tokens map[string]string, passphrase string) error {
+	if len(tokens) == 0 {
+		return os.Remove(f.Path)
+	}
+	if passphrase == "" {
+		var err error
+		if passphrase, err = f.Passphrase(true); err != nil {
+			return err
+		}
+	}
+
+	plain, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	enc := encryptedTokens{KDF: fileKDF, Iterations: f.Iterations, Salt: make([]byte, 16)}
+	if enc.Iterations == 0 {
+		enc.Iterations = DefaultIterations
+	}
+	if _, err := rand.Read(enc.Salt); err != nil {
+		return err
+	}
+	aead, err := fileCipher(passphrase, enc.Salt, enc.Iterations)
+	if err != nil {
+		return err
+	}
+	enc.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(enc.Nonce); err != nil {
+		return err
+	}
+	enc.Data = aead.Seal(nil, enc.Nonce, plain, []byte(fileAD))
+
+	data, err := json.Marshal(&enc)
+	if err != nil {
+		return err
+	}
+	return writeFile(f.Path, data)
+}
+
+func (f *File) Get(orgUrl string) (string, error) {
+	if !f.Exists() {
+		return "", ErrNotStored
+	}
+	tokens, _, err := f.load()
+	if err != nil {
+		return "", err
+	}
+	token, ok := tokens[NormalizeOrgUrl(orgUrl)]
+	if !ok {
+		return "", ErrNotStored
+	}
+	return token, nil
+}
+
+func (f *File) Set(orgUrl, token string) error {
+	tokens, passphrase, err := f.load()
+	if err != nil {
+		return err
+	}
+	tokens[NormalizeOrgUrl(orgUrl)] = token
+	return f.save(tokens, passphrase)
+}
+
+func (f *File) Delete(orgUrl string) error {
+	if !f.Exists() {
+		return ErrNotStored
+	}
+	tokens, passphrase, err := f.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[NormalizeOrgUrl(orgUrl)]; !ok {
+		return ErrNotStored
+	}
+	delete(tokens, NormalizeOrgUrl(orgUrl))
+	return f.save(tokens, passphrase)
+}
+
+// fileCipher returns the AES-256-GCM cipher whose key is derived
+// from the passphrase.
+func fileCipher(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(passphrase), salt, iterations, 32, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// writeFile replaces the file with one containing the data, which
+// only the user can read. The data is written to a temporary file
+// first, so that the file isn't left truncated if writing fails.
+func writeFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	// ioutil.TempFile creates the file with mode 0600
+	tmp, err := ioutil.TempFile(dir, filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,81 @@
+package tokenstore
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFile(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "okta-admin-tokens")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	passphrase, confirmed := "mischief managed", false
+	f := &File{
+		Path:       filepath.Join(dir, "okta-admin", "tokens.enc"),
+		Iterations: 1000,
+		Passphrase: func(confirm bool) (string, error) {
+			confirmed = confirmed || confirm
+			return passphrase, nil
+		},
+	}
+
+	if _, err := f.Get("https://hogwarts.okta.com"); err != ErrNotStored {
+		t.Errorf("Expected no token to be stored, received %v", err)
+	}
+	if err := f.Set("https://hogwarts.okta.com/", "123abc"); err != nil {
+		t.Fatal(err)
+	}
+	if !confirmed {
+		t.Errorf("Expected the passphrase to be confirmed when the file is created")
+	}
+	if err := f.Set("https://durmstrang.okta.com", "456def"); err != nil {
+		t.Fatal(err)
+	}
+	if token, err := f.Get("https://HOGWARTS.okta.com"); err != nil || token != "123abc" {
+		t.Errorf("Expected the token to be read, received %q (error %v)", token, err)
+	}
+
+	// Tokens are encrypted, and only the user can read them
+	data, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "123abc") || strings.Contains(string(data), "hogwarts") {
+		t.Errorf("Expected tokens and organizations to be encrypted, file contains %s", data)
+	}
+	var enc encryptedTokens
+	if err := json.Unmarshal(data, &enc); err != nil || enc.KDF != fileKDF || enc.Iterations != 1000 {
+		t.Errorf("Expected the key derivation to be recorded, received %+v (error %v)", enc, err)
+	}
+	if info, err := os.Stat(f.Path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected file to be readable only by the user, received %v (error %v)", info.Mode(), err)
+	}
+
+	passphrase = "expelliarmus"
+	if _, err := f.Get("https://hogwarts.okta.com"); err == nil || !strings.Contains(err.Error(), "passphrase is wrong") {
+		t.Errorf("Expected a wrong passphrase to be reported, received %v", err)
+	}
+	passphrase = "mischief managed"
+
+	// The file is deleted along with the last token
+	for _, org := range []string{"https://hogwarts.okta.com", "https://durmstrang.okta.com"} {
+		if err := f.Delete(org); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if f.Exists() {
+		t.Errorf("Expected the file to be deleted")
+	}
+	if err := f.Delete("https://hogwarts.okta.com"); err != ErrNotStored {
+		t.Errorf("Expected deleting a missing token to fail with %v, received %v", ErrNotStored, err)
+	}
+}
@@ -0,0 +1,38 @@
+package tokenstore
+
+import "github.com/zalando/go-keyring"
+
+// keyringService is the service tokens are stored under in the
+// keyring. The organization URL is the user.
+const keyringService = "okta-admin"
+
+// Keyring stores API tokens in the system keyring: the Keychain on
+// macOS, the Credential Manager on Windows, and the Secret Service,
+// eg- GNOME Keyring or KWallet, on Linux and BSDs.
+type Keyring struct{}
+
+var _ Store = &Keyring{}
+
+func (k *Keyring) String() string {
+	return "the system keyring"
+}
+
+func (k *Keyring) Get(orgUrl string) (string, error) {
+	token, err := keyring.Get(keyringService, NormalizeOrgUrl(orgUrl))
+	if err == keyring.ErrNotFound {
+		return "", ErrNotStored
+	}
+	return token, err
+}
+
+func (k *Keyring) Set(orgUrl, token string) error {
+	return keyring.Set(keyringService, NormalizeOrgUrl(orgUrl), token)
+}
+
+func (k *Keyring) Delete(orgUrl string) error {
+	err := keyring.Delete(keyringService, NormalizeOrgUrl(orgUrl))
+	if err == keyring.ErrNotFound {
+		return ErrNotStored
+	}
+	return err
+}
@@ -0,0 +1,16 @@
+//go:build !((dragonfly && cgo) || (freebsd && cgo) || linux || netbsd || openbsd)
+// +build !dragonfly !cgo
+// +build !freebsd !cgo
+// +build !linux
+// +build !netbsd
+// +build !openbsd
+
+package tokenstore
+
+import "runtime"
+
+// Available returns whether the system keyring can be used, which
+// is the case on macOS and Windows only.
+func (k *Keyring) Available() bool {
+	return runtime.GOOS == "darwin" || runtime.GOOS == "windows"
+}
@@ -0,0 +1,31 @@
+package tokenstore
+
+import (
+	"github.com/zalando/go-keyring"
+	"testing"
+)
+
+func TestKeyring(t *testing.T) {
+	keyring.MockInit()
+
+	k := &Keyring{}
+	if _, err := k.Get("https://hogwarts.okta.com"); err != ErrNotStored {
+		t.Errorf("Expected no token to be stored, received %v", err)
+	}
+	if err := k.Set("https://hogwarts.okta.com/", "123abc"); err != nil {
+		t.Fatal(err)
+	}
+	if token, err := k.Get("https://HOGWARTS.okta.com"); err != nil || token != "123abc" {
+		t.Errorf("Expected the token to be read, received %q (error %v)", token, err)
+	}
+	if token, err := keyring.Get(keyringService, "https://hogwarts.okta.com"); err != nil || token != "123abc" {
+		t.Errorf("Expected the token to be stored under the normalized URL, received %q (error %v)", token, err)
+	}
+
+	if err := k.Delete("https://hogwarts.okta.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.Delete("https://hogwarts.okta.com"); err != ErrNotStored {
+		t.Errorf("Expected deleting a missing token to fail with %v, received %v", ErrNotStored, err)
+	}
+}
@@ -0,0 +1,33 @@
+//go:build (dragonfly && cgo) || (freebsd && cgo) || linux || netbsd || openbsd
+// +build dragonfly,cgo freebsd,cgo linux netbsd openbsd
+
+package tokenstore
+
+import "github.com/godbus/dbus/v5"
+
+// secretServiceName is the name the Secret Service owns on the
+// session bus.
+const secretServiceName = "org.freedesktop.secrets"
+
+// Available returns whether the Secret Service is running on the
+// session bus or can be started by it. It isn't, eg- over SSH.
+func (k *Keyring) Available() bool {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return false
+	}
+	var owned bool
+	if err := conn.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, secretServiceName).Store(&owned); err == nil && owned {
+		return true
+	}
+	var activatable []string
+	if err := conn.BusObject().Call("org.freedesktop.DBus.ListActivatableNames", 0).Store(&activatable); err != nil {
+		return false
+	}
+	for _, name := range activatable {
+		if name == secretServiceName {
+			return true
+		}
+	}
+	return false
+}
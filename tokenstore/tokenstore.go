@@ -0,0 +1,34 @@
+// Package tokenstore stores the API tokens of Okta organizations,
+// either in the system keyring or in a file encrypted with a
+// passphrase.
+package tokenstore
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrNotStored is returned by stores which don't store an API token
+// for the organization.
+var ErrNotStored = errors.New("no API token is stored for the organization")
+
+// Store stores API tokens per organization.
+type Store interface {
+	// Get returns the API token of the organization, or
+	// ErrNotStored if there's none.
+	Get(orgUrl string) (string, error)
+	// Set stores the API token of the organization, replacing the
+	// one stored before, if any.
+	Set(orgUrl, token string) error
+	// Delete deletes the API token of the organization, or returns
+	// ErrNotStored if there's none.
+	Delete(orgUrl string) error
+	// String describes where tokens are stored.
+	String() string
+}
+
+// NormalizeOrgUrl returns the URL tokens of the organization are
+// stored with, so that eg- a trailing slash doesn't matter.
+func NormalizeOrgUrl(orgUrl string) string {
+	return strings.TrimRight(strings.ToLower(orgUrl), "/")
+}